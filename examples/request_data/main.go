@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"github.com/fatih/color"
 	"github.com/skratchdot/open-golang/open"
@@ -22,22 +23,21 @@ import (
 
 type Report struct {
 	simconnect.RecvSimobjectDataByType
-	Title         [256]byte `name:"TITLE"`
-	Altitude      float64   `name:"INDICATED ALTITUDE" unit:"feet"` // PLANE ALTITUDE or PLANE ALT ABOVE GROUND
-	Latitude      float64   `name:"PLANE LATITUDE" unit:"degrees"`
-	Longitude     float64   `name:"PLANE LONGITUDE" unit:"degrees"`
-	Heading       float64   `name:"PLANE HEADING DEGREES TRUE" unit:"degrees"`
-	Airspeed      float64   `name:"AIRSPEED INDICATED" unit:"knot"`
-	AirspeedTrue  float64   `name:"AIRSPEED TRUE" unit:"knot"`
-	VerticalSpeed float64   `name:"VERTICAL SPEED" unit:"ft/min"`
-	Flaps         float64   `name:"TRAILING EDGE FLAPS LEFT ANGLE" unit:"degrees"`
-	Trim          float64   `name:"ELEVATOR TRIM PCT" unit:"percent"`
-	RudderTrim    float64   `name:"RUDDER TRIM PCT" unit:"percent"`
+	Title         string  `name:"TITLE"`
+	Altitude      float64 `name:"INDICATED ALTITUDE" unit:"feet"` // PLANE ALTITUDE or PLANE ALT ABOVE GROUND
+	Latitude      float64 `name:"PLANE LATITUDE" unit:"degrees"`
+	Longitude     float64 `name:"PLANE LONGITUDE" unit:"degrees"`
+	Heading       float64 `name:"PLANE HEADING DEGREES TRUE" unit:"degrees"`
+	Airspeed      float64 `name:"AIRSPEED INDICATED" unit:"knot"`
+	AirspeedTrue  float64 `name:"AIRSPEED TRUE" unit:"knot"`
+	VerticalSpeed float64 `name:"VERTICAL SPEED" unit:"ft/min"`
+	Flaps         float64 `name:"TRAILING EDGE FLAPS LEFT ANGLE" unit:"degrees"`
+	Trim          float64 `name:"ELEVATOR TRIM PCT" unit:"percent"`
+	RudderTrim    float64 `name:"RUDDER TRIM PCT" unit:"percent"`
 }
 
-func (r *Report) RequestData(s *simconnect.SimConnect) {
+func (r *Report) RequestData(s simconnect.Client, requestID simconnect.DWORD) {
 	defineID := s.GetDefineID(r)
-	requestID := defineID
 	s.RequestDataOnSimObjectType(requestID, defineID, 0, simconnect.SIMOBJECT_TYPE_USER)
 }
 
@@ -186,10 +186,17 @@ func getAircraftIdByName(name string, token string) int {
 		airCrafts[name] = id
 		return id
 	}
-	return -1
 }
 
+var simconnectAddress string
+var recordFile string
+var replayFile string
+
 func main() {
+	flag.StringVar(&simconnectAddress, "simconnect", "", "simconnect server address (host:port), uses SimConnect.dll when empty")
+	flag.StringVar(&recordFile, "record", "", "record the simconnect messages to this file")
+	flag.StringVar(&replayFile, "replay", "", "play back a file written with -record instead of connecting to the simulator")
+	flag.Parse()
 
 	var userInfo *UserInfo
 	token = loadToken(tokenFileName)
@@ -218,90 +225,85 @@ func main() {
 		}
 	}
 
-	s, err := simconnect.New("Request Data")
+	var s simconnect.Client
+	var err error
+	if replayFile != "" {
+		s, err = simconnect.OpenReplay(replayFile)
+	} else {
+		s, err = simconnect.Open("Request Data", simconnectAddress)
+	}
 	if err != nil {
 		panic(err)
 	}
+	if recordFile != "" {
+		recording, err := os.Create(recordFile)
+		if err != nil {
+			panic(err)
+		}
+		defer recording.Close()
+		if s, err = simconnect.NewRecorder(s, recording); err != nil {
+			panic(err)
+		}
+	}
 	fmt.Println("Connected to Flight Simulator!")
+	defer func() {
+		fmt.Println("close")
+		if err := s.Close(); err != nil {
+			panic(err)
+		}
+	}()
 
 	report := &Report{}
 	s.RegisterDataDefinition(report)
-	report.RequestData(s)
-	for {
-		ppData, r1, err := s.GetNextDispatch()
-
-		if r1 < 0 {
-			if uint32(r1) == simconnect.E_FAIL {
-				// skip error, means no new messages?
-				continue
-			} else {
-				panic(fmt.Errorf("GetNextDispatch error: %d %s", r1, err))
-			}
-		}
 
-		recvInfo := *(*simconnect.Recv)(ppData)
-		//fmt.Println(ppData, pcbData, recvInfo)
-
-		switch recvInfo.ID {
-		case simconnect.RECV_ID_EXCEPTION:
-			recvErr := *(*simconnect.RecvException)(ppData)
-			fmt.Printf("SIMCONNECT_RECV_ID_EXCEPTION %#v\n", recvErr)
-
-		case simconnect.RECV_ID_OPEN:
-			recvOpen := *(*simconnect.RecvOpen)(ppData)
-			fmt.Println("SIMCONNECT_RECV_ID_OPEN", fmt.Sprintf("%s", recvOpen.ApplicationName))
-			//spew.Dump(recvOpen)
-		case simconnect.RECV_ID_EVENT:
-			recvEvent := *(*simconnect.RecvEvent)(ppData)
-			fmt.Println("SIMCONNECT_RECV_ID_EVENT")
-			//spew.Dump(recvEvent)
-
-			switch recvEvent.EventID {
-			//case eventSimStartID:
-			//	fmt.Println("SimStart Event")
-			default:
-				fmt.Println("unknown SIMCONNECT_RECV_ID_EVENT", recvEvent.EventID)
-			}
+	d := simconnect.NewDispatcher(s)
+	d.PollInterval = 1000 * time.Millisecond
 
-		case simconnect.RECV_ID_SIMOBJECT_DATA_BYTYPE:
-			recvData := *(*simconnect.RecvSimobjectDataByType)(ppData)
-			fmt.Println("SIMCONNECT_RECV_SIMOBJECT_DATA_BYTYPE")
-
-			switch recvData.RequestID {
-			case s.DefineMap["Report"]:
-				report := (*Report)(ppData)
-				fmt.Printf("REPORT: %s: GPS: %.6f,%.6f Altitude: %.0f\n", report.Title, report.Latitude, report.Longitude, report.Altitude)
-				if report.Longitude > 0.1 || report.Latitude > 0.1 {
-					test := report.Title[:bytes.IndexByte(report.Title[:], 0)]
-					aircraftName := string(test)
-					aircraftId := getAircraftIdByName(aircraftName, token)
-					if aircraftId < 0 {
-						log.Fatal("aircraftId was not resolved")
-					}
-
-					req := &Request{
-						AircraftId: aircraftId,
-						Altitude:   int32(report.Altitude),
-						Latitude:   report.Latitude,
-						Longitude:  report.Longitude,
-						Heading:    float32(report.Heading),
-						Timestamp:  makeTimestamp(),
-					}
-					sendData(req, token)
-				}
-				report.RequestData(s)
+	d.Error = func(err *simconnect.ExceptionError) {
+		fmt.Println("simconnect exception:", err)
+	}
+
+	d.Handle(simconnect.RECV_ID_OPEN, func(msg interface{}) {
+		fmt.Println("SIMCONNECT_RECV_ID_OPEN", msg.(*simconnect.RecvOpen).ApplicationName)
+	})
+
+	d.Handle(simconnect.RECV_ID_EVENT, func(msg interface{}) {
+		fmt.Println("unknown SIMCONNECT_RECV_ID_EVENT", msg.(*simconnect.RecvEvent).EventID)
+	})
+
+	requestID := s.GetRequestID()
+	d.HandleRequest(requestID, func(msg interface{}) {
+		fmt.Println("SIMCONNECT_RECV_SIMOBJECT_DATA_BYTYPE")
+
+		report := msg.(*simconnect.SimobjectData).Value.(*Report)
+		fmt.Printf("REPORT: %s: GPS: %.6f,%.6f Altitude: %.0f\n", report.Title, report.Latitude, report.Longitude, report.Altitude)
+		if report.Longitude > 0.1 || report.Latitude > 0.1 {
+			aircraftId := getAircraftIdByName(report.Title, token)
+			if aircraftId < 0 {
+				log.Fatal("aircraftId was not resolved")
 			}
 
-		default:
-			fmt.Println("recvInfo.dwID unknown", recvInfo.ID)
+			req := &Request{
+				AircraftId: aircraftId,
+				Altitude:   int32(report.Altitude),
+				Latitude:   report.Latitude,
+				Longitude:  report.Longitude,
+				Heading:    float32(report.Heading),
+				Timestamp:  makeTimestamp(),
+			}
+			sendData(req, token)
 		}
+		report.RequestData(s, requestID)
+	})
 
-		time.Sleep(1000 * time.Millisecond)
-	}
-
-	fmt.Println("close")
+	d.HandleDefault(func(msg interface{}) {
+		if recv, ok := msg.(*simconnect.RecvUnknown); ok {
+			fmt.Println("recvInfo.dwID unknown", recv.ID)
+		}
+	})
 
-	if err = s.Close(); err != nil {
+	report.RequestData(s, requestID)
+	if err := d.Run(context.Background()); err != nil && err != simconnect.ErrReplayEnded {
 		panic(err)
 	}
 }
@@ -35,9 +35,8 @@ type Report struct {
 	RudderTrim    float64   `name:"RUDDER TRIM PCT" unit:"percent"`
 }
 
-func (r *Report) RequestData(s *simconnect.SimConnect) {
+func (r *Report) RequestData(s simconnect.Client, requestID simconnect.DWORD) {
 	defineID := s.GetDefineID(r)
-	requestID := defineID
 	s.RequestDataOnSimObjectType(requestID, defineID, 0, simconnect.SIMOBJECT_TYPE_USER)
 }
 
@@ -0,0 +1,196 @@
+// Package logging is a small structured logger with levels, fields and a
+// level per subsystem. a nil *Logger logs nothing, so packages can take one
+// as an optional field.
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is the severity of a record.
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+	LevelOff // disables a subsystem
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	case LevelOff:
+		return "off"
+	}
+	return fmt.Sprintf("Level(%d)", int(l))
+}
+
+// ParseLevel returns the level named by String.
+func ParseLevel(s string) (Level, error) {
+	for l := LevelDebug; l <= LevelOff; l++ {
+		if strings.EqualFold(s, l.String()) {
+			return l, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log level %q", s)
+}
+
+// Field is a key and value attached to a record.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// F returns a Field.
+func F(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Record is one log entry.
+type Record struct {
+	Time      time.Time
+	Level     Level
+	Subsystem string
+	Message   string
+	Fields    []Field
+}
+
+// Levels is the minimum level logged per subsystem, the "" entry applies to
+// the subsystems not listed and defaults to LevelInfo.
+type Levels map[string]Level
+
+// ParseLevels parses a comma separated list of levels and subsystem=level
+// pairs, e.g. "warn,vfrmap=info,websockets=debug". a level without a
+// subsystem sets the default.
+func ParseLevels(s string) (Levels, error) {
+	levels := Levels{}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		subsystem, name := "", part
+		if i := strings.IndexByte(part, '='); i >= 0 {
+			subsystem, name = strings.TrimSpace(part[:i]), strings.TrimSpace(part[i+1:])
+		}
+		l, err := ParseLevel(name)
+		if err != nil {
+			return nil, err
+		}
+		levels[subsystem] = l
+	}
+	return levels, nil
+}
+
+func (ls Levels) level(subsystem string) Level {
+	if l, ok := ls[subsystem]; ok {
+		return l
+	}
+	if l, ok := ls[""]; ok {
+		return l
+	}
+	return LevelInfo
+}
+
+// Logger writes records of one subsystem, with the fields added by With, to
+// a Sink. loggers derived with Named and With share the sink and levels.
+type Logger struct {
+	out       *output
+	subsystem string
+	fields    []Field
+}
+
+type output struct {
+	mu     sync.Mutex
+	sink   Sink
+	levels Levels
+}
+
+// New returns a logger writing to sink, a nil levels logs LevelInfo and up.
+func New(sink Sink, levels Levels) *Logger {
+	return &Logger{out: &output{sink: sink, levels: levels}}
+}
+
+// SetLevels replaces the levels of l and every logger derived from it.
+func (l *Logger) SetLevels(levels Levels) {
+	if l == nil {
+		return
+	}
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+	l.out.levels = levels
+}
+
+// Named returns a logger for subsystem.
+func (l *Logger) Named(subsystem string) *Logger {
+	if l == nil {
+		return nil
+	}
+	return &Logger{out: l.out, subsystem: subsystem, fields: l.fields}
+}
+
+// With returns a logger adding fields to every record.
+func (l *Logger) With(fields ...Field) *Logger {
+	if l == nil {
+		return nil
+	}
+	all := make([]Field, 0, len(l.fields)+len(fields))
+	all = append(all, l.fields...)
+	all = append(all, fields...)
+	return &Logger{out: l.out, subsystem: l.subsystem, fields: all}
+}
+
+// Enabled reports whether records of level are written, e.g. to skip
+// building expensive fields.
+func (l *Logger) Enabled(level Level) bool {
+	if l == nil {
+		return false
+	}
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+	return level >= l.out.levels.level(l.subsystem)
+}
+
+// Log writes a record if level is enabled for the subsystem. errors of the
+// sink are dropped.
+func (l *Logger) Log(level Level, msg string, fields ...Field) {
+	if l == nil || level >= LevelOff {
+		return
+	}
+
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+	if level < l.out.levels.level(l.subsystem) {
+		return
+	}
+
+	r := &Record{
+		Time:      time.Now(),
+		Level:     level,
+		Subsystem: l.subsystem,
+		Message:   msg,
+		Fields:    fields,
+	}
+	if len(l.fields) > 0 {
+		r.Fields = append(append([]Field{}, l.fields...), fields...)
+	}
+	l.out.sink.Write(r)
+}
+
+func (l *Logger) Debug(msg string, fields ...Field) { l.Log(LevelDebug, msg, fields...) }
+func (l *Logger) Info(msg string, fields ...Field)  { l.Log(LevelInfo, msg, fields...) }
+func (l *Logger) Warn(msg string, fields ...Field)  { l.Log(LevelWarn, msg, fields...) }
+func (l *Logger) Error(msg string, fields ...Field) { l.Log(LevelError, msg, fields...) }
@@ -0,0 +1,124 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sink writes records, Logger serializes the calls.
+type Sink interface {
+	Write(r *Record) error
+}
+
+// value returns what a field value is written as, errors and Stringers as
+// their text.
+func value(v interface{}) interface{} {
+	switch v := v.(type) {
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	}
+	return v
+}
+
+// TextSink writes one line per record:
+//
+//	15:04:05.000 INFO  websockets: new browser connection conn_id=1 remote_addr=127.0.0.1:50000
+type TextSink struct {
+	w   io.Writer
+	buf bytes.Buffer
+}
+
+func NewTextSink(w io.Writer) *TextSink {
+	return &TextSink{w: w}
+}
+
+func (s *TextSink) Write(r *Record) error {
+	b := &s.buf
+	b.Reset()
+
+	b.WriteString(r.Time.Format("15:04:05.000"))
+	fmt.Fprintf(b, " %-5s ", strings.ToUpper(r.Level.String()))
+	if r.Subsystem != "" {
+		b.WriteString(r.Subsystem)
+		b.WriteString(": ")
+	}
+	b.WriteString(r.Message)
+	for _, f := range r.Fields {
+		text := fmt.Sprintf("%+v", value(f.Value))
+		if text == "" || strings.ContainsAny(text, " \t\n\"=") {
+			text = strconv.Quote(text)
+		}
+		fmt.Fprintf(b, " %s=%s", f.Key, text)
+	}
+	b.WriteByte('\n')
+
+	_, err := s.w.Write(b.Bytes())
+	return err
+}
+
+// JSONSink writes one json object per record, with the time, level,
+// subsystem and msg keys followed by the fields.
+type JSONSink struct {
+	w   io.Writer
+	buf bytes.Buffer
+}
+
+func NewJSONSink(w io.Writer) *JSONSink {
+	return &JSONSink{w: w}
+}
+
+func (s *JSONSink) Write(r *Record) error {
+	b := &s.buf
+	b.Reset()
+
+	fmt.Fprintf(b, `{"time":%q,"level":%q`, r.Time.Format(time.RFC3339Nano), r.Level)
+	if r.Subsystem != "" {
+		b.WriteString(`,"subsystem":`)
+		s.marshal(r.Subsystem)
+	}
+	b.WriteString(`,"msg":`)
+	s.marshal(r.Message)
+	for _, f := range r.Fields {
+		b.WriteByte(',')
+		s.marshal(f.Key)
+		b.WriteByte(':')
+		s.marshal(value(f.Value))
+	}
+	b.WriteString("}\n")
+
+	_, err := s.w.Write(b.Bytes())
+	return err
+}
+
+// marshal appends v as json, values json can't encode as their %+v text.
+func (s *JSONSink) marshal(v interface{}) {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		buf, _ = json.Marshal(fmt.Sprintf("%+v", v))
+	}
+	s.buf.Write(buf)
+}
+
+// Multi writes every record to each of sinks and returns the first error.
+func Multi(sinks ...Sink) Sink {
+	return multiSink(sinks)
+}
+
+type multiSink []Sink
+
+func (m multiSink) Write(r *Record) error {
+	var first error
+	for _, s := range m {
+		if err := s.Write(r); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
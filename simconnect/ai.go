@@ -0,0 +1,90 @@
+package simconnect
+
+import "context"
+
+// AIObject is an object CreateAI can create: ParkedATCAircraft,
+// EnrouteATCAircraft, NonATCAircraft or SimulatedObject.
+type AIObject interface {
+	createAI(c Client, requestID DWORD) error
+}
+
+// ParkedATCAircraft is an ATC controlled aircraft parked at an airport, it
+// waits for a flight plan, see AISetAircraftFlightPlan.
+type ParkedATCAircraft struct {
+	Title      string // aircraft.cfg title, e.g. "Airbus A320 Neo Asobo"
+	TailNumber string // 11 chars at most
+	AirportID  string // ICAO, e.g. "KSEA"
+}
+
+// EnrouteATCAircraft is an ATC controlled aircraft flying the flight plan at
+// FlightPlanPath (a .pln file without extension), placed Position along it:
+// 0.0 is the start, 1.5 halfway between the second and third waypoint.
+type EnrouteATCAircraft struct {
+	Title          string
+	TailNumber     string
+	FlightNumber   int32
+	FlightPlanPath string
+	Position       float64
+	TouchAndGo     bool
+}
+
+// NonATCAircraft is an aircraft ATC does not know about, placed at Position.
+// it is driven with SetDataOnSimObject and events until released with
+// AIReleaseControl.
+type NonATCAircraft struct {
+	Title      string
+	TailNumber string
+	Position   DataInitPosition
+}
+
+// SimulatedObject is any other object, e.g. a ground vehicle or an animal,
+// placed at Position.
+type SimulatedObject struct {
+	Title    string
+	Position DataInitPosition
+}
+
+func (a ParkedATCAircraft) createAI(c Client, requestID DWORD) error {
+	return c.AICreateParkedATCAircraft(a.Title, a.TailNumber, a.AirportID, requestID)
+}
+
+func (a EnrouteATCAircraft) createAI(c Client, requestID DWORD) error {
+	return c.AICreateEnrouteATCAircraft(a.Title, a.TailNumber, a.FlightNumber, a.FlightPlanPath, a.Position, a.TouchAndGo, requestID)
+}
+
+func (a NonATCAircraft) createAI(c Client, requestID DWORD) error {
+	return c.AICreateNonATCAircraft(a.Title, a.TailNumber, a.Position, requestID)
+}
+
+func (a SimulatedObject) createAI(c Client, requestID DWORD) error {
+	return c.AICreateSimulatedObject(a.Title, a.Position, requestID)
+}
+
+// CreateAI creates o and waits for the object ID the simulator assigns to it,
+// which works with RequestDataOnSimObject, SetDataOnSimObject, SetData and
+// AIRemoveObject like any other object ID. the dispatcher has to be running.
+func (d *Dispatcher) CreateAI(ctx context.Context, o AIObject) (DWORD, error) {
+	requestID := d.client.GetRequestID()
+
+	result := make(chan DWORD, 1)
+	d.HandleRequest(requestID, func(msg interface{}) {
+		if m, ok := msg.(*RecvAssignedObjectID); ok {
+			select {
+			case result <- m.ObjectID:
+			default:
+			}
+		}
+	})
+	defer d.HandleRequest(requestID, nil)
+
+	if err := o.createAI(d.client, requestID); err != nil {
+		return 0, err
+	}
+
+	select {
+	case objectID := <-result:
+		return objectID, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
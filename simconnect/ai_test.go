@@ -0,0 +1,106 @@
+package simconnect_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/supersidor/msfs2020-go/simconnect"
+	"github.com/supersidor/msfs2020-go/simconnect/simtest"
+)
+
+// objectEvents passes the object IDs of a SYSTEM_EVENT_OBJECT_* event to a
+// channel.
+func objectEvents(t *testing.T, d *simconnect.Dispatcher, name simconnect.SystemEvent) chan simconnect.DWORD {
+	ch := make(chan simconnect.DWORD, 4)
+	_, err := d.HandleSystemEvent(name, func(msg interface{}) {
+		if e, ok := msg.(*simconnect.RecvEventObjectAddRemove); ok {
+			ch <- e.Data
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ch
+}
+
+func TestCreateAI(t *testing.T) {
+	_, s, d := start(t)
+	added := objectEvents(t, d, simconnect.SYSTEM_EVENT_OBJECT_ADDED)
+	removed := objectEvents(t, d, simconnect.SYSTEM_EVENT_OBJECT_REMOVED)
+	settle(t, d)
+
+	objectID, err := d.CreateAI(withTimeout(t), simconnect.NonATCAircraft{
+		Title:      "Cessna Skyhawk G1000 Asobo",
+		TailNumber: "N123",
+		Position:   simconnect.DataInitPosition{Latitude: 47.5, Longitude: 8.5, Altitude: 1500},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objectID < simtest.FirstAIObjectID {
+		t.Errorf("assigned object ID %d", objectID)
+	}
+	select {
+	case id := <-added:
+		if id != objectID {
+			t.Errorf("object %d added, want %d", id, objectID)
+		}
+	case <-time.After(timeout):
+		t.Fatal("no ObjectAdded event")
+	}
+
+	// the assigned ID works like any other object ID
+	var p position
+	if err := d.RequestOnce(withTimeout(t), &p, objectID); err != nil {
+		t.Fatal(err)
+	}
+	if p.Latitude != 47.5 || p.Longitude != 8.5 || p.Altitude != 1500 {
+		t.Errorf("created at %v %v %v, want 47.5 8.5 1500", p.Latitude, p.Longitude, p.Altitude)
+	}
+
+	if err := s.AIRemoveObject(objectID, s.GetRequestID()); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case id := <-removed:
+		if id != objectID {
+			t.Errorf("object %d removed, want %d", id, objectID)
+		}
+	case <-time.After(timeout):
+		t.Fatal("no ObjectRemoved event")
+	}
+}
+
+func TestAIFlightPlan(t *testing.T) {
+	srv, s, d := start(t)
+
+	objectID, err := d.CreateAI(withTimeout(t), simconnect.EnrouteATCAircraft{
+		Title:          "Airbus A320 Neo Asobo",
+		TailNumber:     "HB-JLT",
+		FlightNumber:   1,
+		FlightPlanPath: `flights\LSZHLSGG`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path := srv.FlightPlan(objectID); path != `flights\LSZHLSGG` {
+		t.Errorf("flight plan %q", path)
+	}
+
+	if err := s.AISetAircraftFlightPlan(objectID, `flights\LSGGLSZH`, s.GetRequestID()); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "the new flight plan", func() bool { return srv.FlightPlan(objectID) == `flights\LSGGLSZH` })
+
+	parked, err := d.CreateAI(withTimeout(t), simconnect.ParkedATCAircraft{
+		Title:      "Airbus A320 Neo Asobo",
+		TailNumber: "HB-JLS",
+		AirportID:  "LSZH",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parked == objectID {
+		t.Errorf("both aircraft are object %d", parked)
+	}
+}
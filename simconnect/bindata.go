@@ -1,3 +1,6 @@
+//go:build windows
+// +build windows
+
 // Code generated by go-bindata.
 // sources:
 // ../_vendor/MSFS-SDK/SimConnect SDK/lib/SimConnect.dll
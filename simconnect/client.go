@@ -0,0 +1,119 @@
+package simconnect
+
+import (
+	"unsafe"
+)
+
+// Client is the SimConnect API implemented by every transport: *SimConnect
+// loads SimConnect.dll (windows only) and *NetSimConnect speaks the network
+// protocol.
+type Client interface {
+	Close() error
+
+	GetDefineID(a interface{}) DWORD
+	GetRequestID() DWORD
+	GetEventID() DWORD
+	GetGroupID() DWORD
+	GetClientDataID() DWORD
+	RegisterDataDefinition(a interface{}) error
+
+	// SetData writes v, a struct registered with RegisterDataDefinition or a
+	// pointer to it, to the simobject objectID.
+	SetData(objectID DWORD, v interface{}) error
+
+	// SendEvent transmits the key event to the user aircraft, mapping it to a
+	// client event ID the first time it is sent. data is the optional event
+	// parameter, see the KEY_* functions.
+	SendEvent(event KeyEvent, data ...DWORD) error
+
+	// RegisterClientDataDefinition is RegisterDataDefinition for client data
+	// areas: every exported field is a datum placed after the previous one,
+	// an offset:"N" tag on the first one places them in the area.
+	RegisterClientDataDefinition(a interface{}) error
+
+	// WriteClientData writes v, a struct registered with
+	// RegisterClientDataDefinition or a pointer to it, to the client data area
+	// clientDataID.
+	WriteClientData(clientDataID DWORD, v interface{}) error
+
+	// RegisterFacilityDefinition adds the objects and fields of a, a pointer
+	// to a struct with facility tags, to its facility definition. see
+	// Dispatcher.RequestFacilityData.
+	RegisterFacilityDefinition(a interface{}) error
+
+	// SentCall returns the call that sent the packet sendID, the SendID of a
+	// RecvException, see NewExceptionError.
+	SentCall(sendID DWORD) (SentCall, bool)
+
+	AddToDataDefinition(defineID DWORD, name, unit string, dataType DWORD, epsilon float32, datumID DWORD) error
+	ClearDataDefinition(defineID DWORD) error
+	SubscribeToSystemEvent(eventID DWORD, eventName string) error
+	UnsubscribeFromSystemEvent(eventID DWORD) error
+	SetSystemEventState(eventID, state DWORD) error
+	RequestDataOnSimObjectType(requestID, defineID, radius, simobjectType DWORD) error
+	RequestDataOnSimObject(requestID, defineID, objectID DWORD, period Period, flags DataRequestFlag, origin, interval, limit DWORD) error
+	SetDataOnSimObject(defineID, simobjectType, flags, arrayCount, size DWORD, buf unsafe.Pointer) error
+	SubscribeToFacilities(facilityType, requestID DWORD) error
+	UnsubscribeToFacilities(facilityType DWORD) error
+	RequestFacilitiesList(facilityType, requestID DWORD) error
+	SubscribeToFacilitiesEX1(facilityType, newElemInRangeRequestID, oldElemOutRangeRequestID DWORD) error
+	UnsubscribeToFacilitiesEX1(facilityType DWORD, unsubscribeNewInRange, unsubscribeOldOutRange bool) error
+	RequestFacilitiesListEX1(facilityType, requestID DWORD) error
+	MapClientEventToSimEvent(eventID DWORD, eventName string) error
+	TransmitClientEvent(objectID, eventID, data, groupID, flags DWORD) error
+	MenuAddItem(menuItem string, menuEventID, Data DWORD) error
+	MenuDeleteItem(menuItem string, menuEventID, Data DWORD) error
+	AddClientEventToNotificationGroup(groupID, eventID DWORD) error
+	SetNotificationGroupPriority(groupID, priority DWORD) error
+	MapInputEventToClientEvent(groupID DWORD, inputDefinition string, downEventID, downValue, upEventID, upValue DWORD) error
+	RemoveInputEvent(groupID DWORD, inputDefinition string) error
+	ClearInputGroup(groupID DWORD) error
+	SetInputGroupPriority(groupID, priority DWORD) error
+	SetInputGroupState(groupID, state DWORD) error
+	MapClientDataNameToID(clientDataName string, clientDataID DWORD) error
+	CreateClientData(clientDataID, size, flags DWORD) error
+	AddToClientDataDefinition(defineID, offset, sizeOrType DWORD, epsilon float32, datumID DWORD) error
+	ClearClientDataDefinition(defineID DWORD) error
+	RequestClientData(clientDataID, requestID, defineID DWORD, period ClientDataPeriod, flags ClientDataRequestFlag, origin, interval, limit DWORD) error
+	SetClientData(clientDataID, defineID, flags, size DWORD, buf unsafe.Pointer) error
+	AddToFacilityDefinition(defineID DWORD, fieldName string) error
+	RequestFacilityData(defineID, requestID DWORD, icao, region string) error
+	AICreateParkedATCAircraft(containerTitle, tailNumber, airportID string, requestID DWORD) error
+	AICreateEnrouteATCAircraft(containerTitle, tailNumber string, flightNumber int32, flightPlanPath string, flightPlanPosition float64, touchAndGo bool, requestID DWORD) error
+	AICreateNonATCAircraft(containerTitle, tailNumber string, initPos DataInitPosition, requestID DWORD) error
+	AICreateSimulatedObject(containerTitle string, initPos DataInitPosition, requestID DWORD) error
+	AIReleaseControl(objectID, requestID DWORD) error
+	AIRemoveObject(objectID, requestID DWORD) error
+	AISetAircraftFlightPlan(objectID DWORD, flightPlanPath string, requestID DWORD) error
+	RequestSystemState(requestID DWORD, state string) error
+	FlightLoad(fileName string) error
+	FlightSave(fileName, title, description string, flags DWORD) error
+	FlightPlanLoad(fileName string) error
+	ShowText(textType DWORD, duration float64, eventID DWORD, text string) error
+
+	GetNextDispatch() (unsafe.Pointer, int32, error)
+
+	// NextDispatch returns an owned copy of the next received message, or nil
+	// when none is pending.
+	NextDispatch() ([]byte, error)
+
+	// Decode turns a message returned by NextDispatch into a go value of the
+	// concrete type for its RECV_ID.
+	Decode(buf []byte) (interface{}, error)
+}
+
+var _ Client = (*NetSimConnect)(nil)
+
+// Open connects to the simulator. an empty address loads SimConnect.dll and
+// talks to the simulator on this machine, otherwise the network protocol is
+// used to reach the SimConnect server at address ("host:port").
+func Open(name, address string) (Client, error) {
+	if address != "" {
+		s, err := Dial(name, address)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+	return openLocal(name)
+}
@@ -0,0 +1,262 @@
+package simconnect
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// clientDataDefinition describes how a struct registered with
+// RegisterClientDataDefinition maps to a client data definition.
+type clientDataDefinition struct {
+	typ    reflect.Type // struct type
+	header []int        // index of the embedded RecvClientData, nil if there is none
+	datums []clientDatum
+	ids    map[DWORD]int // datum ID to index in datums
+	size   int           // end of the last datum in the client data area
+}
+
+type clientDatum struct {
+	name       string // struct field name, for errors
+	offset     DWORD  // in the client data area
+	size       int    // bytes in a data block
+	sizeOrType DWORD  // AddToClientDataDefinition dwSizeOrType
+	epsilon    float32
+	datumID    DWORD
+	index      []int // struct field index, see reflect.Value.FieldByIndex
+}
+
+var recvClientDataType = reflect.TypeOf(RecvClientData{})
+
+// parseClientDataDefinition reads the offset, epsilon and datumid tags of a
+// struct. every exported field is a datum, laid out in the client data area
+// one after the other. the offset tag of the first datum places them in the
+// area, later offset tags have to match where the datum starts anyway:
+// SimConnect sends and reads data blocks packed in definition order, a gap or
+// a datum out of order would not line up with the area.
+//
+//   - int8, int16, int32, int64 (and their unsigned versions), float32 and
+//     float64 fields are typed datums, the epsilon tag applies to them.
+//   - arrays, e.g. [64]byte or [4]float64, are raw datums of their size.
+//   - an embedded RecvClientData receives the message header.
+//   - struct fields are groups, their fields are read as if they were part
+//     of the outer struct.
+//   - fields tagged offset:"-" and unexported fields are skipped.
+//
+// fields without a datumid tag get their position as datum ID.
+func parseClientDataDefinition(a interface{}) (*clientDataDefinition, error) {
+	t := reflect.TypeOf(a)
+	if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%T is not a pointer to a struct", a)
+	}
+	t = t.Elem()
+
+	def := &clientDataDefinition{typ: t, ids: map[DWORD]int{}}
+	if err := def.parseStruct(t, nil); err != nil {
+		return nil, err
+	}
+	if def.size > CLIENTDATA_MAX_SIZE {
+		return nil, fmt.Errorf("%s takes %d bytes, client data areas hold at most %d", t.Name(), def.size, CLIENTDATA_MAX_SIZE)
+	}
+	return def, nil
+}
+
+func (def *clientDataDefinition) parseStruct(t reflect.Type, parent []int) error {
+	for j := 0; j < t.NumField(); j++ {
+		field := t.Field(j)
+		index := append(append([]int{}, parent...), field.Index...)
+
+		if field.Type == recvClientDataType {
+			if def.header != nil {
+				return fmt.Errorf("%s second RecvClientData header", field.Name)
+			}
+			def.header = index
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+
+		offsetTag, hasOffset := field.Tag.Lookup("offset")
+		if offsetTag == "-" {
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Struct {
+			if err := def.parseStruct(field.Type, index); err != nil {
+				return err
+			}
+			continue
+		}
+
+		d := clientDatum{
+			name:    field.Name,
+			offset:  DWORD(def.size),
+			size:    binary.Size(reflect.Zero(field.Type).Interface()),
+			datumID: DWORD(len(def.datums)),
+			index:   index,
+		}
+
+		switch field.Type.Kind() {
+		case reflect.Int8, reflect.Uint8, reflect.Bool:
+			d.sizeOrType = CLIENT_DATA_TYPE_INT8
+		case reflect.Int16, reflect.Uint16:
+			d.sizeOrType = CLIENT_DATA_TYPE_INT16
+		case reflect.Int32, reflect.Uint32:
+			d.sizeOrType = CLIENT_DATA_TYPE_INT32
+		case reflect.Int64, reflect.Uint64:
+			d.sizeOrType = CLIENT_DATA_TYPE_INT64
+		case reflect.Float32:
+			d.sizeOrType = CLIENT_DATA_TYPE_FLOAT32
+		case reflect.Float64:
+			d.sizeOrType = CLIENT_DATA_TYPE_FLOAT64
+		case reflect.Array:
+			if d.size <= 0 {
+				return fmt.Errorf("%s %s has no fixed size", field.Name, field.Type)
+			}
+			d.sizeOrType = DWORD(d.size)
+		default:
+			return fmt.Errorf("%s client data type not implemented: %s", field.Name, field.Type)
+		}
+
+		if hasOffset {
+			offset, err := strconv.ParseUint(offsetTag, 10, 32)
+			if err != nil {
+				return fmt.Errorf("%s invalid offset tag: %s", field.Name, err)
+			}
+			if len(def.datums) > 0 && int(offset) != def.size {
+				return fmt.Errorf("%s offset %d, the previous datum ends at %d", field.Name, offset, def.size)
+			}
+			d.offset = DWORD(offset)
+		}
+
+		if tag, ok := field.Tag.Lookup("epsilon"); ok {
+			epsilon, err := strconv.ParseFloat(tag, 32)
+			if err != nil {
+				return fmt.Errorf("%s invalid epsilon tag: %s", field.Name, err)
+			}
+			d.epsilon = float32(epsilon)
+		}
+
+		if tag, ok := field.Tag.Lookup("datumid"); ok {
+			datumID, err := strconv.ParseUint(tag, 10, 32)
+			if err != nil {
+				return fmt.Errorf("%s invalid datumid tag: %s", field.Name, err)
+			}
+			d.datumID = DWORD(datumID)
+		}
+		if _, ok := def.ids[d.datumID]; ok {
+			return fmt.Errorf("%s datum ID %d is used twice", field.Name, d.datumID)
+		}
+		def.ids[d.datumID] = len(def.datums)
+
+		if end := int(d.offset) + d.size; end > def.size {
+			def.size = end
+		}
+		def.datums = append(def.datums, d)
+	}
+
+	return nil
+}
+
+// unmarshal copies the data block of a RECV_ID_CLIENT_DATA message into a new
+// struct and returns a pointer to it, along with the positions of the datums
+// that were set. the block holds the datums one after the other in definition
+// order, or the listed ones prefixed by their datum ID when tagged.
+func (def *clientDataDefinition) unmarshal(header RecvClientData, data []byte) (interface{}, []int, error) {
+	v := reflect.New(def.typ)
+	e := v.Elem()
+
+	if def.header != nil {
+		e.FieldByIndex(def.header).Set(reflect.ValueOf(header))
+	}
+
+	var set []int
+	r := &recvReader{buf: data}
+	read := func(i int) error {
+		d := def.datums[i]
+		buf := r.bytes(d.size)
+		if r.err != nil {
+			return fmt.Errorf("%s %s: %s", def.typ.Name(), d.name, r.err)
+		}
+		field := e.FieldByIndex(d.index)
+		if err := binary.Read(bytes.NewReader(buf), binary.LittleEndian, field.Addr().Interface()); err != nil {
+			return fmt.Errorf("%s %s: %s", def.typ.Name(), d.name, err)
+		}
+		set = append(set, i)
+		return nil
+	}
+
+	if ClientDataRequestFlag(header.Flags)&CLIENT_DATA_REQUEST_FLAG_TAGGED == 0 {
+		for i := range def.datums {
+			if err := read(i); err != nil {
+				return nil, nil, err
+			}
+		}
+		return v.Interface(), set, nil
+	}
+
+	for i := DWORD(0); i < header.DefineCount; i++ {
+		datumID := r.dword()
+		if r.err != nil {
+			return nil, nil, fmt.Errorf("%s: %s", def.typ.Name(), r.err)
+		}
+		j, ok := def.ids[datumID]
+		if !ok {
+			return nil, nil, fmt.Errorf("%s: unknown datum ID %d", def.typ.Name(), datumID)
+		}
+		if err := read(j); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return v.Interface(), set, nil
+}
+
+// marshal serializes v, the definition struct or a pointer to it, into a data
+// block for SetClientData.
+func (def *clientDataDefinition) marshal(v interface{}) ([]byte, error) {
+	e := reflect.Indirect(reflect.ValueOf(v))
+	if e.Type() != def.typ {
+		return nil, fmt.Errorf("can't marshal %T as %s", v, def.typ)
+	}
+	if len(def.datums) == 0 {
+		return nil, fmt.Errorf("%s has no datums", def.typ.Name())
+	}
+
+	var buf bytes.Buffer
+	for _, d := range def.datums {
+		if err := binary.Write(&buf, binary.LittleEndian, e.FieldByIndex(d.index).Interface()); err != nil {
+			return nil, fmt.Errorf("%s %s: %s", def.typ.Name(), d.name, err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// Update copies the fields received in the message into v, a pointer to the
+// struct registered for DefineID, see SimobjectData.Update.
+func (d *ClientData) Update(v interface{}) error {
+	if d.Value == nil {
+		return fmt.Errorf("defineID %d is not registered", d.DefineID)
+	}
+
+	dst := reflect.ValueOf(v)
+	src := reflect.ValueOf(d.Value)
+	if dst.Type() != src.Type() {
+		return fmt.Errorf("can't update %T with %T", v, d.Value)
+	}
+
+	dst = dst.Elem()
+	src = src.Elem()
+	if d.def.header != nil {
+		dst.FieldByIndex(d.def.header).Set(src.FieldByIndex(d.def.header))
+	}
+	for _, i := range d.set {
+		index := d.def.datums[i].index
+		dst.FieldByIndex(index).Set(src.FieldByIndex(index))
+	}
+	return nil
+}
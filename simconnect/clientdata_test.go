@@ -0,0 +1,123 @@
+package simconnect_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/supersidor/msfs2020-go/simconnect"
+)
+
+// gauge starts 8 bytes into its client data area.
+type gauge struct {
+	simconnect.RecvClientData
+	Heading float64 `offset:"8"`
+	Speed   int32
+	Mode    [4]byte `datumid:"7"`
+}
+
+func le(v interface{}) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, v)
+	return buf.Bytes()
+}
+
+func TestClientData(t *testing.T) {
+	srv, s, d := start(t)
+
+	clientDataID := s.GetClientDataID()
+	if err := s.MapClientDataNameToID("Gauge", clientDataID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CreateClientData(clientDataID, 24, simconnect.CREATE_CLIENT_DATA_FLAG_DEFAULT); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RegisterClientDataDefinition(&gauge{}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := s.WriteClientData(clientDataID, gauge{Heading: 270, Speed: 120, Mode: [4]byte{'H', 'D', 'G'}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := append(append(append(make([]byte, 8), le(270.0)...), le(int32(120))...), 'H', 'D', 'G', 0)
+	eventually(t, "the area to be written", func() bool { return bytes.Equal(srv.ClientData("Gauge"), want) })
+
+	received := make(chan *simconnect.ClientData, 8)
+	requestID := s.GetRequestID()
+	d.HandleRequest(requestID, func(msg interface{}) {
+		if data, ok := msg.(*simconnect.ClientData); ok {
+			received <- data
+		}
+	})
+	flags := simconnect.CLIENT_DATA_REQUEST_FLAG_CHANGED | simconnect.CLIENT_DATA_REQUEST_FLAG_TAGGED
+	err = s.RequestClientData(clientDataID, requestID, s.GetDefineID(&gauge{}), simconnect.CLIENT_DATA_PERIOD_ON_SET, flags, 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	settle(t, d)
+
+	next := func() *simconnect.ClientData {
+		t.Helper()
+		select {
+		case data := <-received:
+			return data
+		case <-withTimeout(t).Done():
+			t.Fatal("no client data")
+		}
+		return nil
+	}
+
+	// the first answer has every datum, the second only the speed
+	var g gauge
+	srv.SetClientData("Gauge", 0, want)
+	if err := next().Update(&g); err != nil {
+		t.Fatal(err)
+	}
+	if g.Heading != 270 || g.Speed != 120 || string(g.Mode[:3]) != "HDG" {
+		t.Errorf("got %+v", g)
+	}
+
+	srv.SetClientData("Gauge", 16, le(int32(130)))
+	data := next()
+	if data.DefineCount != 1 {
+		t.Errorf("%d datums changed, want 1", data.DefineCount)
+	}
+	if v := data.Value.(*gauge); v.Heading != 0 || v.Speed != 130 {
+		t.Errorf("Value is %+v, want only the speed set", v)
+	}
+	if err := data.Update(&g); err != nil {
+		t.Fatal(err)
+	}
+	if g.Heading != 270 || g.Speed != 130 || string(g.Mode[:3]) != "HDG" {
+		t.Errorf("updated to %+v", g)
+	}
+}
+
+func TestClientDataLayout(t *testing.T) {
+	_, s, _ := start(t)
+
+	// SimConnect packs data blocks in definition order, the datums have to
+	// follow one another in the area
+	var gap struct {
+		A int32
+		B int32 `offset:"8"`
+	}
+	if err := s.RegisterClientDataDefinition(&gap); err == nil {
+		t.Error("registered a definition with a gap")
+	}
+	var reversed struct {
+		A int32 `offset:"4"`
+		B int32 `offset:"0"`
+	}
+	if err := s.RegisterClientDataDefinition(&reversed); err == nil {
+		t.Error("registered a definition out of order")
+	}
+	var tagged struct {
+		A int32 `offset:"4"`
+		B int32 `offset:"8"`
+	}
+	if err := s.RegisterClientDataDefinition(&tagged); err != nil {
+		t.Error(err)
+	}
+}
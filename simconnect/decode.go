@@ -0,0 +1,352 @@
+package simconnect
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// recvReader reads little endian values out of a received message.
+type recvReader struct {
+	buf []byte
+	err error
+}
+
+func (r *recvReader) bytes(n int) []byte {
+	if r.err != nil {
+		return make([]byte, n)
+	}
+	if n < 0 || len(r.buf) < n {
+		r.err = fmt.Errorf("message too short, need %d bytes have %d", n, len(r.buf))
+		return make([]byte, n)
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+func (r *recvReader) dword() DWORD {
+	return DWORD(binary.LittleEndian.Uint32(r.bytes(4)))
+}
+
+func (r *recvReader) uint64() uint64 {
+	return binary.LittleEndian.Uint64(r.bytes(8))
+}
+
+func (r *recvReader) float32() float32 {
+	return math.Float32frombits(binary.LittleEndian.Uint32(r.bytes(4)))
+}
+
+func (r *recvReader) float64() float64 {
+	return math.Float64frombits(r.uint64())
+}
+
+func (r *recvReader) string(size int) string {
+	return cString(r.bytes(size))
+}
+
+// stringV reads a null terminated DATATYPE_STRINGV string.
+func (r *recvReader) stringV() string {
+	if r.err != nil {
+		return ""
+	}
+	i := bytes.IndexByte(r.buf, 0)
+	if i < 0 {
+		r.err = fmt.Errorf("unterminated string of %d bytes", len(r.buf))
+		return ""
+	}
+	return string(r.bytes(i + 1)[:i])
+}
+
+// rest returns a copy of the unread part of the message.
+func (r *recvReader) rest() []byte {
+	b := append([]byte(nil), r.buf...)
+	r.buf = nil
+	return b
+}
+
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
+
+func (r *recvReader) recv() Recv {
+	return Recv{
+		Size:    r.dword(),
+		Version: r.dword(),
+		ID:      r.dword(),
+	}
+}
+
+// Decode copies a message returned by NextDispatch into an owned go value of
+// the concrete type for its RECV_ID:
+//
+//	RECV_ID_OPEN                    *RecvOpen
+//	RECV_ID_QUIT                    *RecvQuit
+//	RECV_ID_EVENT                   *RecvEvent
+//	RECV_ID_EVENT_FILENAME          *RecvEventFilename
+//	RECV_ID_EVENT_FRAME             *RecvEventFrame
+//	RECV_ID_EVENT_OBJECT_ADDREMOVE  *RecvEventObjectAddRemove
+//	RECV_ID_EXCEPTION               *RecvException
+//	RECV_ID_SIMOBJECT_DATA(_BYTYPE) *SimobjectData
+//	RECV_ID_CLIENT_DATA             *ClientData
+//	RECV_ID_ASSIGNED_OBJECT_ID      *RecvAssignedObjectID
+//	RECV_ID_SYSTEM_STATE            *RecvSystemState
+//	RECV_ID_AIRPORT_LIST            *RecvFacilityAirportList
+//	RECV_ID_WAYPOINT_LIST           *RecvFacilityWaypointList
+//	RECV_ID_NDB_LIST                *RecvFacilityNDBList
+//	RECV_ID_VOR_LIST                *RecvFacilityVORList
+//	RECV_ID_FACILITY_DATA           *FacilityData
+//	RECV_ID_FACILITY_DATA_END       *RecvFacilityDataEnd
+//
+// everything else is returned as *RecvUnknown. simobject data of definitions
+// registered with RegisterDataDefinition is unmarshalled into a new struct,
+// see SimobjectData.Value, the same goes for client data and
+// RegisterClientDataDefinition.
+func (s *registry) Decode(buf []byte) (interface{}, error) {
+	r := &recvReader{buf: buf}
+	recv := r.recv()
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	var msg interface{}
+	switch recv.ID {
+	case RECV_ID_OPEN:
+		msg = &RecvOpen{
+			Recv:                    recv,
+			ApplicationName:         r.string(256),
+			ApplicationVersionMajor: r.dword(),
+			ApplicationVersionMinor: r.dword(),
+			ApplicationBuildMajor:   r.dword(),
+			ApplicationBuildMinor:   r.dword(),
+			SimConnectVersionMajor:  r.dword(),
+			SimConnectVersionMinor:  r.dword(),
+			SimConnectBuildMajor:    r.dword(),
+			SimConnectBuildMinor:    r.dword(),
+			Reserved1:               r.dword(),
+			Reserved2:               r.dword(),
+		}
+
+	case RECV_ID_QUIT:
+		msg = &RecvQuit{Recv: recv}
+
+	case RECV_ID_EVENT:
+		e := r.event(recv)
+		msg = &e
+
+	case RECV_ID_EVENT_FILENAME:
+		msg = &RecvEventFilename{
+			RecvEvent: r.event(recv),
+			FileName:  r.string(protocolStringPath),
+			Flags:     r.dword(),
+		}
+
+	case RECV_ID_EVENT_FRAME:
+		msg = &RecvEventFrame{
+			RecvEvent: r.event(recv),
+			FrameRate: r.float32(),
+			SimSpeed:  r.float32(),
+		}
+
+	case RECV_ID_EVENT_OBJECT_ADDREMOVE:
+		msg = &RecvEventObjectAddRemove{
+			RecvEvent: r.event(recv),
+			ObjType:   r.dword(),
+		}
+
+	case RECV_ID_EXCEPTION:
+		msg = &RecvException{
+			Recv:      recv,
+			Exception: r.dword(),
+			SendID:    r.dword(),
+			Index:     r.dword(),
+		}
+
+	case RECV_ID_SIMOBJECT_DATA, RECV_ID_SIMOBJECT_DATA_BYTYPE:
+		data := &SimobjectData{
+			RecvSimobjectData: RecvSimobjectData{
+				Recv:        recv,
+				RequestID:   r.dword(),
+				ObjectID:    r.dword(),
+				DefineID:    r.dword(),
+				Flags:       r.dword(),
+				EntryNumber: r.dword(),
+				OutOf:       r.dword(),
+				DefineCount: r.dword(),
+			},
+			Data: r.rest(),
+		}
+		if r.err != nil {
+			return nil, r.err
+		}
+
+		if def, ok := s.definition(data.DefineID); ok {
+			value, set, err := def.unmarshal(data.RecvSimobjectData, data.Data)
+			if err != nil {
+				return nil, err
+			}
+			data.Value = value
+			data.def = def
+			data.set = set
+		}
+		msg = data
+
+	case RECV_ID_CLIENT_DATA:
+		data := &ClientData{
+			RecvClientData: RecvClientData{RecvSimobjectData{
+				Recv:        recv,
+				RequestID:   r.dword(),
+				ObjectID:    r.dword(),
+				DefineID:    r.dword(),
+				Flags:       r.dword(),
+				EntryNumber: r.dword(),
+				OutOf:       r.dword(),
+				DefineCount: r.dword(),
+			}},
+			Data: r.rest(),
+		}
+		if r.err != nil {
+			return nil, r.err
+		}
+
+		if def, ok := s.clientDefinition(data.DefineID); ok {
+			value, set, err := def.unmarshal(data.RecvClientData, data.Data)
+			if err != nil {
+				return nil, err
+			}
+			data.Value = value
+			data.def = def
+			data.set = set
+		}
+		msg = data
+
+	case RECV_ID_ASSIGNED_OBJECT_ID:
+		msg = &RecvAssignedObjectID{
+			Recv:      recv,
+			RequestID: r.dword(),
+			ObjectID:  r.dword(),
+		}
+
+	case RECV_ID_SYSTEM_STATE:
+		msg = &RecvSystemState{
+			Recv:      recv,
+			RequestID: r.dword(),
+			Integer:   r.dword(),
+			Float:     r.float32(),
+			String:    r.string(protocolStringPath),
+		}
+
+	case RECV_ID_AIRPORT_LIST:
+		list := &RecvFacilityAirportList{RecvFacilityList: r.facilityList(recv)}
+		for i := DWORD(0); i < list.ArraySize && r.err == nil; i++ {
+			list.List = append(list.List, r.facilityAirport())
+		}
+		msg = list
+
+	case RECV_ID_WAYPOINT_LIST:
+		list := &RecvFacilityWaypointList{RecvFacilityList: r.facilityList(recv)}
+		for i := DWORD(0); i < list.ArraySize && r.err == nil; i++ {
+			list.List = append(list.List, r.facilityWaypoint())
+		}
+		msg = list
+
+	case RECV_ID_NDB_LIST:
+		list := &RecvFacilityNDBList{RecvFacilityList: r.facilityList(recv)}
+		for i := DWORD(0); i < list.ArraySize && r.err == nil; i++ {
+			list.List = append(list.List, r.facilityNDB())
+		}
+		msg = list
+
+	case RECV_ID_VOR_LIST:
+		list := &RecvFacilityVORList{RecvFacilityList: r.facilityList(recv)}
+		for i := DWORD(0); i < list.ArraySize && r.err == nil; i++ {
+			list.List = append(list.List, r.facilityVOR())
+		}
+		msg = list
+
+	case RECV_ID_FACILITY_DATA:
+		msg = &FacilityData{
+			RecvFacilityData: RecvFacilityData{
+				Recv:                  recv,
+				UserRequestID:         r.dword(),
+				UniqueRequestID:       r.dword(),
+				ParentUniqueRequestID: r.dword(),
+				Type:                  r.dword(),
+				IsListItem:            r.dword(),
+				ItemIndex:             r.dword(),
+				ListSize:              r.dword(),
+			},
+			Data: r.rest(),
+		}
+
+	case RECV_ID_FACILITY_DATA_END:
+		msg = &RecvFacilityDataEnd{Recv: recv, RequestID: r.dword()}
+
+	default:
+		msg = &RecvUnknown{Recv: recv, Data: r.rest()}
+	}
+
+	if r.err != nil {
+		return nil, fmt.Errorf("RECV_ID %d: %s", recv.ID, r.err)
+	}
+
+	return msg, nil
+}
+
+func (r *recvReader) event(recv Recv) RecvEvent {
+	return RecvEvent{
+		Recv:    recv,
+		GroupID: r.dword(),
+		EventID: r.dword(),
+		Data:    r.dword(),
+	}
+}
+
+func (r *recvReader) facilityList(recv Recv) RecvFacilityList {
+	return RecvFacilityList{
+		Recv:        recv,
+		RequestID:   r.dword(),
+		ArraySize:   r.dword(),
+		EntryNumber: r.dword(),
+		OutOf:       r.dword(),
+	}
+}
+
+func (r *recvReader) facilityAirport() DataFacilityAirport {
+	return DataFacilityAirport{
+		Icao:      r.string(9),
+		Latitude:  r.float64(),
+		Longitude: r.float64(),
+		Altitude:  r.float64(),
+	}
+}
+
+func (r *recvReader) facilityWaypoint() DataFacilityWaypoint {
+	return DataFacilityWaypoint{
+		DataFacilityAirport: r.facilityAirport(),
+		MagVar:              r.float32(),
+	}
+}
+
+func (r *recvReader) facilityNDB() DataFacilityNDB {
+	return DataFacilityNDB{
+		DataFacilityWaypoint: r.facilityWaypoint(),
+		Frequency:            r.dword(),
+	}
+}
+
+func (r *recvReader) facilityVOR() DataFacilityVOR {
+	return DataFacilityVOR{
+		DataFacilityNDB: r.facilityNDB(),
+		Flags:           r.dword(),
+		Localizer:       r.float32(),
+		GlideLat:        r.float64(),
+		GlideLon:        r.float64(),
+		GlideAlt:        r.float64(),
+		GlideSlopeAngle: r.float32(),
+	}
+}
@@ -0,0 +1,570 @@
+package simconnect
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// dataDefinition describes how a struct registered with RegisterDataDefinition
+// maps to the datums of its SimConnect data definition.
+type dataDefinition struct {
+	typ    reflect.Type // struct type
+	header []int        // index of the embedded RecvSimobjectData, nil if there is none
+	datums []datum
+	ids    map[DWORD]int // datum ID to index in datums
+}
+
+type datum struct {
+	name     string
+	unit     string
+	dataType DWORD
+	epsilon  float32
+	datumID  DWORD
+	index    []int // struct field index, see reflect.Value.FieldByIndex
+	elem     int   // array element of an indexed simvar, -1 for the whole field
+}
+
+var recvSimobjectDataType = reflect.TypeOf(RecvSimobjectData{})
+var recvSimobjectDataByTypeType = reflect.TypeOf(RecvSimobjectDataByType{})
+var stringVType = reflect.TypeOf(StringV(""))
+
+// parseDataDefinition reads the name, unit, epsilon and datumid tags of a
+// struct:
+//
+//   - an embedded RecvSimobjectData or RecvSimobjectDataByType receives the
+//     message header, it is optional and may be placed anywhere.
+//   - struct fields without a name tag are groups, their fields are read
+//     as if they were part of the outer struct.
+//   - fields tagged name:"-" and unexported fields are skipped.
+//   - an array field with "{n}" in its name is an indexed simvar, e.g.
+//     `name:"GENERAL ENG RPM:{n}"`, and expands to one datum per element
+//     starting at index 1.
+//
+// fields without a datumid tag get their position as datum ID, it identifies
+// them in DATA_REQUEST_FLAG_TAGGED messages.
+func parseDataDefinition(a interface{}) (*dataDefinition, error) {
+	t := reflect.TypeOf(a)
+	if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%T is not a pointer to a struct", a)
+	}
+	t = t.Elem()
+
+	def := &dataDefinition{typ: t, ids: map[DWORD]int{}}
+	if err := def.parseStruct(t, nil); err != nil {
+		return nil, err
+	}
+
+	for i, d := range def.datums {
+		if d.elem < 0 && t.FieldByIndex(d.index).Type.Kind() == reflect.Slice && i != len(def.datums)-1 {
+			return nil, fmt.Errorf("%s waypoint list has to be the last field", d.name)
+		}
+	}
+
+	return def, nil
+}
+
+func (def *dataDefinition) parseStruct(t reflect.Type, parent []int) error {
+	for j := 0; j < t.NumField(); j++ {
+		field := t.Field(j)
+		index := append(append([]int{}, parent...), field.Index...)
+
+		switch field.Type {
+		case recvSimobjectDataType, recvSimobjectDataByTypeType:
+			if def.header != nil {
+				return fmt.Errorf("%s second RecvSimobjectData header", field.Name)
+			}
+			def.header = index
+			if field.Type == recvSimobjectDataByTypeType {
+				def.header = append(index, 0)
+			}
+			continue
+		}
+
+		if field.PkgPath != "" && !field.Anonymous {
+			continue // unexported
+		}
+
+		nameTag, ok := field.Tag.Lookup("name")
+		if nameTag == "-" {
+			continue
+		}
+		if !ok || nameTag == "" {
+			if field.Type.Kind() == reflect.Struct {
+				if err := def.parseStruct(field.Type, index); err != nil {
+					return err
+				}
+				continue
+			}
+			return fmt.Errorf("%s name tag not found, tag it name:\"-\" to skip it", field.Name)
+		}
+		if field.PkgPath != "" {
+			continue // unexported embedded struct
+		}
+
+		if strings.Contains(nameTag, "{n}") {
+			if field.Type.Kind() != reflect.Array {
+				return fmt.Errorf("%s indexed simvar %s needs an array field", field.Name, nameTag)
+			}
+			for i := 0; i < field.Type.Len(); i++ {
+				name := strings.Replace(nameTag, "{n}", strconv.Itoa(i+1), -1)
+				if err := def.addDatum(field, field.Type.Elem(), name, index, i); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if err := def.addDatum(field, field.Type, nameTag, index, -1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (def *dataDefinition) addDatum(field reflect.StructField, typ reflect.Type, name string, index []int, elem int) error {
+	dataType, err := derefDataType(fieldTypeName(typ))
+	if err != nil {
+		return err
+	}
+
+	d := datum{
+		name:     name,
+		unit:     field.Tag.Get("unit"),
+		dataType: dataType,
+		datumID:  DWORD(len(def.datums)),
+		index:    index,
+		elem:     elem,
+	}
+
+	if tag, ok := field.Tag.Lookup("epsilon"); ok {
+		epsilon, err := strconv.ParseFloat(tag, 32)
+		if err != nil {
+			return fmt.Errorf("%s invalid epsilon tag: %s", field.Name, err)
+		}
+		d.epsilon = float32(epsilon)
+	}
+
+	if tag, ok := field.Tag.Lookup("datumid"); ok {
+		datumID, err := strconv.ParseUint(tag, 10, 32)
+		if err != nil {
+			return fmt.Errorf("%s invalid datumid tag: %s", field.Name, err)
+		}
+		d.datumID = DWORD(datumID)
+		if elem > 0 {
+			d.datumID += DWORD(elem) // elements of indexed simvars follow each other
+		}
+	}
+	if _, ok := def.ids[d.datumID]; ok {
+		return fmt.Errorf("%s datum ID %d is used twice", field.Name, d.datumID)
+	}
+	def.ids[d.datumID] = len(def.datums)
+
+	def.datums = append(def.datums, d)
+	return nil
+}
+
+// fieldTypeName returns the name derefDataType knows a field type by.
+func fieldTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return fmt.Sprintf("[%d]byte", t.Len())
+		}
+		return fmt.Sprintf("[%d]%s", t.Len(), fieldTypeName(t.Elem()))
+	case reflect.Struct, reflect.Slice:
+		return t.String()
+	case reflect.String:
+		if t == stringVType {
+			return t.String()
+		}
+	}
+	return t.Kind().String()
+}
+
+// field returns the value of datum d in the struct v.
+func (d *datum) field(v reflect.Value) reflect.Value {
+	f := v.FieldByIndex(d.index)
+	if d.elem >= 0 {
+		f = f.Index(d.elem)
+	}
+	return f
+}
+
+// unmarshal copies the data block of a RECV_ID_SIMOBJECT_DATA message into a
+// new struct and returns a pointer to it, along with the positions of the
+// datums that were set. tagged messages only contain the datums listed in
+// them, untagged ones contain every datum.
+func (def *dataDefinition) unmarshal(header RecvSimobjectData, data []byte) (interface{}, []int, error) {
+	v := reflect.New(def.typ)
+	e := v.Elem()
+
+	if def.header != nil {
+		e.FieldByIndex(def.header).Set(reflect.ValueOf(header))
+	}
+
+	var set []int
+	r := &recvReader{buf: data}
+	if DataRequestFlag(header.Flags)&DATA_REQUEST_FLAG_TAGGED == 0 {
+		for i, d := range def.datums {
+			if err := r.datum(d.field(e), d.dataType); err != nil {
+				return nil, nil, fmt.Errorf("%s %s: %s", def.typ.Name(), d.name, err)
+			}
+			set = append(set, i)
+		}
+		return v.Interface(), set, nil
+	}
+
+	for i := DWORD(0); i < header.DefineCount; i++ {
+		datumID := r.dword()
+		if r.err != nil {
+			return nil, nil, fmt.Errorf("%s: %s", def.typ.Name(), r.err)
+		}
+		j, ok := def.ids[datumID]
+		if !ok {
+			return nil, nil, fmt.Errorf("%s: unknown datum ID %d", def.typ.Name(), datumID)
+		}
+
+		d := def.datums[j]
+		if err := r.datum(d.field(e), d.dataType); err != nil {
+			return nil, nil, fmt.Errorf("%s %s: %s", def.typ.Name(), d.name, err)
+		}
+		set = append(set, j)
+	}
+
+	return v.Interface(), set, nil
+}
+
+// datum reads one value of dataType into field.
+func (r *recvReader) datum(field reflect.Value, dataType DWORD) error {
+	switch dataType {
+	case DATATYPE_STRINGV:
+		s := r.stringV()
+		if r.err != nil {
+			return r.err
+		}
+		if field.Kind() != reflect.String {
+			return fmt.Errorf("can't store string in %s", field.Type())
+		}
+		field.SetString(s)
+		return nil
+
+	case DATATYPE_INITPOSITION, DATATYPE_MARKERSTATE, DATATYPE_WAYPOINT, DATATYPE_LATLONALT, DATATYPE_XYZ:
+		if field.Kind() != reflect.Slice {
+			return r.structured(field, dataType)
+		}
+
+		// a list takes the rest of the data block
+		list := reflect.MakeSlice(field.Type(), 0, 0)
+		for len(r.buf) > 0 {
+			item := reflect.New(field.Type().Elem()).Elem()
+			if err := r.structured(item, dataType); err != nil {
+				return err
+			}
+			list = reflect.Append(list, item)
+		}
+		field.Set(list)
+		return nil
+	}
+
+	size, err := dataTypeSize(dataType)
+	if err != nil {
+		return err
+	}
+	buf := r.bytes(size)
+	if r.err != nil {
+		return r.err
+	}
+	br := &recvReader{buf: buf}
+
+	var i int64
+	var f float64
+	switch dataType {
+	case DATATYPE_INT32:
+		i = int64(int32(br.dword()))
+		f = float64(i)
+	case DATATYPE_INT64:
+		i = int64(br.uint64())
+		f = float64(i)
+	case DATATYPE_FLOAT32:
+		f = float64(br.float32())
+		i = int64(f)
+	case DATATYPE_FLOAT64:
+		f = br.float64()
+		i = int64(f)
+	default:
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(cString(buf))
+		case reflect.Array:
+			reflect.Copy(field, reflect.ValueOf(buf))
+		default:
+			return fmt.Errorf("can't store string in %s", field.Type())
+		}
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		field.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		field.SetUint(uint64(i))
+	case reflect.Float32, reflect.Float64:
+		field.SetFloat(f)
+	case reflect.Bool:
+		field.SetBool(f != 0 && !math.IsNaN(f))
+	default:
+		return fmt.Errorf("can't store number in %s", field.Type())
+	}
+
+	return nil
+}
+
+// structured reads one of the Data* structs of dataType into field.
+func (r *recvReader) structured(field reflect.Value, dataType DWORD) error {
+	var v interface{}
+	switch dataType {
+	case DATATYPE_INITPOSITION:
+		v = DataInitPosition{
+			Latitude:  r.float64(),
+			Longitude: r.float64(),
+			Altitude:  r.float64(),
+			Pitch:     r.float64(),
+			Bank:      r.float64(),
+			Heading:   r.float64(),
+			OnGround:  r.dword(),
+			Airspeed:  r.dword(),
+		}
+	case DATATYPE_MARKERSTATE:
+		v = DataMarkerState{
+			MarkerName:  r.string(64),
+			MarkerState: r.dword(),
+		}
+	case DATATYPE_WAYPOINT:
+		v = DataWaypoint{
+			Latitude:        r.float64(),
+			Longitude:       r.float64(),
+			Altitude:        r.float64(),
+			Flags:           r.dword(),
+			KtsSpeed:        r.float64(),
+			PercentThrottle: r.float64(),
+		}
+	case DATATYPE_LATLONALT:
+		v = DataLatLonAlt{
+			Latitude:  r.float64(),
+			Longitude: r.float64(),
+			Altitude:  r.float64(),
+		}
+	case DATATYPE_XYZ:
+		v = DataXYZ{
+			X: r.float64(),
+			Y: r.float64(),
+			Z: r.float64(),
+		}
+	}
+	if r.err != nil {
+		return r.err
+	}
+
+	if field.Type() != reflect.TypeOf(v) {
+		return fmt.Errorf("can't store %T in %s", v, field.Type())
+	}
+	field.Set(reflect.ValueOf(v))
+	return nil
+}
+
+// marshal serializes v, the definition struct or a pointer to it, into a data
+// block for SetDataOnSimObject. a waypoint list is written as arrayCount
+// units of unitSize bytes, every other definition as a single unit.
+func (def *dataDefinition) marshal(v interface{}) (data []byte, arrayCount, unitSize DWORD, err error) {
+	e := reflect.Indirect(reflect.ValueOf(v))
+	if e.Type() != def.typ {
+		return nil, 0, 0, fmt.Errorf("can't marshal %T as %s", v, def.typ)
+	}
+	if len(def.datums) == 0 {
+		return nil, 0, 0, fmt.Errorf("%s has no datums", def.typ.Name())
+	}
+
+	p := &packet{}
+	for _, d := range def.datums {
+		f := d.field(e)
+		if f.Kind() != reflect.Slice {
+			if err := p.datum(f, d.dataType); err != nil {
+				return nil, 0, 0, fmt.Errorf("%s %s: %s", def.typ.Name(), d.name, err)
+			}
+			continue
+		}
+
+		if len(def.datums) != 1 {
+			return nil, 0, 0, fmt.Errorf("%s %s: a waypoint list has to be the only datum to be set", def.typ.Name(), d.name)
+		}
+		if f.Len() == 0 {
+			return nil, 0, 0, fmt.Errorf("%s %s: empty waypoint list", def.typ.Name(), d.name)
+		}
+		for i := 0; i < f.Len(); i++ {
+			if err := p.datum(f.Index(i), d.dataType); err != nil {
+				return nil, 0, 0, fmt.Errorf("%s %s[%d]: %s", def.typ.Name(), d.name, i, err)
+			}
+		}
+		size, _ := dataTypeSize(d.dataType)
+		return p.buf, DWORD(f.Len()), DWORD(size), nil
+	}
+
+	return p.buf, 0, DWORD(len(p.buf)), nil
+}
+
+// datum appends field as a value of dataType, the way recvReader.datum reads
+// it.
+func (p *packet) datum(field reflect.Value, dataType DWORD) error {
+	if dataType == DATATYPE_STRINGV {
+		if field.Kind() != reflect.String {
+			return fmt.Errorf("can't write %s as string", field.Type())
+		}
+		p.bytes(append([]byte(field.String()), 0))
+		return nil
+	}
+
+	size, err := dataTypeSize(dataType)
+	if err != nil {
+		return err
+	}
+	start := len(p.buf)
+
+	switch dataType {
+	case DATATYPE_INITPOSITION, DATATYPE_MARKERSTATE, DATATYPE_WAYPOINT, DATATYPE_LATLONALT, DATATYPE_XYZ:
+		if err := p.structured(field.Interface(), dataType); err != nil {
+			return err
+		}
+
+	case DATATYPE_INT32, DATATYPE_INT64, DATATYPE_FLOAT32, DATATYPE_FLOAT64:
+		var i int64
+		var f float64
+		switch field.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			i = field.Int()
+			f = float64(i)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			i = int64(field.Uint())
+			f = float64(i)
+		case reflect.Float32, reflect.Float64:
+			f = field.Float()
+			i = int64(f)
+		case reflect.Bool:
+			if field.Bool() {
+				i, f = 1, 1
+			}
+		default:
+			return fmt.Errorf("can't write %s as number", field.Type())
+		}
+
+		switch dataType {
+		case DATATYPE_INT32:
+			if i < math.MinInt32 || i > math.MaxInt32 {
+				return fmt.Errorf("%d overflows INT32", i)
+			}
+			p.int32(int32(i))
+		case DATATYPE_INT64:
+			p.uint64(uint64(i))
+		case DATATYPE_FLOAT32:
+			p.float32(float32(f))
+		case DATATYPE_FLOAT64:
+			p.float64(f)
+		}
+
+	default:
+		switch field.Kind() {
+		case reflect.String:
+			if len(field.String()) >= size {
+				return fmt.Errorf("string of %d bytes doesn't fit in %d", len(field.String()), size)
+			}
+			p.string(field.String(), size)
+		case reflect.Array:
+			if field.Len() != size || field.Type().Elem().Kind() != reflect.Uint8 {
+				return fmt.Errorf("can't write %s as %d byte string", field.Type(), size)
+			}
+			b := make([]byte, size)
+			reflect.Copy(reflect.ValueOf(b), field)
+			p.bytes(b)
+		default:
+			return fmt.Errorf("can't write %s as string", field.Type())
+		}
+	}
+
+	if n := len(p.buf) - start; n != size {
+		return fmt.Errorf("wrote %d bytes, DATATYPE %d takes %d", n, dataType, size)
+	}
+	return nil
+}
+
+// structured appends one of the Data* structs.
+func (p *packet) structured(v interface{}, dataType DWORD) error {
+	switch v := v.(type) {
+	case DataInitPosition:
+		p.initPosition(v)
+	case DataMarkerState:
+		if len(v.MarkerName) >= 64 {
+			return fmt.Errorf("marker name of %d bytes doesn't fit in 64", len(v.MarkerName))
+		}
+		p.string(v.MarkerName, 64).
+			dword(v.MarkerState)
+	case DataWaypoint:
+		p.float64(v.Latitude).
+			float64(v.Longitude).
+			float64(v.Altitude).
+			dword(v.Flags).
+			float64(v.KtsSpeed).
+			float64(v.PercentThrottle)
+	case DataLatLonAlt:
+		p.float64(v.Latitude).
+			float64(v.Longitude).
+			float64(v.Altitude)
+	case DataXYZ:
+		p.float64(v.X).
+			float64(v.Y).
+			float64(v.Z)
+	default:
+		return fmt.Errorf("can't write %T as DATATYPE %d", v, dataType)
+	}
+	return nil
+}
+
+func (p *packet) initPosition(v DataInitPosition) *packet {
+	return p.float64(v.Latitude).
+		float64(v.Longitude).
+		float64(v.Altitude).
+		float64(v.Pitch).
+		float64(v.Bank).
+		float64(v.Heading).
+		dword(v.OnGround).
+		dword(v.Airspeed)
+}
+
+// Update copies the fields received in the message into v, a pointer to the
+// struct registered for DefineID. fields missing from a
+// DATA_REQUEST_FLAG_TAGGED message keep their value, so v holds the latest
+// state of a CHANGED|TAGGED subscription.
+func (d *SimobjectData) Update(v interface{}) error {
+	if d.Value == nil {
+		return fmt.Errorf("defineID %d is not registered", d.DefineID)
+	}
+
+	dst := reflect.ValueOf(v)
+	src := reflect.ValueOf(d.Value)
+	if dst.Type() != src.Type() {
+		return fmt.Errorf("can't update %T with %T", v, d.Value)
+	}
+
+	dst = dst.Elem()
+	src = src.Elem()
+	if d.def.header != nil {
+		dst.FieldByIndex(d.def.header).Set(src.FieldByIndex(d.def.header))
+	}
+	for _, i := range d.set {
+		datum := &d.def.datums[i]
+		datum.field(dst).Set(datum.field(src))
+	}
+	return nil
+}
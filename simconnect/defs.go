@@ -8,6 +8,14 @@ const E_FAIL uint32 = 0x80004005
 
 type DWORD uint32
 
+// boolDWORD returns a BOOL parameter, 1 for true.
+func boolDWORD(b bool) DWORD {
+	if b {
+		return 1
+	}
+	return 0
+}
+
 const UNUSED DWORD = 0xffffffff // special value to indicate unused event, ID
 const OBJECT_ID_USER DWORD = 0  // proxy value for User vehicle ObjectID
 
@@ -64,12 +72,22 @@ const GROUP_PRIORITY_STANDARD DWORD = 1900000000       // standard priority
 const GROUP_PRIORITY_DEFAULT DWORD = 2000000000        // default priority
 const GROUP_PRIORITY_LOWEST DWORD = 4000000000         // priorities lower than this will be ignored
 
+// SetInputGroupState states
+const STATE_OFF DWORD = 0
+const STATE_ON DWORD = 1
+
+// TransmitClientEvent flags
+const EVENT_FLAG_DEFAULT DWORD = 0x00000000
+const EVENT_FLAG_FAST_REPEAT_TIMER DWORD = 0x00000001   // set event repeat timer to simulate fast repeat
+const EVENT_FLAG_SLOW_REPEAT_TIMER DWORD = 0x00000002   // set event repeat timer to simulate slow repeat
+const EVENT_FLAG_GROUPID_IS_PRIORITY DWORD = 0x00000010 // interpret GroupID parameter as priority value
+
 func derefDataType(fieldType string) (DWORD, error) {
 	var dataType DWORD
 	switch fieldType {
-	case "int32":
+	case "int32", "uint32", "bool":
 		dataType = DATATYPE_INT32
-	case "int64":
+	case "int64", "uint64":
 		dataType = DATATYPE_INT64
 	case "float32":
 		dataType = DATATYPE_FLOAT32
@@ -83,10 +101,22 @@ func derefDataType(fieldType string) (DWORD, error) {
 		dataType = DATATYPE_STRING64
 	case "[128]byte":
 		dataType = DATATYPE_STRING128
-	case "[256]byte":
+	case "[256]byte", "string":
 		dataType = DATATYPE_STRING256
 	case "[260]byte":
 		dataType = DATATYPE_STRING260
+	case "simconnect.StringV":
+		dataType = DATATYPE_STRINGV
+	case "simconnect.DataInitPosition":
+		dataType = DATATYPE_INITPOSITION
+	case "simconnect.DataMarkerState":
+		dataType = DATATYPE_MARKERSTATE
+	case "simconnect.DataWaypoint", "[]simconnect.DataWaypoint":
+		dataType = DATATYPE_WAYPOINT
+	case "simconnect.DataLatLonAlt":
+		dataType = DATATYPE_LATLONALT
+	case "simconnect.DataXYZ":
+		dataType = DATATYPE_XYZ
 	default:
 		return 0, fmt.Errorf("DATATYPE not implemented: %s", fieldType)
 	}
@@ -94,6 +124,97 @@ func derefDataType(fieldType string) (DWORD, error) {
 	return dataType, nil
 }
 
+// dataTypeSize returns the number of bytes a DATATYPE_* takes in a data block.
+func dataTypeSize(dataType DWORD) (int, error) {
+	switch dataType {
+	case DATATYPE_INT32, DATATYPE_FLOAT32:
+		return 4, nil
+	case DATATYPE_INT64, DATATYPE_FLOAT64, DATATYPE_STRING8:
+		return 8, nil
+	case DATATYPE_STRING32:
+		return 32, nil
+	case DATATYPE_STRING64:
+		return 64, nil
+	case DATATYPE_STRING128:
+		return 128, nil
+	case DATATYPE_STRING256:
+		return 256, nil
+	case DATATYPE_STRING260:
+		return 260, nil
+	case DATATYPE_INITPOSITION:
+		return 56, nil
+	case DATATYPE_MARKERSTATE:
+		return 68, nil
+	case DATATYPE_WAYPOINT:
+		return 44, nil
+	case DATATYPE_LATLONALT, DATATYPE_XYZ:
+		return 24, nil
+	}
+	return 0, fmt.Errorf("DATATYPE size unknown: %d", dataType)
+}
+
+// StringV is a DATATYPE_STRINGV field, a null terminated string of variable
+// length.
+type StringV string
+
+// the Data* types below are the structured data types of SimConnect.h, they
+// can be used as fields of data definition structs.
+
+// DataInitPosition is DATATYPE_INITPOSITION, used with the "Initial Position"
+// simvar to place an object.
+type DataInitPosition struct {
+	Latitude  float64 // degrees
+	Longitude float64 // degrees
+	Altitude  float64 // feet
+	Pitch     float64 // degrees
+	Bank      float64 // degrees
+	Heading   float64 // degrees
+	OnGround  DWORD   // 1=force to be on the ground
+	Airspeed  DWORD   // knots
+}
+
+// DataMarkerState is DATATYPE_MARKERSTATE.
+type DataMarkerState struct {
+	MarkerName  string // 64 chars
+	MarkerState DWORD
+}
+
+const (
+	WAYPOINT_NONE                   DWORD = 0x00
+	WAYPOINT_SPEED_REQUESTED        DWORD = 0x04     // requested speed at waypoint is valid
+	WAYPOINT_THROTTLE_REQUESTED     DWORD = 0x08     // request a specific throttle percentage
+	WAYPOINT_COMPUTE_VERTICAL_SPEED DWORD = 0x10     // compute vertical to speed to reach waypoint altitude when crossing the waypoint
+	WAYPOINT_ALTITUDE_IS_AGL        DWORD = 0x20     // AltitudeIsAGL
+	WAYPOINT_ON_GROUND              DWORD = 0x100000 // place this waypoint on the ground
+	WAYPOINT_REVERSE                DWORD = 0x200000 // Back up to this waypoint. Only valid on first waypoint
+	WAYPOINT_WRAP_TO_FIRST          DWORD = 0x400000 // Wrap around back to first waypoint. Only valid on last waypoint.
+)
+
+// DataWaypoint is DATATYPE_WAYPOINT. a []DataWaypoint field holds a waypoint
+// list, e.g. "AI WAYPOINT LIST", and has to be the last field of its struct.
+type DataWaypoint struct {
+	Latitude        float64 // degrees
+	Longitude       float64 // degrees
+	Altitude        float64 // feet
+	Flags           DWORD   // WAYPOINT_*
+	KtsSpeed        float64 // knots
+	PercentThrottle float64
+}
+
+// DataLatLonAlt is DATATYPE_LATLONALT.
+type DataLatLonAlt struct {
+	Latitude  float64
+	Longitude float64
+	Altitude  float64
+}
+
+// DataXYZ is DATATYPE_XYZ.
+type DataXYZ struct {
+	X float64
+	Y float64
+	Z float64
+}
+
 const (
 	RECV_ID_NULL DWORD = iota
 	RECV_ID_EXCEPTION
@@ -122,8 +243,11 @@ const (
 	RECV_ID_EVENT_MULTIPLAYER_SESSION_ENDED
 	RECV_ID_EVENT_RACE_END
 	RECV_ID_EVENT_RACE_LAP
-
 	RECV_ID_PICK
+	RECV_ID_EVENT_EX1
+	RECV_ID_FACILITY_DATA
+	RECV_ID_FACILITY_DATA_END
+	RECV_ID_FACILITY_MINIMAL_LIST
 )
 
 const (
@@ -135,6 +259,66 @@ const (
 	SIMOBJECT_TYPE_GROUND
 )
 
+// Period is SIMCONNECT_PERIOD, how often RequestDataOnSimObject sends data.
+type Period DWORD
+
+const (
+	PERIOD_NEVER Period = iota
+	PERIOD_ONCE
+	PERIOD_VISUAL_FRAME
+	PERIOD_SIM_FRAME
+	PERIOD_SECOND
+)
+
+// DataRequestFlag is SIMCONNECT_DATA_REQUEST_FLAG.
+type DataRequestFlag DWORD
+
+const (
+	DATA_REQUEST_FLAG_DEFAULT DataRequestFlag = 0x00000000
+	DATA_REQUEST_FLAG_CHANGED DataRequestFlag = 0x00000001 // send requested data when value(s) change
+	DATA_REQUEST_FLAG_TAGGED  DataRequestFlag = 0x00000002 // send requested data in tagged format
+)
+
+// ClientDataPeriod is SIMCONNECT_CLIENT_DATA_PERIOD, how often
+// RequestClientData sends data.
+type ClientDataPeriod DWORD
+
+const (
+	CLIENT_DATA_PERIOD_NEVER ClientDataPeriod = iota
+	CLIENT_DATA_PERIOD_ONCE
+	CLIENT_DATA_PERIOD_VISUAL_FRAME
+	CLIENT_DATA_PERIOD_ON_SET
+	CLIENT_DATA_PERIOD_SECOND
+)
+
+// ClientDataRequestFlag is SIMCONNECT_CLIENT_DATA_REQUEST_FLAG.
+type ClientDataRequestFlag DWORD
+
+const (
+	CLIENT_DATA_REQUEST_FLAG_DEFAULT ClientDataRequestFlag = 0x00000000
+	CLIENT_DATA_REQUEST_FLAG_CHANGED ClientDataRequestFlag = 0x00000001 // send requested ClientData when value(s) change
+	CLIENT_DATA_REQUEST_FLAG_TAGGED  ClientDataRequestFlag = 0x00000002 // send requested ClientData in tagged format
+)
+
+const CLIENT_DATA_SET_FLAG_DEFAULT DWORD = 0x00000000
+const CLIENT_DATA_SET_FLAG_TAGGED DWORD = 0x00000001 // data is in tagged format
+
+const CREATE_CLIENT_DATA_FLAG_DEFAULT DWORD = 0x00000000
+const CREATE_CLIENT_DATA_FLAG_READ_ONLY DWORD = 0x00000001 // permit only ClientData creator to write into ClientData
+
+const CLIENTDATAOFFSET_AUTO DWORD = 0xffffffff // automatically compute offset of the ClientData variable
+const CLIENTDATA_MAX_SIZE = 8192               // maximum size of a client data area
+
+// AddToClientDataDefinition dwSizeOrType values besides a size in bytes
+const (
+	CLIENT_DATA_TYPE_INT8    DWORD = 0xffffffff // -1, 8-bit integer number
+	CLIENT_DATA_TYPE_INT16   DWORD = 0xfffffffe // -2, 16-bit integer number
+	CLIENT_DATA_TYPE_INT32   DWORD = 0xfffffffd // -3, 32-bit integer number
+	CLIENT_DATA_TYPE_INT64   DWORD = 0xfffffffc // -4, 64-bit integer number
+	CLIENT_DATA_TYPE_FLOAT32 DWORD = 0xfffffffb // -5, 32-bit floating-point number (float)
+	CLIENT_DATA_TYPE_FLOAT64 DWORD = 0xfffffffa // -6, 64-bit floating-point number (double)
+)
+
 const (
 	FACILITY_LIST_TYPE_AIRPORT DWORD = iota
 	FACILITY_LIST_TYPE_WAYPOINT
@@ -143,15 +327,57 @@ const (
 	FACILITY_LIST_TYPE_COUNT // invalid
 )
 
+// SIMCONNECT_FACILITY_DATA_TYPE, the type of the object in a
+// RECV_ID_FACILITY_DATA message.
+const (
+	FACILITY_DATA_AIRPORT DWORD = iota
+	FACILITY_DATA_RUNWAY
+	FACILITY_DATA_START
+	FACILITY_DATA_FREQUENCY
+	FACILITY_DATA_HELIPAD
+	FACILITY_DATA_APPROACH
+	FACILITY_DATA_APPROACH_TRANSITION
+	FACILITY_DATA_APPROACH_LEG
+	FACILITY_DATA_FINAL_APPROACH_LEG
+	FACILITY_DATA_MISSED_APPROACH_LEG
+	FACILITY_DATA_DEPARTURE
+	FACILITY_DATA_ARRIVAL
+	FACILITY_DATA_RUNWAY_TRANSITION
+	FACILITY_DATA_ENROUTE_TRANSITION
+	FACILITY_DATA_TAXI_POINT
+	FACILITY_DATA_TAXI_PARKING
+	FACILITY_DATA_TAXI_PATH
+	FACILITY_DATA_TAXI_NAME
+	FACILITY_DATA_JETWAY
+	FACILITY_DATA_VOR
+	FACILITY_DATA_NDB
+	FACILITY_DATA_WAYPOINT
+	FACILITY_DATA_ROUTE
+	FACILITY_DATA_PAVEMENT
+	FACILITY_DATA_APPROACH_LIGHTS
+	FACILITY_DATA_VASI
+)
+
+// DataFacilityVOR.Flags
+const (
+	RECV_ID_VOR_LIST_HAS_NAV_SIGNAL  DWORD = 0x00000001 // has VOR component
+	RECV_ID_VOR_LIST_HAS_LOCALIZER   DWORD = 0x00000002 // has localizer component
+	RECV_ID_VOR_LIST_HAS_GLIDE_SLOPE DWORD = 0x00000004 // has glide slope component
+	RECV_ID_VOR_LIST_HAS_DME         DWORD = 0x00000008 // station has DME
+)
+
 type Recv struct {
 	Size    DWORD
 	Version DWORD
 	ID      DWORD
 }
 
+// the Recv* types below are owned copies of the SIMCONNECT_RECV_* messages,
+// see Decode. fixed size strings are returned as go strings.
+
 type RecvOpen struct {
 	Recv
-	ApplicationName         [256]byte
+	ApplicationName         string
 	ApplicationVersionMajor DWORD
 	ApplicationVersionMinor DWORD
 	ApplicationBuildMajor   DWORD
@@ -164,6 +390,10 @@ type RecvOpen struct {
 	Reserved2               DWORD
 }
 
+type RecvQuit struct {
+	Recv
+}
+
 type RecvEvent struct {
 	Recv
 	//static const DWORD UNKNOWN_GROUP = DWORD_MAX;
@@ -172,14 +402,39 @@ type RecvEvent struct {
 	Data    DWORD // uEventID-dependent context
 }
 
+// RecvEventFilename is a system event carrying a file name, e.g.
+// SYSTEM_EVENT_FLIGHT_LOADED.
+type RecvEventFilename struct {
+	RecvEvent
+	FileName string // MAX_PATH chars
+	Flags    DWORD
+}
+
+// RecvEventFrame is SYSTEM_EVENT_FRAME or SYSTEM_EVENT_PAUSE_FRAME.
+type RecvEventFrame struct {
+	RecvEvent
+	FrameRate float32 // frames per second
+	SimSpeed  float32 // simulation rate, 1 is real time
+}
+
+// RecvEventObjectAddRemove is SYSTEM_EVENT_OBJECT_ADDED or
+// SYSTEM_EVENT_OBJECT_REMOVED, Data is the object ID.
+type RecvEventObjectAddRemove struct {
+	RecvEvent
+	ObjType DWORD // SIMOBJECT_TYPE_*
+}
+
+// RecvSimobjectData is the header of RECV_ID_SIMOBJECT_DATA. data definition
+// structs may embed it (or RecvSimobjectDataByType) to receive the header
+// fields along with the data.
 type RecvSimobjectData struct {
 	Recv
 	RequestID   DWORD
 	ObjectID    DWORD
 	DefineID    DWORD
 	Flags       DWORD // SIMCONNECT_DATA_REQUEST_FLAG
-	entrynumber DWORD // if multiple objects returned, this is number <entrynumber> out of <outof>.
-	outof       DWORD // note: starts with 1, not 0.
+	EntryNumber DWORD // if multiple objects returned, this is number <entrynumber> out of <outof>.
+	OutOf       DWORD // note: starts with 1, not 0.
 	DefineCount DWORD // data count (number of datums, *not* byte count)
 	//SIMCONNECT_DATAV(   dwData, dwDefineID, ); // data begins here, dwDefineCount data items
 }
@@ -188,15 +443,75 @@ type RecvSimobjectDataByType struct {
 	RecvSimobjectData
 }
 
+// SimobjectData is a decoded RECV_ID_SIMOBJECT_DATA or
+// RECV_ID_SIMOBJECT_DATA_BYTYPE message.
+type SimobjectData struct {
+	RecvSimobjectData
+
+	// Data is the raw data block following the header.
+	Data []byte
+
+	// Value is a new pointer to the struct registered for DefineID with
+	// RegisterDataDefinition, filled in from Data. nil if DefineID is unknown.
+	// with DATA_REQUEST_FLAG_TAGGED only the received fields are set, see
+	// Update.
+	Value interface{}
+
+	def *dataDefinition
+	set []int // positions in def.datums of the datums set in Value
+}
+
+// RecvClientData is the header of RECV_ID_CLIENT_DATA, laid out like
+// RecvSimobjectData. client data definition structs may embed it to receive
+// the header fields along with the data.
+type RecvClientData struct {
+	RecvSimobjectData
+}
+
+// ClientData is a decoded RECV_ID_CLIENT_DATA message.
+type ClientData struct {
+	RecvClientData
+
+	// Data is the raw data block following the header.
+	Data []byte
+
+	// Value is a new pointer to the struct registered for DefineID with
+	// RegisterClientDataDefinition, filled in from Data. nil if DefineID is
+	// unknown. with CLIENT_DATA_REQUEST_FLAG_TAGGED only the received fields
+	// are set, see Update.
+	Value interface{}
+
+	def *clientDataDefinition
+	set []int // positions in def.datums of the datums set in Value
+}
+
 type RecvException struct {
 	Recv
-	Exception DWORD // see SIMCONNECT_EXCEPTION
+	Exception DWORD // see EXCEPTION_*
 	//static const DWORD UNKNOWN_SENDID = 0;
-	SendID DWORD // see SimConnect_GetLastSentPacketID
+	SendID DWORD // see SimConnect_GetLastSentPacketID and Client.SentCall
 	//static const DWORD UNKNOWN_INDEX = DWORD_MAX;
 	Index DWORD // index of parameter that was source of error
 }
 
+// RecvAssignedObjectID answers the AICreate* calls with the object ID of the
+// new object.
+type RecvAssignedObjectID struct {
+	Recv
+	RequestID DWORD
+	ObjectID  DWORD
+}
+
+// RecvSystemState answers RequestSystemState, which of the fields is set
+// depends on the state, see the SYSTEM_STATE_* constants.
+type RecvSystemState struct {
+	Recv
+	RequestID DWORD
+	Integer   DWORD
+	Float     float32
+	String    string // MAX_PATH chars
+}
+
 type RecvFacilityList struct {
 	Recv
 	RequestID   DWORD
@@ -207,11 +522,11 @@ type RecvFacilityList struct {
 
 type RecvFacilityAirportList struct {
 	RecvFacilityList
-	List [1]DataFacilityAirport
+	List []DataFacilityAirport
 }
 
 type DataFacilityAirport struct {
-	Icao      [9]byte // ICAO of the object
+	Icao      string  // ICAO of the object
 	Latitude  float64 // degrees
 	Longitude float64 // degrees
 	Altitude  float64 // meters
@@ -219,10 +534,68 @@ type DataFacilityAirport struct {
 
 type RecvFacilityWaypointList struct {
 	RecvFacilityList
-	List [1]DataFacilityWaypoint
+	List []DataFacilityWaypoint
 }
 
 type DataFacilityWaypoint struct {
 	DataFacilityAirport
-	MagVar float64 // Magvar in degrees
+	MagVar float32 // Magvar in degrees
+}
+
+type RecvFacilityNDBList struct {
+	RecvFacilityList
+	List []DataFacilityNDB
+}
+
+type DataFacilityNDB struct {
+	DataFacilityWaypoint
+	Frequency DWORD // frequency in Hz
+}
+
+type RecvFacilityVORList struct {
+	RecvFacilityList
+	List []DataFacilityVOR
+}
+
+type DataFacilityVOR struct {
+	DataFacilityNDB
+	Flags           DWORD   // RECV_ID_VOR_LIST_HAS_* flags
+	Localizer       float32 // heading in degrees, with RECV_ID_VOR_LIST_HAS_LOCALIZER
+	GlideLat        float64 // glide slope location in degrees, with RECV_ID_VOR_LIST_HAS_GLIDE_SLOPE
+	GlideLon        float64
+	GlideAlt        float64 // meters
+	GlideSlopeAngle float32 // degrees
+}
+
+// RecvFacilityData is the header of RECV_ID_FACILITY_DATA, one object of the
+// facility tree requested with RequestFacilityData. children name their
+// parent with ParentUniqueRequestID.
+type RecvFacilityData struct {
+	Recv
+	UserRequestID         DWORD // RequestFacilityData requestID
+	UniqueRequestID       DWORD // identifies this object
+	ParentUniqueRequestID DWORD // UniqueRequestID of the parent, 0 for the root
+	Type                  DWORD // FACILITY_DATA_*
+	IsListItem            DWORD // 1 if the object is an item of a list
+	ItemIndex             DWORD // position in the list
+	ListSize              DWORD // length of the list
+}
+
+// FacilityData is a decoded RECV_ID_FACILITY_DATA message, see
+// Dispatcher.RequestFacilityData to put the objects together.
+type FacilityData struct {
+	RecvFacilityData
+	Data []byte // the fields of the object in definition order
+}
+
+// RecvFacilityDataEnd follows the last RECV_ID_FACILITY_DATA of a request.
+type RecvFacilityDataEnd struct {
+	Recv
+	RequestID DWORD
+}
+
+// RecvUnknown is returned by Decode for messages it has no type for.
+type RecvUnknown struct {
+	Recv
+	Data []byte // message body following the Recv header
 }
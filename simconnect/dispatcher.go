@@ -0,0 +1,295 @@
+package simconnect
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/supersidor/msfs2020-go/logging"
+)
+
+// Handler is called by a Dispatcher with a message returned by Decode.
+type Handler func(msg interface{})
+
+// recvMessage is implemented by every decoded message through the embedded Recv.
+type recvMessage interface {
+	header() Recv
+}
+
+// requestMessage is implemented by messages answering a request ID.
+type requestMessage interface {
+	requestID() DWORD
+}
+
+// eventMessage is implemented by RecvEvent and the messages embedding it.
+type eventMessage interface {
+	eventID() DWORD
+}
+
+func (r *Recv) header() Recv                     { return *r }
+func (r *RecvSimobjectData) requestID() DWORD    { return r.RequestID }
+func (r *RecvAssignedObjectID) requestID() DWORD { return r.RequestID }
+func (r *RecvSystemState) requestID() DWORD      { return r.RequestID }
+func (r *RecvFacilityList) requestID() DWORD     { return r.RequestID }
+func (r *RecvFacilityData) requestID() DWORD     { return r.UserRequestID }
+func (r *RecvFacilityDataEnd) requestID() DWORD  { return r.RequestID }
+func (r *RecvEvent) eventID() DWORD              { return r.EventID }
+
+// Dispatcher pumps the messages of a Client and calls the handlers registered
+// for them. a message goes to the first matching handler of:
+//
+//	exceptions    HandleException, by SIMCONNECT_EXCEPTION, then Error
+//	requests      HandleRequest, by request ID
+//	events        HandleEvent and HandleSystemEvent, by client event ID
+//	message types Handle, by RECV_ID
+//	everything    HandleDefault
+//
+// handlers run on the pump goroutine and must not block.
+type Dispatcher struct {
+	client Client
+
+	// PollInterval is how long the pump waits before looking for new messages
+	// once every pending one was handled. defaults to 10ms.
+	PollInterval time.Duration
+
+	// DecodeError is called with messages Decode failed on, the pump keeps
+	// running. nil ignores them.
+	DecodeError func(buf []byte, err error)
+
+	// Error is called with the exceptions no HandleException handler took, as
+	// *ExceptionError naming the call that caused them. nil passes them on to
+	// the Handle and HandleDefault handlers as *RecvException.
+	Error func(err *ExceptionError)
+
+	// Log gets the messages Decode failed on and, at LevelDebug, the ones no
+	// handler took. nil logs nothing.
+	Log *logging.Logger
+
+	mu         sync.Mutex
+	exceptions map[DWORD]Handler
+	requests   map[DWORD]Handler
+	events     map[DWORD]Handler
+	recvs      map[DWORD]Handler
+	fallback   Handler
+
+	start sync.Once
+	done  chan struct{}
+	err   error
+}
+
+func NewDispatcher(c Client) *Dispatcher {
+	return &Dispatcher{
+		client:     c,
+		exceptions: map[DWORD]Handler{},
+		requests:   map[DWORD]Handler{},
+		events:     map[DWORD]Handler{},
+		recvs:      map[DWORD]Handler{},
+		done:       make(chan struct{}),
+	}
+}
+
+// Client returns the connection the dispatcher reads from.
+func (d *Dispatcher) Client() Client {
+	return d.client
+}
+
+// HandleRequest calls h with every message answering requestID, e.g. the
+// *SimobjectData of RequestDataOnSimObject or the facility lists of
+// RequestFacilitiesList. a nil h removes the handler.
+func (d *Dispatcher) HandleRequest(requestID DWORD, h Handler) {
+	d.set(d.requests, requestID, h)
+}
+
+// HandleEvent calls h with every *RecvEvent for the client event eventID.
+// a nil h removes the handler.
+func (d *Dispatcher) HandleEvent(eventID DWORD, h Handler) {
+	d.set(d.events, eventID, h)
+}
+
+// HandleSystemEvent subscribes to the system event name (e.g.
+// SYSTEM_EVENT_SIM_START) and calls h with every message of it, a *RecvEvent
+// or the message named by the SYSTEM_EVENT_* constant. it returns the client
+// event ID used for the subscription, see SetSystemEventState and
+// UnhandleSystemEvent.
+func (d *Dispatcher) HandleSystemEvent(name SystemEvent, h Handler) (DWORD, error) {
+	eventID := d.client.GetEventID()
+	d.HandleEvent(eventID, h)
+
+	if err := d.client.SubscribeToSystemEvent(eventID, string(name)); err != nil {
+		d.HandleEvent(eventID, nil)
+		return 0, err
+	}
+	return eventID, nil
+}
+
+// UnhandleSystemEvent unsubscribes from the system event subscribed to with
+// HandleSystemEvent as eventID and removes its handler.
+func (d *Dispatcher) UnhandleSystemEvent(eventID DWORD) error {
+	d.HandleEvent(eventID, nil)
+	return d.client.UnsubscribeFromSystemEvent(eventID)
+}
+
+// HandleException calls h with every *RecvException of the given
+// SIMCONNECT_EXCEPTION. a nil h removes the handler.
+func (d *Dispatcher) HandleException(exception DWORD, h Handler) {
+	d.set(d.exceptions, exception, h)
+}
+
+// Handle calls h with every message of recvID (RECV_ID_*) that wasn't handled
+// by a more specific handler. a nil h removes the handler.
+func (d *Dispatcher) Handle(recvID DWORD, h Handler) {
+	d.set(d.recvs, recvID, h)
+}
+
+// HandleDefault calls h with every message no other handler took.
+func (d *Dispatcher) HandleDefault(h Handler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.fallback = h
+}
+
+func (d *Dispatcher) set(handlers map[DWORD]Handler, id DWORD, h Handler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if h == nil {
+		delete(handlers, id)
+	} else {
+		handlers[id] = h
+	}
+}
+
+func (d *Dispatcher) handler(msg interface{}) Handler {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if e, ok := msg.(*RecvException); ok {
+		if h, ok := d.exceptions[e.Exception]; ok {
+			return h
+		}
+		if d.Error != nil {
+			return func(msg interface{}) {
+				d.Error(NewExceptionError(d.client, e))
+			}
+		}
+	}
+	if m, ok := msg.(requestMessage); ok {
+		if h, ok := d.requests[m.requestID()]; ok {
+			return h
+		}
+	}
+	if m, ok := msg.(eventMessage); ok {
+		if h, ok := d.events[m.eventID()]; ok {
+			return h
+		}
+	}
+	if m, ok := msg.(recvMessage); ok {
+		if h, ok := d.recvs[m.header().ID]; ok {
+			return h
+		}
+	}
+	return d.fallback
+}
+
+// Dispatch calls the handler for one decoded message.
+func (d *Dispatcher) Dispatch(msg interface{}) {
+	h := d.handler(msg)
+	if h == nil {
+		if d.Log.Enabled(logging.LevelDebug) {
+			d.Log.Debug("unhandled message", messageFields(msg)...)
+		}
+		return
+	}
+	h(msg)
+}
+
+// messageFields returns the recv_id and, if the message has one, the
+// request_id or event_id of msg.
+func messageFields(msg interface{}) []logging.Field {
+	var fields []logging.Field
+	if m, ok := msg.(recvMessage); ok {
+		fields = append(fields, logging.F("recv_id", m.header().ID))
+	}
+	if m, ok := msg.(requestMessage); ok {
+		fields = append(fields, logging.F("request_id", m.requestID()))
+	}
+	if m, ok := msg.(eventMessage); ok {
+		fields = append(fields, logging.F("event_id", m.eventID()))
+	}
+	return fields
+}
+
+// Drain handles every pending message and returns once there are none left.
+func (d *Dispatcher) Drain() error {
+	for {
+		buf, err := d.client.NextDispatch()
+		if err != nil {
+			return err
+		}
+		if buf == nil {
+			return nil
+		}
+
+		msg, err := d.client.Decode(buf)
+		if err != nil {
+			fields := []logging.Field{logging.F("size", len(buf)), logging.F("error", err)}
+			if len(buf) >= 12 {
+				fields = append(fields, logging.F("recv_id", binary.LittleEndian.Uint32(buf[8:])))
+			}
+			d.Log.Warn("invalid message", fields...)
+			if d.DecodeError != nil {
+				d.DecodeError(buf, err)
+			}
+			continue
+		}
+		d.Dispatch(msg)
+	}
+}
+
+// Run pumps messages until ctx is cancelled or the connection fails. it
+// returns nil when stopped by ctx.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	interval := d.PollInterval
+	if interval == 0 {
+		interval = 10 * time.Millisecond
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.Drain(); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Start runs the pump in its own goroutine, see Run. Wait returns its result.
+// only the first call starts it.
+func (d *Dispatcher) Start(ctx context.Context) {
+	d.start.Do(func() {
+		go func() {
+			defer close(d.done)
+			d.err = d.Run(ctx)
+		}()
+	})
+}
+
+// Done is closed once the pump started with Start stopped, it stays open
+// until then.
+func (d *Dispatcher) Done() <-chan struct{} {
+	return d.done
+}
+
+// Wait blocks until the pump started with Start stopped and returns its error.
+func (d *Dispatcher) Wait() error {
+	<-d.done
+	return d.err
+}
@@ -0,0 +1,198 @@
+package simconnect
+
+// KeyEvent is a simulator key event, as documented in MSFS-SDK/Documentation
+// "Event IDs". SendEvent takes a KeyEvent so only the events below, or ones
+// named deliberately with CustomKeyEvent, compile. a string does not convert
+// to it, SendEvent("PAUS_TOGGLE") is a compile error.
+//
+// the events below are functions, e.g. SendEvent(KEY_PAUSE_TOGGLE()), so no
+// importer can change what they send.
+//
+// the comment of each event describes its parameter, events without one
+// ignore it.
+type KeyEvent struct {
+	name string
+}
+
+// CustomKeyEvent returns the key event name, for events not listed below.
+func CustomKeyEvent(name string) KeyEvent {
+	return KeyEvent{name: name}
+}
+
+// String returns the name of the event.
+func (e KeyEvent) String() string {
+	return e.name
+}
+
+// simulation
+func KEY_PAUSE_TOGGLE() KeyEvent    { return KeyEvent{"PAUSE_TOGGLE"} }
+func KEY_PAUSE_ON() KeyEvent        { return KeyEvent{"PAUSE_ON"} }
+func KEY_PAUSE_OFF() KeyEvent       { return KeyEvent{"PAUSE_OFF"} }
+func KEY_PAUSE_SET() KeyEvent       { return KeyEvent{"PAUSE_SET"} } // 1 pauses, 0 resumes
+func KEY_SIM_RATE_INCR() KeyEvent   { return KeyEvent{"SIM_RATE_INCR"} }
+func KEY_SIM_RATE_DECR() KeyEvent   { return KeyEvent{"SIM_RATE_DECR"} }
+func KEY_SLEW_TOGGLE() KeyEvent     { return KeyEvent{"SLEW_TOGGLE"} }
+func KEY_SLEW_ON() KeyEvent         { return KeyEvent{"SLEW_ON"} }
+func KEY_SLEW_OFF() KeyEvent        { return KeyEvent{"SLEW_OFF"} }
+func KEY_SLEW_SET() KeyEvent        { return KeyEvent{"SLEW_SET"} } // 1 enables slew mode, 0 disables it
+func KEY_SITUATION_RESET() KeyEvent { return KeyEvent{"SITUATION_RESET"} }
+func KEY_FREEZE_LATITUDE_LONGITUDE_TOGGLE() KeyEvent {
+	return KeyEvent{"FREEZE_LATITUDE_LONGITUDE_TOGGLE"}
+}
+func KEY_FREEZE_ALTITUDE_TOGGLE() KeyEvent { return KeyEvent{"FREEZE_ALTITUDE_TOGGLE"} }
+func KEY_FREEZE_ATTITUDE_TOGGLE() KeyEvent { return KeyEvent{"FREEZE_ATTITUDE_TOGGLE"} }
+func KEY_SOUND_TOGGLE() KeyEvent           { return KeyEvent{"SOUND_TOGGLE"} }
+
+// engines and electrics
+func KEY_THROTTLE_FULL() KeyEvent            { return KeyEvent{"THROTTLE_FULL"} }
+func KEY_THROTTLE_CUT() KeyEvent             { return KeyEvent{"THROTTLE_CUT"} }
+func KEY_THROTTLE_INCR() KeyEvent            { return KeyEvent{"THROTTLE_INCR"} }
+func KEY_THROTTLE_DECR() KeyEvent            { return KeyEvent{"THROTTLE_DECR"} }
+func KEY_THROTTLE_SET() KeyEvent             { return KeyEvent{"THROTTLE_SET"} }      // 0 to 16383
+func KEY_AXIS_THROTTLE_SET() KeyEvent        { return KeyEvent{"AXIS_THROTTLE_SET"} } // -16383 to 16383, as DWORD(int32)
+func KEY_MIXTURE_RICH() KeyEvent             { return KeyEvent{"MIXTURE_RICH"} }
+func KEY_MIXTURE_LEAN() KeyEvent             { return KeyEvent{"MIXTURE_LEAN"} }
+func KEY_MIXTURE_SET() KeyEvent              { return KeyEvent{"MIXTURE_SET"} }    // 0 to 16383
+func KEY_PROP_PITCH_SET() KeyEvent           { return KeyEvent{"PROP_PITCH_SET"} } // 0 to 16383
+func KEY_ENGINE_AUTO_START() KeyEvent        { return KeyEvent{"ENGINE_AUTO_START"} }
+func KEY_ENGINE_AUTO_SHUTDOWN() KeyEvent     { return KeyEvent{"ENGINE_AUTO_SHUTDOWN"} }
+func KEY_MAGNETO_OFF() KeyEvent              { return KeyEvent{"MAGNETO_OFF"} }
+func KEY_MAGNETO_BOTH() KeyEvent             { return KeyEvent{"MAGNETO_BOTH"} }
+func KEY_MAGNETO_START() KeyEvent            { return KeyEvent{"MAGNETO_START"} }
+func KEY_TOGGLE_STARTER1() KeyEvent          { return KeyEvent{"TOGGLE_STARTER1"} }
+func KEY_TOGGLE_MASTER_BATTERY() KeyEvent    { return KeyEvent{"TOGGLE_MASTER_BATTERY"} }
+func KEY_TOGGLE_MASTER_ALTERNATOR() KeyEvent { return KeyEvent{"TOGGLE_MASTER_ALTERNATOR"} }
+func KEY_MASTER_BATTERY_ON() KeyEvent        { return KeyEvent{"MASTER_BATTERY_ON"} }
+func KEY_MASTER_BATTERY_OFF() KeyEvent       { return KeyEvent{"MASTER_BATTERY_OFF"} }
+func KEY_TOGGLE_AVIONICS_MASTER() KeyEvent   { return KeyEvent{"TOGGLE_AVIONICS_MASTER"} }
+func KEY_AVIONICS_MASTER_SET() KeyEvent      { return KeyEvent{"AVIONICS_MASTER_SET"} } // 1 on, 0 off
+func KEY_PITOT_HEAT_TOGGLE() KeyEvent        { return KeyEvent{"PITOT_HEAT_TOGGLE"} }
+func KEY_ANTI_ICE_TOGGLE() KeyEvent          { return KeyEvent{"ANTI_ICE_TOGGLE"} }
+func KEY_REQUEST_FUEL_KEY() KeyEvent         { return KeyEvent{"REQUEST_FUEL_KEY"} }
+
+// flight controls
+func KEY_FLAPS_UP() KeyEvent                { return KeyEvent{"FLAPS_UP"} }
+func KEY_FLAPS_DOWN() KeyEvent              { return KeyEvent{"FLAPS_DOWN"} }
+func KEY_FLAPS_INCR() KeyEvent              { return KeyEvent{"FLAPS_INCR"} }
+func KEY_FLAPS_DECR() KeyEvent              { return KeyEvent{"FLAPS_DECR"} }
+func KEY_FLAPS_SET() KeyEvent               { return KeyEvent{"FLAPS_SET"} } // 0 to 16383
+func KEY_GEAR_TOGGLE() KeyEvent             { return KeyEvent{"GEAR_TOGGLE"} }
+func KEY_GEAR_UP() KeyEvent                 { return KeyEvent{"GEAR_UP"} }
+func KEY_GEAR_DOWN() KeyEvent               { return KeyEvent{"GEAR_DOWN"} }
+func KEY_GEAR_SET() KeyEvent                { return KeyEvent{"GEAR_SET"} } // 1 down, 0 up
+func KEY_PARKING_BRAKES() KeyEvent          { return KeyEvent{"PARKING_BRAKES"} }
+func KEY_BRAKES() KeyEvent                  { return KeyEvent{"BRAKES"} }
+func KEY_SPOILERS_TOGGLE() KeyEvent         { return KeyEvent{"SPOILERS_TOGGLE"} }
+func KEY_SPOILERS_ON() KeyEvent             { return KeyEvent{"SPOILERS_ON"} }
+func KEY_SPOILERS_OFF() KeyEvent            { return KeyEvent{"SPOILERS_OFF"} }
+func KEY_SPOILERS_SET() KeyEvent            { return KeyEvent{"SPOILERS_SET"} } // 0 to 16383
+func KEY_SPOILERS_ARM_TOGGLE() KeyEvent     { return KeyEvent{"SPOILERS_ARM_TOGGLE"} }
+func KEY_ELEV_TRIM_UP() KeyEvent            { return KeyEvent{"ELEV_TRIM_UP"} }
+func KEY_ELEV_TRIM_DN() KeyEvent            { return KeyEvent{"ELEV_TRIM_DN"} }
+func KEY_ELEVATOR_TRIM_SET() KeyEvent       { return KeyEvent{"ELEVATOR_TRIM_SET"} } // -16383 to 16383, as DWORD(int32)
+func KEY_AILERON_TRIM_LEFT() KeyEvent       { return KeyEvent{"AILERON_TRIM_LEFT"} }
+func KEY_AILERON_TRIM_RIGHT() KeyEvent      { return KeyEvent{"AILERON_TRIM_RIGHT"} }
+func KEY_RUDDER_TRIM_LEFT() KeyEvent        { return KeyEvent{"RUDDER_TRIM_LEFT"} }
+func KEY_RUDDER_TRIM_RIGHT() KeyEvent       { return KeyEvent{"RUDDER_TRIM_RIGHT"} }
+func KEY_RUDDER_TRIM_SET() KeyEvent         { return KeyEvent{"RUDDER_TRIM_SET"} }   // -16383 to 16383, as DWORD(int32)
+func KEY_AXIS_ELEVATOR_SET() KeyEvent       { return KeyEvent{"AXIS_ELEVATOR_SET"} } // -16383 to 16383, as DWORD(int32)
+func KEY_AXIS_AILERONS_SET() KeyEvent       { return KeyEvent{"AXIS_AILERONS_SET"} } // -16383 to 16383, as DWORD(int32)
+func KEY_AXIS_RUDDER_SET() KeyEvent         { return KeyEvent{"AXIS_RUDDER_SET"} }   // -16383 to 16383, as DWORD(int32)
+func KEY_CENTER_AILER_RUDDER() KeyEvent     { return KeyEvent{"CENTER_AILER_RUDDER"} }
+func KEY_TOGGLE_PUSHBACK() KeyEvent         { return KeyEvent{"TOGGLE_PUSHBACK"} }
+func KEY_TOGGLE_JETWAY() KeyEvent           { return KeyEvent{"TOGGLE_JETWAY"} }
+func KEY_SMOKE_TOGGLE() KeyEvent            { return KeyEvent{"SMOKE_TOGGLE"} }
+func KEY_TOGGLE_TAIL_HOOK_HANDLE() KeyEvent { return KeyEvent{"TOGGLE_TAIL_HOOK_HANDLE"} }
+func KEY_TOGGLE_WATER_RUDDER() KeyEvent     { return KeyEvent{"TOGGLE_WATER_RUDDER"} }
+func KEY_TOGGLE_AIRCRAFT_EXIT() KeyEvent    { return KeyEvent{"TOGGLE_AIRCRAFT_EXIT"} } // exit number, starting at 1
+
+// autopilot
+func KEY_AP_MASTER() KeyEvent                  { return KeyEvent{"AP_MASTER"} }
+func KEY_AUTOPILOT_ON() KeyEvent               { return KeyEvent{"AUTOPILOT_ON"} }
+func KEY_AUTOPILOT_OFF() KeyEvent              { return KeyEvent{"AUTOPILOT_OFF"} }
+func KEY_AP_WING_LEVELER() KeyEvent            { return KeyEvent{"AP_WING_LEVELER"} }
+func KEY_YAW_DAMPER_TOGGLE() KeyEvent          { return KeyEvent{"YAW_DAMPER_TOGGLE"} }
+func KEY_AP_PANEL_HEADING_HOLD() KeyEvent      { return KeyEvent{"AP_PANEL_HEADING_HOLD"} }
+func KEY_AP_HDG_HOLD_ON() KeyEvent             { return KeyEvent{"AP_HDG_HOLD_ON"} }
+func KEY_AP_HDG_HOLD_OFF() KeyEvent            { return KeyEvent{"AP_HDG_HOLD_OFF"} }
+func KEY_HEADING_BUG_SET() KeyEvent            { return KeyEvent{"HEADING_BUG_SET"} } // degrees
+func KEY_HEADING_BUG_INC() KeyEvent            { return KeyEvent{"HEADING_BUG_INC"} }
+func KEY_HEADING_BUG_DEC() KeyEvent            { return KeyEvent{"HEADING_BUG_DEC"} }
+func KEY_AP_PANEL_ALTITUDE_HOLD() KeyEvent     { return KeyEvent{"AP_PANEL_ALTITUDE_HOLD"} }
+func KEY_AP_ALT_HOLD_ON() KeyEvent             { return KeyEvent{"AP_ALT_HOLD_ON"} }
+func KEY_AP_ALT_HOLD_OFF() KeyEvent            { return KeyEvent{"AP_ALT_HOLD_OFF"} }
+func KEY_AP_ALT_VAR_SET_ENGLISH() KeyEvent     { return KeyEvent{"AP_ALT_VAR_SET_ENGLISH"} } // feet
+func KEY_AP_ALT_VAR_SET_METRIC() KeyEvent      { return KeyEvent{"AP_ALT_VAR_SET_METRIC"} }  // meters
+func KEY_AP_ALT_VAR_INC() KeyEvent             { return KeyEvent{"AP_ALT_VAR_INC"} }
+func KEY_AP_ALT_VAR_DEC() KeyEvent             { return KeyEvent{"AP_ALT_VAR_DEC"} }
+func KEY_AP_PANEL_VS_HOLD() KeyEvent           { return KeyEvent{"AP_PANEL_VS_HOLD"} }
+func KEY_AP_VS_VAR_SET_ENGLISH() KeyEvent      { return KeyEvent{"AP_VS_VAR_SET_ENGLISH"} } // feet per minute, as DWORD(int32)
+func KEY_AP_VS_VAR_INC() KeyEvent              { return KeyEvent{"AP_VS_VAR_INC"} }
+func KEY_AP_VS_VAR_DEC() KeyEvent              { return KeyEvent{"AP_VS_VAR_DEC"} }
+func KEY_AP_FLIGHT_LEVEL_CHANGE() KeyEvent     { return KeyEvent{"AP_FLIGHT_LEVEL_CHANGE"} }
+func KEY_AP_PANEL_SPEED_HOLD() KeyEvent        { return KeyEvent{"AP_PANEL_SPEED_HOLD"} }
+func KEY_AP_SPD_VAR_SET() KeyEvent             { return KeyEvent{"AP_SPD_VAR_SET"} } // knots
+func KEY_AP_SPD_VAR_INC() KeyEvent             { return KeyEvent{"AP_SPD_VAR_INC"} }
+func KEY_AP_SPD_VAR_DEC() KeyEvent             { return KeyEvent{"AP_SPD_VAR_DEC"} }
+func KEY_AUTO_THROTTLE_ARM() KeyEvent          { return KeyEvent{"AUTO_THROTTLE_ARM"} }
+func KEY_AP_NAV1_HOLD() KeyEvent               { return KeyEvent{"AP_NAV1_HOLD"} }
+func KEY_AP_NAV1_HOLD_ON() KeyEvent            { return KeyEvent{"AP_NAV1_HOLD_ON"} }
+func KEY_AP_NAV1_HOLD_OFF() KeyEvent           { return KeyEvent{"AP_NAV1_HOLD_OFF"} }
+func KEY_AP_APR_HOLD() KeyEvent                { return KeyEvent{"AP_APR_HOLD"} }
+func KEY_AP_APR_HOLD_ON() KeyEvent             { return KeyEvent{"AP_APR_HOLD_ON"} }
+func KEY_AP_APR_HOLD_OFF() KeyEvent            { return KeyEvent{"AP_APR_HOLD_OFF"} }
+func KEY_AP_LOC_HOLD() KeyEvent                { return KeyEvent{"AP_LOC_HOLD"} }
+func KEY_AP_BC_HOLD() KeyEvent                 { return KeyEvent{"AP_BC_HOLD"} }
+func KEY_AP_ATT_HOLD() KeyEvent                { return KeyEvent{"AP_ATT_HOLD"} }
+func KEY_AP_MAX_BANK_INC() KeyEvent            { return KeyEvent{"AP_MAX_BANK_INC"} }
+func KEY_AP_MAX_BANK_DEC() KeyEvent            { return KeyEvent{"AP_MAX_BANK_DEC"} }
+func KEY_TOGGLE_FLIGHT_DIRECTOR() KeyEvent     { return KeyEvent{"TOGGLE_FLIGHT_DIRECTOR"} }
+func KEY_TOGGLE_GPS_DRIVES_NAV1() KeyEvent     { return KeyEvent{"TOGGLE_GPS_DRIVES_NAV1"} }
+func KEY_AP_PANEL_MACH_HOLD() KeyEvent         { return KeyEvent{"AP_PANEL_MACH_HOLD"} }
+func KEY_AP_MACH_VAR_SET() KeyEvent            { return KeyEvent{"AP_MACH_VAR_SET"} } // mach * 100
+func KEY_AP_PANEL_SPEED_HOLD_TOGGLE() KeyEvent { return KeyEvent{"AP_PANEL_SPEED_HOLD_TOGGLE"} }
+
+// radios and instruments
+func KEY_COM_RADIO_SET() KeyEvent               { return KeyEvent{"COM_RADIO_SET"} }         // BCD16, e.g. 0x2345 for 123.45
+func KEY_COM_RADIO_SET_HZ() KeyEvent            { return KeyEvent{"COM_RADIO_SET_HZ"} }      // Hz, e.g. 123450000
+func KEY_COM_STBY_RADIO_SET_HZ() KeyEvent       { return KeyEvent{"COM_STBY_RADIO_SET_HZ"} } // Hz
+func KEY_COM_STBY_RADIO_SWAP() KeyEvent         { return KeyEvent{"COM_STBY_RADIO_SWAP"} }
+func KEY_COM2_RADIO_SET_HZ() KeyEvent           { return KeyEvent{"COM2_RADIO_SET_HZ"} }      // Hz
+func KEY_COM2_STBY_RADIO_SET_HZ() KeyEvent      { return KeyEvent{"COM2_STBY_RADIO_SET_HZ"} } // Hz
+func KEY_COM2_RADIO_SWAP() KeyEvent             { return KeyEvent{"COM2_RADIO_SWAP"} }
+func KEY_NAV1_RADIO_SET_HZ() KeyEvent           { return KeyEvent{"NAV1_RADIO_SET_HZ"} } // Hz
+func KEY_NAV1_STBY_SET_HZ() KeyEvent            { return KeyEvent{"NAV1_STBY_SET_HZ"} }  // Hz
+func KEY_NAV1_RADIO_SWAP() KeyEvent             { return KeyEvent{"NAV1_RADIO_SWAP"} }
+func KEY_NAV2_RADIO_SET_HZ() KeyEvent           { return KeyEvent{"NAV2_RADIO_SET_HZ"} } // Hz
+func KEY_NAV2_STBY_SET_HZ() KeyEvent            { return KeyEvent{"NAV2_STBY_SET_HZ"} }  // Hz
+func KEY_NAV2_RADIO_SWAP() KeyEvent             { return KeyEvent{"NAV2_RADIO_SWAP"} }
+func KEY_ADF_COMPLETE_SET() KeyEvent            { return KeyEvent{"ADF_COMPLETE_SET"} } // BCD32, e.g. 0x03450000 for 345.0
+func KEY_XPNDR_SET() KeyEvent                   { return KeyEvent{"XPNDR_SET"} }        // BCD16, e.g. 0x7000
+func KEY_VOR1_SET() KeyEvent                    { return KeyEvent{"VOR1_SET"} }         // OBS degrees
+func KEY_VOR2_SET() KeyEvent                    { return KeyEvent{"VOR2_SET"} }         // OBS degrees
+func KEY_KOHLSMAN_SET() KeyEvent                { return KeyEvent{"KOHLSMAN_SET"} }     // millibars * 16
+func KEY_BAROMETRIC() KeyEvent                  { return KeyEvent{"BAROMETRIC"} }       // sets the altimeter to the current pressure
+func KEY_GYRO_DRIFT_SET() KeyEvent              { return KeyEvent{"GYRO_DRIFT_SET"} }   // degrees
+func KEY_HEADING_GYRO_SET() KeyEvent            { return KeyEvent{"HEADING_GYRO_SET"} }
+func KEY_TOGGLE_VACUUM_FAILURE() KeyEvent       { return KeyEvent{"TOGGLE_VACUUM_FAILURE"} }
+func KEY_TOGGLE_ELECTRICAL_FAILURE() KeyEvent   { return KeyEvent{"TOGGLE_ELECTRICAL_FAILURE"} }
+func KEY_TOGGLE_PITOT_BLOCKAGE() KeyEvent       { return KeyEvent{"TOGGLE_PITOT_BLOCKAGE"} }
+func KEY_TOGGLE_STATIC_PORT_BLOCKAGE() KeyEvent { return KeyEvent{"TOGGLE_STATIC_PORT_BLOCKAGE"} }
+
+// lights
+func KEY_ALL_LIGHTS_TOGGLE() KeyEvent         { return KeyEvent{"ALL_LIGHTS_TOGGLE"} }
+func KEY_STROBES_TOGGLE() KeyEvent            { return KeyEvent{"STROBES_TOGGLE"} }
+func KEY_STROBES_SET() KeyEvent               { return KeyEvent{"STROBES_SET"} } // 1 on, 0 off
+func KEY_PANEL_LIGHTS_TOGGLE() KeyEvent       { return KeyEvent{"PANEL_LIGHTS_TOGGLE"} }
+func KEY_LANDING_LIGHTS_TOGGLE() KeyEvent     { return KeyEvent{"LANDING_LIGHTS_TOGGLE"} }
+func KEY_LANDING_LIGHTS_SET() KeyEvent        { return KeyEvent{"LANDING_LIGHTS_SET"} } // 1 on, 0 off
+func KEY_TOGGLE_BEACON_LIGHTS() KeyEvent      { return KeyEvent{"TOGGLE_BEACON_LIGHTS"} }
+func KEY_BEACON_LIGHTS_SET() KeyEvent         { return KeyEvent{"BEACON_LIGHTS_SET"} } // 1 on, 0 off
+func KEY_TOGGLE_TAXI_LIGHTS() KeyEvent        { return KeyEvent{"TOGGLE_TAXI_LIGHTS"} }
+func KEY_TAXI_LIGHTS_SET() KeyEvent           { return KeyEvent{"TAXI_LIGHTS_SET"} } // 1 on, 0 off
+func KEY_TOGGLE_NAV_LIGHTS() KeyEvent         { return KeyEvent{"TOGGLE_NAV_LIGHTS"} }
+func KEY_NAV_LIGHTS_SET() KeyEvent            { return KeyEvent{"NAV_LIGHTS_SET"} } // 1 on, 0 off
+func KEY_TOGGLE_LOGO_LIGHTS() KeyEvent        { return KeyEvent{"TOGGLE_LOGO_LIGHTS"} }
+func KEY_TOGGLE_WING_LIGHTS() KeyEvent        { return KeyEvent{"TOGGLE_WING_LIGHTS"} }
+func KEY_TOGGLE_CABIN_LIGHTS() KeyEvent       { return KeyEvent{"TOGGLE_CABIN_LIGHTS"} }
+func KEY_TOGGLE_RECOGNITION_LIGHTS() KeyEvent { return KeyEvent{"TOGGLE_RECOGNITION_LIGHTS"} }
@@ -0,0 +1,59 @@
+package simconnect_test
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/supersidor/msfs2020-go/simconnect"
+)
+
+func TestKeyEventCompile(t *testing.T) {
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go not found")
+	}
+
+	// neither strings nor conversions make a KeyEvent, the catalog can't be
+	// reassigned
+	out, err := exec.Command(goTool, "build", "-o", os.DevNull, "testdata/keyevent.go").CombinedOutput()
+	if err == nil {
+		t.Fatal("testdata/keyevent.go compiled")
+	}
+	for _, line := range []string{"keyevent.go:8:", "keyevent.go:9:", "keyevent.go:10:"} {
+		if !strings.Contains(string(out), line) {
+			t.Errorf("no error at %s\n%s", line, out)
+		}
+	}
+}
+
+func TestSendEvent(t *testing.T) {
+	srv, s, _ := start(t)
+
+	if err := s.SendEvent(simconnect.KEY_PAUSE_SET(), 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SendEvent(simconnect.CustomKeyEvent("A32NX.FCU_HDG_INC"), 5); err != nil {
+		t.Fatal(err)
+	}
+	// the second time the event is mapped already
+	if err := s.SendEvent(simconnect.CustomKeyEvent("A32NX.FCU_HDG_INC"), 6); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		name string
+		data simconnect.DWORD
+	}{
+		{"PAUSE_SET", 1},
+		{"A32NX.FCU_HDG_INC", 5},
+		{"A32NX.FCU_HDG_INC", 6},
+	}
+	eventually(t, "the events", func() bool { return len(srv.Events()) == len(want) })
+	for i, e := range srv.Events() {
+		if e.Name != want[i].name || e.Data != want[i].data {
+			t.Errorf("event %d is %s %d, want %s %d", i, e.Name, e.Data, want[i].name, want[i].data)
+		}
+	}
+}
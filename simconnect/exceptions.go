@@ -0,0 +1,244 @@
+package simconnect
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// SIMCONNECT_EXCEPTION, the Exception of RecvException.
+const (
+	EXCEPTION_NONE DWORD = iota
+	EXCEPTION_ERROR
+	EXCEPTION_SIZE_MISMATCH
+	EXCEPTION_UNRECOGNIZED_ID
+	EXCEPTION_UNOPENED
+	EXCEPTION_VERSION_MISMATCH
+	EXCEPTION_TOO_MANY_GROUPS
+	EXCEPTION_NAME_UNRECOGNIZED
+	EXCEPTION_TOO_MANY_EVENT_NAMES
+	EXCEPTION_EVENT_ID_DUPLICATE
+	EXCEPTION_TOO_MANY_MAPS
+	EXCEPTION_TOO_MANY_OBJECTS
+	EXCEPTION_TOO_MANY_REQUESTS
+	EXCEPTION_WEATHER_INVALID_PORT
+	EXCEPTION_WEATHER_INVALID_METAR
+	EXCEPTION_WEATHER_UNABLE_TO_GET_OBSERVATION
+	EXCEPTION_WEATHER_UNABLE_TO_CREATE_STATION
+	EXCEPTION_WEATHER_UNABLE_TO_REMOVE_STATION
+	EXCEPTION_INVALID_DATA_TYPE
+	EXCEPTION_INVALID_DATA_SIZE
+	EXCEPTION_DATA_ERROR
+	EXCEPTION_INVALID_ARRAY
+	EXCEPTION_CREATE_OBJECT_FAILED
+	EXCEPTION_LOAD_FLIGHTPLAN_FAILED
+	EXCEPTION_OPERATION_INVALID_FOR_OBJECT_TYPE
+	EXCEPTION_ILLEGAL_OPERATION
+	EXCEPTION_ALREADY_SUBSCRIBED
+	EXCEPTION_INVALID_ENUM
+	EXCEPTION_DEFINITION_ERROR
+	EXCEPTION_DUPLICATE_ID
+	EXCEPTION_DATUM_ID
+	EXCEPTION_OUT_OF_BOUNDS
+	EXCEPTION_ALREADY_CREATED
+	EXCEPTION_OBJECT_OUTSIDE_REALITY_BUBBLE
+	EXCEPTION_OBJECT_CONTAINER
+	EXCEPTION_OBJECT_AI
+	EXCEPTION_OBJECT_ATC
+	EXCEPTION_OBJECT_SCHEDULE
+	EXCEPTION_JETWAY_DATA
+	EXCEPTION_ACTION_NOT_FOUND
+	EXCEPTION_NOT_AN_ACTION
+	EXCEPTION_INCORRECT_ACTION_PARAMS
+	EXCEPTION_GET_INPUT_EVENT_FAILED
+	EXCEPTION_SET_INPUT_EVENT_FAILED
+)
+
+// exceptions holds the name and description of every SIMCONNECT_EXCEPTION,
+// indexed by its value.
+var exceptions = [...]struct{ name, description string }{
+	{"NONE", "no exception"},
+	{"ERROR", "unspecific error"},
+	{"SIZE_MISMATCH", "the size of the data does not match the data definition"},
+	{"UNRECOGNIZED_ID", "the client event, request, data definition or object ID is not recognized"},
+	{"UNOPENED", "the communication with the server is not open"},
+	{"VERSION_MISMATCH", "the SimConnect version of the client is not supported"},
+	{"TOO_MANY_GROUPS", "the maximum number of input or notification groups is reached"},
+	{"NAME_UNRECOGNIZED", "the simulation variable, event or file name is not recognized"},
+	{"TOO_MANY_EVENT_NAMES", "the maximum number of event names is reached"},
+	{"EVENT_ID_DUPLICATE", "the client event ID is already in use"},
+	{"TOO_MANY_MAPS", "the maximum number of mappings is reached"},
+	{"TOO_MANY_OBJECTS", "the maximum number of objects is reached"},
+	{"TOO_MANY_REQUESTS", "the maximum number of requests is reached"},
+	{"WEATHER_INVALID_PORT", "invalid port number for weather"},
+	{"WEATHER_INVALID_METAR", "invalid METAR string"},
+	{"WEATHER_UNABLE_TO_GET_OBSERVATION", "the weather observation could not be retrieved"},
+	{"WEATHER_UNABLE_TO_CREATE_STATION", "the weather station could not be created"},
+	{"WEATHER_UNABLE_TO_REMOVE_STATION", "the weather station could not be removed"},
+	{"INVALID_DATA_TYPE", "the data type is invalid for the request"},
+	{"INVALID_DATA_SIZE", "the size of the data is invalid for the request"},
+	{"DATA_ERROR", "generic data error"},
+	{"INVALID_ARRAY", "invalid array sent with SetDataOnSimObject"},
+	{"CREATE_OBJECT_FAILED", "the AI object could not be created"},
+	{"LOAD_FLIGHTPLAN_FAILED", "the flight plan could not be loaded, it may not exist or be invalid"},
+	{"OPERATION_INVALID_FOR_OBJECT_TYPE", "the operation is invalid for the type of the object"},
+	{"ILLEGAL_OPERATION", "the operation is not allowed, e.g. an AI call in the wrong state"},
+	{"ALREADY_SUBSCRIBED", "the client is already subscribed"},
+	{"INVALID_ENUM", "a value is not a member of its enumeration"},
+	{"DEFINITION_ERROR", "the data definition has a variable length datum not at its end"},
+	{"DUPLICATE_ID", "the ID is already in use"},
+	{"DATUM_ID", "the datum ID is not recognized"},
+	{"OUT_OF_BOUNDS", "the radius of the request is out of bounds"},
+	{"ALREADY_CREATED", "the client data area or its name is already in use"},
+	{"OBJECT_OUTSIDE_REALITY_BUBBLE", "the object is outside the reality bubble of the user"},
+	{"OBJECT_CONTAINER", "the container of the AI object could not be found"},
+	{"OBJECT_AI", "the AI of the object could not be created"},
+	{"OBJECT_ATC", "the ATC of the object could not be created"},
+	{"OBJECT_SCHEDULE", "the schedule of the object could not be created"},
+	{"JETWAY_DATA", "the jetway data could not be retrieved"},
+	{"ACTION_NOT_FOUND", "the action is not found"},
+	{"NOT_AN_ACTION", "the name is not an action"},
+	{"INCORRECT_ACTION_PARAMS", "the parameters of the action are wrong"},
+	{"GET_INPUT_EVENT_FAILED", "the input event could not be read"},
+	{"SET_INPUT_EVENT_FAILED", "the input event could not be set"},
+}
+
+// ExceptionName returns the name of the SIMCONNECT_EXCEPTION exception
+// without its prefix, e.g. "UNRECOGNIZED_ID".
+func ExceptionName(exception DWORD) string {
+	if int(exception) < len(exceptions) {
+		return exceptions[exception].name
+	}
+	return fmt.Sprintf("EXCEPTION(%d)", exception)
+}
+
+// ExceptionDescription describes the SIMCONNECT_EXCEPTION exception.
+func ExceptionDescription(exception DWORD) string {
+	if int(exception) < len(exceptions) {
+		return exceptions[exception].description
+	}
+	return "unknown exception"
+}
+
+// SentCall is a call that sent a packet to the simulator, kept to name the
+// call an exception was raised for.
+type SentCall struct {
+	SendID DWORD
+	Method string        // e.g. "AddToDataDefinition"
+	Args   []interface{} // the arguments of the call, pointers to buffers left out
+	Caller string        // file:line of the call from outside this package
+}
+
+// String formats the call like its Go source, e.g.
+// AddToDataDefinition(0, "PLANE LATITUDE", "degrees", 4, 0, 4294967295).
+func (c SentCall) String() string {
+	args := make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		if s, ok := arg.(string); ok {
+			args[i] = fmt.Sprintf("%q", s)
+		} else {
+			args[i] = fmt.Sprintf("%v", arg)
+		}
+	}
+	return fmt.Sprintf("%s(%s)", c.Method, strings.Join(args, ", "))
+}
+
+// sentCalls is the number of calls a registry keeps, exceptions for older
+// calls are reported without them.
+const sentCalls = 256
+
+// packagePath is the import path of this package, its frames are skipped
+// looking for the caller of a call.
+var packagePath = reflect.TypeOf(SentCall{}).PkgPath()
+
+// recordCall keeps the call of the method skip frames up from recordCall,
+// with its arguments args, under sendID.
+func (s *registry) recordCall(sendID DWORD, skip int, args []interface{}) {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(skip+1, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	call := SentCall{SendID: sendID, Args: args}
+	for {
+		frame, more := frames.Next()
+		if call.Method == "" {
+			call.Method = frame.Function[strings.LastIndex(frame.Function, ".")+1:]
+		} else if !internalFrame(frame.Function) {
+			call.Caller = fmt.Sprintf("%s:%d", filepath.Base(frame.File), frame.Line)
+			break
+		}
+		if !more {
+			break
+		}
+	}
+
+	s.sentMu.Lock()
+	defer s.sentMu.Unlock()
+	s.sent[sendID%sentCalls] = call
+}
+
+// internalFrame reports whether function belongs to this package or the
+// runtime.
+func internalFrame(function string) bool {
+	if strings.HasPrefix(function, "runtime.") {
+		return true
+	}
+	if !strings.HasPrefix(function, packagePath) {
+		return false
+	}
+	return strings.HasPrefix(function[len(packagePath):], ".")
+}
+
+// SentCall returns the call that sent the packet sendID, the SendID of a
+// RecvException. only the most recent calls are kept.
+func (s *registry) SentCall(sendID DWORD) (SentCall, bool) {
+	s.sentMu.Lock()
+	defer s.sentMu.Unlock()
+
+	call := s.sent[sendID%sentCalls]
+	if call.Method == "" || call.SendID != sendID {
+		return SentCall{}, false
+	}
+	return call, true
+}
+
+// ExceptionError is a RecvException as an error, naming the call that caused
+// it when it is still known, e.g.
+//
+//	NAME_UNRECOGNIZED from AddToDataDefinition(0, "PLANE LATTITUDE", "degrees", 4, 0, 4294967295) at main.go:42
+type ExceptionError struct {
+	Exception DWORD // SIMCONNECT_EXCEPTION
+	SendID    DWORD
+	Index     DWORD     // index of the argument the exception is about
+	Call      *SentCall // nil if the call is no longer known
+}
+
+// NewExceptionError looks up the call of the client c that e was raised for.
+func NewExceptionError(c Client, e *RecvException) *ExceptionError {
+	err := &ExceptionError{
+		Exception: e.Exception,
+		SendID:    e.SendID,
+		Index:     e.Index,
+	}
+	if call, ok := c.SentCall(e.SendID); ok {
+		err.Call = &call
+	}
+	return err
+}
+
+func (e *ExceptionError) Error() string {
+	if e.Call == nil {
+		return fmt.Sprintf("%s from sendID %d", ExceptionName(e.Exception), e.SendID)
+	}
+	if e.Call.Caller == "" {
+		return fmt.Sprintf("%s from %s", ExceptionName(e.Exception), e.Call)
+	}
+	return fmt.Sprintf("%s from %s at %s", ExceptionName(e.Exception), e.Call, e.Call.Caller)
+}
+
+// Description describes the exception, see ExceptionDescription.
+func (e *ExceptionError) Description() string {
+	return ExceptionDescription(e.Exception)
+}
@@ -0,0 +1,117 @@
+package simconnect
+
+import "context"
+
+// Facilities is a facility list put together from every message answering
+// one request, lists too big for one message are sent in parts numbered by
+// EntryNumber and OutOf. only the slice of Type is set.
+type Facilities struct {
+	RequestID DWORD
+	Type      DWORD // FACILITY_LIST_TYPE_*
+
+	Airports  []DataFacilityAirport
+	Waypoints []DataFacilityWaypoint
+	NDBs      []DataFacilityNDB
+	VORs      []DataFacilityVOR
+}
+
+// Len returns the number of facilities in the list.
+func (f *Facilities) Len() int {
+	return len(f.Airports) + len(f.Waypoints) + len(f.NDBs) + len(f.VORs)
+}
+
+// facilityListPart is implemented by the decoded facility list messages.
+type facilityListPart interface {
+	facilityList() *RecvFacilityList
+	appendTo(f *Facilities)
+}
+
+func (l *RecvFacilityList) facilityList() *RecvFacilityList { return l }
+
+func (l *RecvFacilityAirportList) appendTo(f *Facilities) {
+	f.Type = FACILITY_LIST_TYPE_AIRPORT
+	f.Airports = append(f.Airports, l.List...)
+}
+
+func (l *RecvFacilityWaypointList) appendTo(f *Facilities) {
+	f.Type = FACILITY_LIST_TYPE_WAYPOINT
+	f.Waypoints = append(f.Waypoints, l.List...)
+}
+
+func (l *RecvFacilityNDBList) appendTo(f *Facilities) {
+	f.Type = FACILITY_LIST_TYPE_NDB
+	f.NDBs = append(f.NDBs, l.List...)
+}
+
+func (l *RecvFacilityVORList) appendTo(f *Facilities) {
+	f.Type = FACILITY_LIST_TYPE_VOR
+	f.VORs = append(f.VORs, l.List...)
+}
+
+// HandleFacilities calls h with the whole list once every part of a facility
+// list answering requestID arrived. a list starting over before the last part
+// drops the parts received so far. subscriptions call h once per list the
+// simulator sends. a nil h removes the handler.
+func (d *Dispatcher) HandleFacilities(requestID DWORD, h func(f *Facilities)) {
+	if h == nil {
+		d.HandleRequest(requestID, nil)
+		return
+	}
+
+	// handlers run on the pump goroutine, one at a time
+	var f *Facilities
+	d.HandleRequest(requestID, func(msg interface{}) {
+		part, ok := msg.(facilityListPart)
+		if !ok {
+			return
+		}
+
+		list := part.facilityList()
+		if f == nil || list.EntryNumber == 0 {
+			f = &Facilities{RequestID: requestID}
+		}
+		part.appendTo(f)
+
+		if list.EntryNumber+1 >= list.OutOf {
+			h(f)
+			f = nil
+		}
+	})
+}
+
+// RequestFacilities requests the facilities of facilityType, one of the
+// FACILITY_LIST_TYPE_* values, in the facilities cache and waits for the
+// whole list. the dispatcher has to be running.
+func (d *Dispatcher) RequestFacilities(ctx context.Context, facilityType DWORD) (*Facilities, error) {
+	return d.requestFacilities(ctx, facilityType, d.client.RequestFacilitiesList)
+}
+
+// RequestFacilitiesEX1 is RequestFacilities with RequestFacilitiesListEX1,
+// which only lists the facilities in range of the user aircraft.
+func (d *Dispatcher) RequestFacilitiesEX1(ctx context.Context, facilityType DWORD) (*Facilities, error) {
+	return d.requestFacilities(ctx, facilityType, d.client.RequestFacilitiesListEX1)
+}
+
+func (d *Dispatcher) requestFacilities(ctx context.Context, facilityType DWORD, request func(facilityType, requestID DWORD) error) (*Facilities, error) {
+	requestID := d.client.GetRequestID()
+
+	result := make(chan *Facilities, 1)
+	d.HandleFacilities(requestID, func(f *Facilities) {
+		select {
+		case result <- f:
+		default:
+		}
+	})
+	defer d.HandleFacilities(requestID, nil)
+
+	if err := request(facilityType, requestID); err != nil {
+		return nil, err
+	}
+
+	select {
+	case f := <-result:
+		return f, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
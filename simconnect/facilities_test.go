@@ -0,0 +1,95 @@
+package simconnect_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/supersidor/msfs2020-go/simconnect"
+)
+
+func vor(i int) simconnect.DataFacilityVOR {
+	return simconnect.DataFacilityVOR{
+		DataFacilityNDB: simconnect.DataFacilityNDB{
+			DataFacilityWaypoint: simconnect.DataFacilityWaypoint{
+				DataFacilityAirport: simconnect.DataFacilityAirport{
+					Icao:      fmt.Sprintf("VOR%d", i),
+					Latitude:  47 + float64(i)/10,
+					Longitude: 8,
+					Altitude:  400,
+				},
+				MagVar: 2.5,
+			},
+			Frequency: 112000000 + simconnect.DWORD(i)*50000,
+		},
+		Flags:           simconnect.RECV_ID_VOR_LIST_HAS_LOCALIZER | simconnect.RECV_ID_VOR_LIST_HAS_GLIDE_SLOPE,
+		Localizer:       143,
+		GlideLat:        47.4,
+		GlideLon:        8.5,
+		GlideAlt:        420,
+		GlideSlopeAngle: 3,
+	}
+}
+
+func TestRequestFacilities(t *testing.T) {
+	srv, _, d := start(t)
+	srv.FacilitiesPerMessage = 2
+
+	var vors []simconnect.DataFacilityVOR
+	for i := 0; i < 5; i++ {
+		vors = append(vors, vor(i))
+	}
+	srv.SetFacilities(simconnect.FACILITY_LIST_TYPE_VOR, vors)
+
+	// sent in 3 parts
+	f, err := d.RequestFacilities(withTimeout(t), simconnect.FACILITY_LIST_TYPE_VOR)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Type != simconnect.FACILITY_LIST_TYPE_VOR || f.Len() != 5 {
+		t.Fatalf("got type %d with %d facilities, want 5 VORs", f.Type, f.Len())
+	}
+	if !reflect.DeepEqual(f.VORs, vors) {
+		t.Errorf("got %+v, want %+v", f.VORs, vors)
+	}
+
+	f, err = d.RequestFacilities(withTimeout(t), simconnect.FACILITY_LIST_TYPE_AIRPORT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Len() != 0 {
+		t.Errorf("got %d airports, want none", f.Len())
+	}
+}
+
+func TestSubscribeToFacilities(t *testing.T) {
+	srv, s, d := start(t)
+
+	lists := make(chan *simconnect.Facilities, 4)
+	requestID := s.GetRequestID()
+	d.HandleFacilities(requestID, func(f *simconnect.Facilities) { lists <- f })
+	if err := s.SubscribeToFacilities(simconnect.FACILITY_LIST_TYPE_NDB, requestID); err != nil {
+		t.Fatal(err)
+	}
+	next := func() *simconnect.Facilities {
+		t.Helper()
+		select {
+		case f := <-lists:
+			return f
+		case <-withTimeout(t).Done():
+			t.Fatal("no facility list")
+		}
+		return nil
+	}
+
+	// the subscription starts with the facilities cached so far, then the
+	// ones added
+	if f := next(); f.Len() != 0 {
+		t.Errorf("got %d NDBs, want none", f.Len())
+	}
+	ndb := vor(1).DataFacilityNDB
+	srv.SetFacilities(simconnect.FACILITY_LIST_TYPE_NDB, []simconnect.DataFacilityNDB{ndb})
+	if f := next(); len(f.NDBs) != 1 || f.NDBs[0] != ndb {
+		t.Errorf("got %+v, want %+v", f.NDBs, ndb)
+	}
+}
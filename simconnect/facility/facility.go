@@ -0,0 +1,109 @@
+// Package facility holds facility definitions of the airport tree for
+// Dispatcher.RequestFacilityData: runways, frequencies and procedures.
+//
+//	var a facility.Airport
+//	err := d.RequestFacilityData(ctx, &a, "KSEA", "")
+//
+// the structs name the fields of the SDK facility definitions, embed them in
+// a struct of your own and add or drop fields to request more or less.
+package facility
+
+import "github.com/supersidor/msfs2020-go/simconnect"
+
+// Airport is an airport with its runways, frequencies, approaches and
+// departures.
+type Airport struct {
+	simconnect.RecvFacilityData `facility:"AIRPORT" json:"-"`
+
+	ICAO      string  `facility:"ICAO" size:"8"`
+	Region    string  `facility:"REGION" size:"8"`
+	Name      string  `facility:"NAME64" size:"64"`
+	Latitude  float64 `facility:"LATITUDE"`
+	Longitude float64 `facility:"LONGITUDE"`
+	Altitude  float64 `facility:"ALTITUDE"` // meters
+	MagVar    float32 `facility:"MAGVAR"`
+
+	Runways     []Runway    `facility:"RUNWAY"`
+	Frequencies []Frequency `facility:"FREQUENCY"`
+	Approaches  []Approach  `facility:"APPROACH"`
+	Departures  []Departure `facility:"DEPARTURE"`
+}
+
+// Runway is a runway of an airport, both of its ends.
+type Runway struct {
+	Latitude            float64 `facility:"LATITUDE"`
+	Longitude           float64 `facility:"LONGITUDE"`
+	Altitude            float64 `facility:"ALTITUDE"`
+	Heading             float32 `facility:"HEADING"`
+	Length              float32 `facility:"LENGTH"` // meters
+	Width               float32 `facility:"WIDTH"`
+	Surface             int32   `facility:"SURFACE"`
+	PrimaryNumber       int32   `facility:"PRIMARY_NUMBER"`
+	PrimaryDesignator   int32   `facility:"PRIMARY_DESIGNATOR"`
+	SecondaryNumber     int32   `facility:"SECONDARY_NUMBER"`
+	SecondaryDesignator int32   `facility:"SECONDARY_DESIGNATOR"`
+
+	PrimaryThreshold   Pavement `facility:"PRIMARY_THRESHOLD"`
+	SecondaryThreshold Pavement `facility:"SECONDARY_THRESHOLD"`
+}
+
+// Pavement is a threshold, blast pad or overrun of a runway end.
+type Pavement struct {
+	Length float32 `facility:"LENGTH"`
+	Width  float32 `facility:"WIDTH"`
+	Enable int32   `facility:"ENABLE"`
+}
+
+// Frequency is a COM frequency of an airport, e.g. its tower.
+type Frequency struct {
+	Type      int32  `facility:"TYPE"`
+	Frequency int32  `facility:"FREQUENCY"` // Hz
+	Name      string `facility:"NAME" size:"64"`
+}
+
+// Approach is an approach procedure with its transitions and legs.
+type Approach struct {
+	Type             int32   `facility:"TYPE"`
+	Suffix           int32   `facility:"SUFFIX"`
+	RunwayNumber     int32   `facility:"RUNWAY_NUMBER"`
+	RunwayDesignator int32   `facility:"RUNWAY_DESIGNATOR"`
+	FAFICAO          string  `facility:"FAF_ICAO" size:"8"`
+	FAFRegion        string  `facility:"FAF_REGION" size:"8"`
+	FAFAltitude      float32 `facility:"FAF_ALTITUDE"`
+	MissedAltitude   float32 `facility:"MISSED_ALTITUDE"`
+
+	Transitions []ApproachTransition `facility:"APPROACH_TRANSITION"`
+	FinalLegs   []Leg                `facility:"FINAL_APPROACH_LEG"`
+	MissedLegs  []Leg                `facility:"MISSED_APPROACH_LEG"`
+}
+
+// ApproachTransition leads from an initial approach fix to an approach.
+type ApproachTransition struct {
+	Type    int32  `facility:"TYPE"`
+	IAFICAO string `facility:"IAF_ICAO" size:"8"`
+	Name    string `facility:"NAME" size:"8"`
+
+	Legs []Leg `facility:"APPROACH_LEG"`
+}
+
+// Leg is a leg of a procedure.
+type Leg struct {
+	Type          int32   `facility:"TYPE"`
+	FixICAO       string  `facility:"FIX_ICAO" size:"8"`
+	FixRegion     string  `facility:"FIX_REGION" size:"8"`
+	FixLatitude   float64 `facility:"FIX_LATITUDE"`
+	FixLongitude  float64 `facility:"FIX_LONGITUDE"`
+	FixAltitude   float64 `facility:"FIX_ALTITUDE"`
+	Course        float32 `facility:"COURSE"`
+	RouteDistance float32 `facility:"ROUTE_DISTANCE"`
+	Altitude1     float32 `facility:"ALTITUDE1"`
+	Altitude2     float32 `facility:"ALTITUDE2"`
+	TurnDirection int32   `facility:"TURN_DIRECTION"`
+}
+
+// Departure is a departure procedure.
+type Departure struct {
+	Name string `facility:"NAME" size:"8"`
+
+	Legs []Leg `facility:"APPROACH_LEG"`
+}
@@ -0,0 +1,359 @@
+package simconnect
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// facilityDataTypes maps the objects of facility definitions to the
+// FACILITY_DATA_* type of their messages.
+var facilityDataTypes = map[string]DWORD{
+	"AIRPORT":                   FACILITY_DATA_AIRPORT,
+	"RUNWAY":                    FACILITY_DATA_RUNWAY,
+	"START":                     FACILITY_DATA_START,
+	"FREQUENCY":                 FACILITY_DATA_FREQUENCY,
+	"HELIPAD":                   FACILITY_DATA_HELIPAD,
+	"APPROACH":                  FACILITY_DATA_APPROACH,
+	"APPROACH_TRANSITION":       FACILITY_DATA_APPROACH_TRANSITION,
+	"APPROACH_LEG":              FACILITY_DATA_APPROACH_LEG,
+	"FINAL_APPROACH_LEG":        FACILITY_DATA_FINAL_APPROACH_LEG,
+	"MISSED_APPROACH_LEG":       FACILITY_DATA_MISSED_APPROACH_LEG,
+	"DEPARTURE":                 FACILITY_DATA_DEPARTURE,
+	"ARRIVAL":                   FACILITY_DATA_ARRIVAL,
+	"RUNWAY_TRANSITION":         FACILITY_DATA_RUNWAY_TRANSITION,
+	"ENROUTE_TRANSITION":        FACILITY_DATA_ENROUTE_TRANSITION,
+	"TAXI_POINT":                FACILITY_DATA_TAXI_POINT,
+	"TAXI_PARKING":              FACILITY_DATA_TAXI_PARKING,
+	"TAXI_PATH":                 FACILITY_DATA_TAXI_PATH,
+	"TAXI_NAME":                 FACILITY_DATA_TAXI_NAME,
+	"JETWAY":                    FACILITY_DATA_JETWAY,
+	"VOR":                       FACILITY_DATA_VOR,
+	"NDB":                       FACILITY_DATA_NDB,
+	"WAYPOINT":                  FACILITY_DATA_WAYPOINT,
+	"ROUTE":                     FACILITY_DATA_ROUTE,
+	"PRIMARY_THRESHOLD":         FACILITY_DATA_PAVEMENT,
+	"PRIMARY_BLASTPAD":          FACILITY_DATA_PAVEMENT,
+	"PRIMARY_OVERRUN":           FACILITY_DATA_PAVEMENT,
+	"SECONDARY_THRESHOLD":       FACILITY_DATA_PAVEMENT,
+	"SECONDARY_BLASTPAD":        FACILITY_DATA_PAVEMENT,
+	"SECONDARY_OVERRUN":         FACILITY_DATA_PAVEMENT,
+	"PRIMARY_APPROACH_LIGHTS":   FACILITY_DATA_APPROACH_LIGHTS,
+	"SECONDARY_APPROACH_LIGHTS": FACILITY_DATA_APPROACH_LIGHTS,
+	"PRIMARY_LEFT_VASI":         FACILITY_DATA_VASI,
+	"PRIMARY_RIGHT_VASI":        FACILITY_DATA_VASI,
+	"SECONDARY_LEFT_VASI":       FACILITY_DATA_VASI,
+	"SECONDARY_RIGHT_VASI":      FACILITY_DATA_VASI,
+}
+
+var recvFacilityDataType = reflect.TypeOf(RecvFacilityData{})
+
+// facilityDefinition describes how a struct registered with
+// RegisterFacilityDefinition maps to the objects of a facility definition.
+type facilityDefinition struct {
+	root    *facilityObject
+	entries []string // AddToFacilityDefinition field names
+}
+
+// facilityObject is a struct standing for one object of the facility tree.
+type facilityObject struct {
+	name     string // e.g. AIRPORT or PRIMARY_THRESHOLD
+	typ      reflect.Type
+	header   []int // index of the embedded RecvFacilityData, nil if there is none
+	fields   []facilityField
+	children []facilityChild
+}
+
+type facilityField struct {
+	name  string
+	index []int
+	size  int // bytes of a string field
+}
+
+// facilityChild is a struct or slice of structs field holding child objects.
+type facilityChild struct {
+	index    []int
+	list     bool
+	dataType DWORD // FACILITY_DATA_*, UNUSED if unknown
+	object   *facilityObject
+}
+
+// parseFacilityDefinition reads the facility and size tags of a struct:
+//
+//   - the root struct embeds RecvFacilityData tagged with the object it
+//     stands for, e.g. `facility:"AIRPORT"`. child structs may embed it to
+//     receive the header of their message.
+//   - int32, uint32, bool, float32 and float64 fields are read as they are,
+//     string fields need the size of the field in bytes, e.g.
+//     `facility:"ICAO" size:"8"`, [N]byte fields take N bytes.
+//   - struct fields tagged with an object name are child objects, slices of
+//     structs lists of them, e.g. `facility:"RUNWAY"`.
+//   - untagged struct fields are groups, their fields are read as if they
+//     were part of the outer struct.
+//   - fields tagged facility:"-" and unexported fields are skipped.
+func parseFacilityDefinition(a interface{}) (*facilityDefinition, error) {
+	t := reflect.TypeOf(a)
+	if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%T is not a pointer to a struct", a)
+	}
+	t = t.Elem()
+
+	field, ok := t.FieldByName("RecvFacilityData")
+	name := field.Tag.Get("facility")
+	if !ok || field.Type != recvFacilityDataType || name == "" {
+		return nil, fmt.Errorf("%s has to embed RecvFacilityData tagged with the facility, e.g. `facility:\"AIRPORT\"`", t.Name())
+	}
+
+	def := &facilityDefinition{}
+	root, err := def.parseObject(name, t)
+	if err != nil {
+		return nil, err
+	}
+	def.root = root
+	return def, nil
+}
+
+func (def *facilityDefinition) parseObject(name string, t reflect.Type) (*facilityObject, error) {
+	o := &facilityObject{name: name, typ: t}
+	def.entries = append(def.entries, "OPEN "+name)
+	if err := def.parseStruct(o, t, nil); err != nil {
+		return nil, err
+	}
+	def.entries = append(def.entries, "CLOSE "+name)
+	return o, nil
+}
+
+func (def *facilityDefinition) parseStruct(o *facilityObject, t reflect.Type, parent []int) error {
+	for j := 0; j < t.NumField(); j++ {
+		field := t.Field(j)
+		index := append(append([]int{}, parent...), field.Index...)
+
+		if field.Type == recvFacilityDataType {
+			if o.header != nil {
+				return fmt.Errorf("%s second RecvFacilityData header", field.Name)
+			}
+			o.header = index
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+
+		name, ok := field.Tag.Lookup("facility")
+		if name == "-" {
+			continue
+		}
+		if !ok || name == "" {
+			if field.Type.Kind() == reflect.Struct {
+				if err := def.parseStruct(o, field.Type, index); err != nil {
+					return err
+				}
+				continue
+			}
+			return fmt.Errorf("%s facility tag not found, tag it facility:\"-\" to skip it", field.Name)
+		}
+
+		switch {
+		case field.Type.Kind() == reflect.Struct,
+			field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.Struct:
+			c := facilityChild{index: index, list: field.Type.Kind() == reflect.Slice, dataType: UNUSED}
+			if dataType, ok := facilityDataTypes[name]; ok {
+				c.dataType = dataType
+			}
+			elem := field.Type
+			if c.list {
+				elem = elem.Elem()
+			}
+			child, err := def.parseObject(name, elem)
+			if err != nil {
+				return err
+			}
+			c.object = child
+			o.children = append(o.children, c)
+			continue
+		}
+
+		f := facilityField{name: name, index: index}
+		switch field.Type.Kind() {
+		case reflect.Int32, reflect.Uint32, reflect.Bool, reflect.Float32:
+			f.size = 4
+		case reflect.Float64:
+			f.size = 8
+		case reflect.Array:
+			if field.Type.Elem().Kind() != reflect.Uint8 {
+				return fmt.Errorf("%s facility type not implemented: %s", field.Name, field.Type)
+			}
+			f.size = field.Type.Len()
+		case reflect.String:
+			size, err := strconv.Atoi(field.Tag.Get("size"))
+			if err != nil || size <= 0 {
+				return fmt.Errorf("%s string needs a size tag, e.g. size:\"8\"", field.Name)
+			}
+			f.size = size
+		default:
+			return fmt.Errorf("%s facility type not implemented: %s", field.Name, field.Type)
+		}
+		o.fields = append(o.fields, f)
+		def.entries = append(def.entries, name)
+	}
+
+	return nil
+}
+
+// unmarshal reads the fields of the object from the data of its message.
+func (o *facilityObject) unmarshal(v reflect.Value, data *FacilityData) error {
+	if o.header != nil {
+		v.FieldByIndex(o.header).Set(reflect.ValueOf(data.RecvFacilityData))
+	}
+
+	r := &recvReader{buf: data.Data}
+	for _, f := range o.fields {
+		field := v.FieldByIndex(f.index)
+		switch field.Kind() {
+		case reflect.Int32:
+			field.SetInt(int64(int32(r.dword())))
+		case reflect.Uint32:
+			field.SetUint(uint64(r.dword()))
+		case reflect.Bool:
+			field.SetBool(r.dword() != 0)
+		case reflect.Float32:
+			field.SetFloat(float64(r.float32()))
+		case reflect.Float64:
+			field.SetFloat(r.float64())
+		case reflect.Array:
+			reflect.Copy(field, reflect.ValueOf(r.bytes(f.size)))
+		case reflect.String:
+			field.SetString(r.string(f.size))
+		}
+		if r.err != nil {
+			return fmt.Errorf("%s %s: %s", o.name, f.name, r.err)
+		}
+	}
+	return nil
+}
+
+// facilityTree puts the objects of one RequestFacilityData together.
+type facilityTree struct {
+	def   *facilityDefinition
+	root  reflect.Value // the struct of the root object
+	found bool
+
+	nodes map[DWORD]*facilityNode // by UniqueRequestID
+}
+
+// facilityNode is an object received, done counts the children that are
+// complete.
+type facilityNode struct {
+	object *facilityObject
+	value  reflect.Value
+	done   []bool
+}
+
+// add places the object of data in the tree.
+func (t *facilityTree) add(data *FacilityData) error {
+	parent, ok := t.nodes[data.ParentUniqueRequestID]
+	if !ok {
+		if t.found {
+			return fmt.Errorf("facility data %d has unknown parent %d", data.UniqueRequestID, data.ParentUniqueRequestID)
+		}
+		t.found = true
+		return t.place(data, t.def.root, t.root)
+	}
+
+	for i, c := range parent.object.children {
+		if parent.done[i] || (c.dataType != UNUSED && c.dataType != data.Type) {
+			continue
+		}
+
+		field := parent.value.FieldByIndex(c.index)
+		if !c.list {
+			parent.done[i] = true
+			return t.place(data, c.object, field)
+		}
+
+		if field.Len() != int(data.ListSize) {
+			field.Set(reflect.MakeSlice(field.Type(), int(data.ListSize), int(data.ListSize)))
+		}
+		if data.ItemIndex >= data.ListSize {
+			return fmt.Errorf("%s item %d of %d", c.object.name, data.ItemIndex, data.ListSize)
+		}
+		if data.ItemIndex+1 == data.ListSize {
+			parent.done[i] = true
+		}
+		return t.place(data, c.object, field.Index(int(data.ItemIndex)))
+	}
+
+	return fmt.Errorf("facility data %d of type %d does not fit %s", data.UniqueRequestID, data.Type, parent.object.name)
+}
+
+func (t *facilityTree) place(data *FacilityData, o *facilityObject, v reflect.Value) error {
+	if err := o.unmarshal(v, data); err != nil {
+		return err
+	}
+	t.nodes[data.UniqueRequestID] = &facilityNode{
+		object: o,
+		value:  v,
+		done:   make([]bool, len(o.children)),
+	}
+	return nil
+}
+
+// RequestFacilityData fills v, a pointer to a struct registered with
+// RegisterFacilityDefinition, with the facility icao (e.g. "KSEA") and waits
+// for every object of it. region is optional, "" matches any. v is
+// registered if it wasn't, the dispatcher has to be running.
+func (d *Dispatcher) RequestFacilityData(ctx context.Context, v interface{}, icao, region string) error {
+	c := d.client
+	if err := c.RegisterFacilityDefinition(v); err != nil {
+		return err
+	}
+	def, ok := c.(interface {
+		facilityDefinition(defineID DWORD) (*facilityDefinition, bool)
+	})
+	if !ok {
+		return fmt.Errorf("%T keeps no facility definitions", c)
+	}
+	defineID := c.GetDefineID(v)
+	fd, ok := def.facilityDefinition(defineID)
+	if !ok {
+		return fmt.Errorf("%T is not registered as facility definition", v)
+	}
+
+	tree := &facilityTree{
+		def:   fd,
+		root:  reflect.ValueOf(v).Elem(),
+		nodes: map[DWORD]*facilityNode{},
+	}
+
+	// handlers run on the pump goroutine, one at a time
+	requestID := c.GetRequestID()
+	result := make(chan error, 1)
+	var err error
+	d.HandleRequest(requestID, func(msg interface{}) {
+		switch m := msg.(type) {
+		case *FacilityData:
+			if err == nil {
+				err = tree.add(m)
+			}
+		case *RecvFacilityDataEnd:
+			if err == nil && !tree.found {
+				err = fmt.Errorf("no facility data for %s", icao)
+			}
+			result <- err
+		}
+	})
+	defer d.HandleRequest(requestID, nil)
+
+	if err := c.RequestFacilityData(defineID, requestID, icao, region); err != nil {
+		return err
+	}
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
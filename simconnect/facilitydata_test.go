@@ -0,0 +1,102 @@
+package simconnect_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/supersidor/msfs2020-go/simconnect"
+	"github.com/supersidor/msfs2020-go/simconnect/simtest"
+)
+
+type threshold struct {
+	Length float32 `facility:"LENGTH"`
+}
+
+type runwayEnds struct {
+	Heading   float32   `facility:"HEADING"`
+	Threshold threshold `facility:"PRIMARY_THRESHOLD"`
+}
+
+type frequency struct {
+	Name      string `facility:"NAME" size:"64"`
+	Frequency int32  `facility:"FREQUENCY"`
+}
+
+type coordinates struct {
+	Latitude  float64 `facility:"LATITUDE"`
+	Longitude float64 `facility:"LONGITUDE"`
+}
+
+type airportDetails struct {
+	simconnect.RecvFacilityData `facility:"AIRPORT"`
+	Position                    coordinates
+	ICAO                        string       `facility:"ICAO" size:"8"`
+	Name                        string       `facility:"NAME" size:"32"`
+	Skipped                     int32        `facility:"-"`
+	Runways                     []runwayEnds `facility:"RUNWAY"`
+	Frequencies                 []frequency  `facility:"FREQUENCY"`
+}
+
+func TestRequestFacilityData(t *testing.T) {
+	srv, _, d := start(t)
+	srv.SetFacilityData("LSZH", &simtest.FacilityObject{
+		Fields: map[string]interface{}{
+			"LATITUDE":  47.46,
+			"LONGITUDE": 8.55,
+			"ICAO":      "LSZH",
+			"NAME":      "Zurich",
+		},
+		Children: map[string][]*simtest.FacilityObject{
+			"RUNWAY": {
+				{
+					Fields: map[string]interface{}{"HEADING": float32(137)},
+					Children: map[string][]*simtest.FacilityObject{
+						"PRIMARY_THRESHOLD": {{Fields: map[string]interface{}{"LENGTH": float32(150)}}},
+					},
+				},
+				{Fields: map[string]interface{}{"HEADING": float32(275)}},
+			},
+			"FREQUENCY": {
+				{Fields: map[string]interface{}{"NAME": "TOWER", "FREQUENCY": int32(118100000)}},
+			},
+		},
+	})
+
+	var a airportDetails
+	if err := d.RequestFacilityData(withTimeout(t), &a, "LSZH", ""); err != nil {
+		t.Fatal(err)
+	}
+	if a.ICAO != "LSZH" || a.Name != "Zurich" || a.Position.Latitude != 47.46 || a.Position.Longitude != 8.55 {
+		t.Errorf("airport %+v", a)
+	}
+	if len(a.Runways) != 2 || a.Runways[0].Heading != 137 || a.Runways[0].Threshold.Length != 150 || a.Runways[1].Heading != 275 {
+		t.Errorf("runways %+v", a.Runways)
+	}
+	if len(a.Frequencies) != 1 || a.Frequencies[0].Name != "TOWER" || a.Frequencies[0].Frequency != 118100000 {
+		t.Errorf("frequencies %+v", a.Frequencies)
+	}
+
+	err := d.RequestFacilityData(withTimeout(t), &a, "XXXX", "")
+	if err == nil || !strings.Contains(err.Error(), "no facility data") {
+		t.Errorf("unknown airport returned %v", err)
+	}
+}
+
+func TestRegisterFacilityDefinition(t *testing.T) {
+	_, s, _ := start(t)
+
+	var untagged struct {
+		simconnect.RecvFacilityData
+		Latitude float64 `facility:"LATITUDE"`
+	}
+	if err := s.RegisterFacilityDefinition(&untagged); err == nil {
+		t.Error("registered a definition without the facility tag")
+	}
+	var unsized struct {
+		simconnect.RecvFacilityData `facility:"AIRPORT"`
+		ICAO                        string `facility:"ICAO"`
+	}
+	if err := s.RegisterFacilityDefinition(&unsized); err == nil {
+		t.Error("registered a string field without a size")
+	}
+}
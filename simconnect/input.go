@@ -0,0 +1,174 @@
+package simconnect
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Input is an input definition of MapInputEventToClientEvent: a key chord
+// like "Shift+Ctrl+B", or a joystick button or axis like
+// "joystick:0:button:3".
+type Input string
+
+// KeyChord returns the input of keys pressed together, e.g.
+// KeyChord("Shift", "Ctrl", "B").
+func KeyChord(keys ...string) Input {
+	return Input(strings.Join(keys, "+"))
+}
+
+// JoystickButton returns the input of a button of joystick, both counted from
+// zero.
+func JoystickButton(joystick, button int) Input {
+	return Input(fmt.Sprintf("joystick:%d:button:%d", joystick, button))
+}
+
+// JoystickAxis returns the input of an axis of joystick, axis is one of
+// "XAxis", "YAxis", "ZAxis", "RxAxis", "RyAxis", "RzAxis", "Slider" or "POV".
+// the handler receives the axis position in RecvEvent.Data.
+func JoystickAxis(joystick int, axis string) Input {
+	return Input(fmt.Sprintf("joystick:%d:%s", joystick, axis))
+}
+
+// InputGroup binds inputs to handlers of a Dispatcher. the priority and state
+// of the group are sent with its first binding, SimConnect input groups start
+// out disabled.
+type InputGroup struct {
+	d        *Dispatcher
+	id       DWORD
+	priority DWORD
+
+	mu       sync.Mutex
+	state    DWORD
+	started  bool
+	bindings map[Input][]DWORD // client event IDs of the down and up handlers
+}
+
+// NewInputGroup returns an input group with a new group ID and priority, one
+// of the GROUP_PRIORITY_* values.
+func (d *Dispatcher) NewInputGroup(priority DWORD) *InputGroup {
+	return &InputGroup{
+		d:        d,
+		id:       d.client.GetGroupID(),
+		priority: priority,
+		state:    STATE_ON,
+		bindings: map[Input][]DWORD{},
+	}
+}
+
+// ID returns the input group ID.
+func (g *InputGroup) ID() DWORD {
+	return g.id
+}
+
+// Bind calls down with the *RecvEvent of every press of input. binding an
+// input again replaces its handlers.
+func (g *InputGroup) Bind(input Input, down Handler) error {
+	return g.BindUpDown(input, down, nil)
+}
+
+// BindUpDown calls down when input is pressed and up when it is released,
+// either can be nil. when the group can't be set up the binding is removed
+// again.
+func (g *InputGroup) BindUpDown(input Input, down, up Handler) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	c := g.d.client
+	if _, ok := g.bindings[input]; ok {
+		if err := g.unbind(input); err != nil {
+			return err
+		}
+	}
+
+	downEventID, upEventID := UNUSED, UNUSED
+	var eventIDs []DWORD
+	if down != nil {
+		downEventID = c.GetEventID()
+		g.d.HandleEvent(downEventID, down)
+		eventIDs = append(eventIDs, downEventID)
+	}
+	if up != nil {
+		upEventID = c.GetEventID()
+		g.d.HandleEvent(upEventID, up)
+		eventIDs = append(eventIDs, upEventID)
+	}
+
+	if err := c.MapInputEventToClientEvent(g.id, string(input), downEventID, 0, upEventID, 0); err != nil {
+		for _, eventID := range eventIDs {
+			g.d.HandleEvent(eventID, nil)
+		}
+		return err
+	}
+	g.bindings[input] = eventIDs
+
+	if !g.started {
+		err := c.SetInputGroupPriority(g.id, g.priority)
+		if err == nil {
+			err = c.SetInputGroupState(g.id, g.state)
+		}
+		if err != nil {
+			// undo the binding, the next one sets up the group again
+			g.unbind(input)
+			return err
+		}
+		g.started = true
+	}
+
+	return nil
+}
+
+// Unbind removes the binding of input.
+func (g *InputGroup) Unbind(input Input) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.unbind(input)
+}
+
+// unbind removes the binding of input, g.mu has to be held.
+func (g *InputGroup) unbind(input Input) error {
+	eventIDs, ok := g.bindings[input]
+	if !ok {
+		return fmt.Errorf("input %s is not bound", input)
+	}
+
+	for _, eventID := range eventIDs {
+		g.d.HandleEvent(eventID, nil)
+	}
+	delete(g.bindings, input)
+
+	return g.d.client.RemoveInputEvent(g.id, string(input))
+}
+
+// SetEnabled turns the group on or off, a disabled group keeps its bindings.
+func (g *InputGroup) SetEnabled(on bool) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	state := STATE_OFF
+	if on {
+		state = STATE_ON
+	}
+	if g.started {
+		if err := g.d.client.SetInputGroupState(g.id, state); err != nil {
+			return err
+		}
+	}
+	g.state = state
+	return nil
+}
+
+// Clear removes every binding of the group.
+func (g *InputGroup) Clear() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for input, eventIDs := range g.bindings {
+		for _, eventID := range eventIDs {
+			g.d.HandleEvent(eventID, nil)
+		}
+		delete(g.bindings, input)
+	}
+
+	return g.d.client.ClearInputGroup(g.id)
+}
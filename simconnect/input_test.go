@@ -0,0 +1,146 @@
+package simconnect_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/supersidor/msfs2020-go/simconnect"
+	"github.com/supersidor/msfs2020-go/simconnect/simtest"
+)
+
+// handled returns a handler passing the data of its events to a channel.
+func handled() (simconnect.Handler, chan simconnect.DWORD) {
+	ch := make(chan simconnect.DWORD, 8)
+	return func(msg interface{}) {
+		if e, ok := msg.(*simconnect.RecvEvent); ok {
+			ch <- e.Data
+		}
+	}, ch
+}
+
+// settle waits until the server handled everything sent to it so far, it
+// handles the packets of a client in order.
+func settle(t *testing.T, d *simconnect.Dispatcher) {
+	t.Helper()
+	if _, err := d.RequestSystemState(withTimeout(t), simconnect.SYSTEM_STATE_SIM); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func expect(t *testing.T, ch chan simconnect.DWORD, what string) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(timeout):
+		t.Fatalf("no %s", what)
+	}
+}
+
+func expectNone(t *testing.T, ch chan simconnect.DWORD, what string) {
+	t.Helper()
+	select {
+	case <-ch:
+		t.Fatalf("unexpected %s", what)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestInputGroup(t *testing.T) {
+	srv, _, d := start(t)
+
+	input := simconnect.KeyChord("Shift", "Ctrl", "B")
+	down, downs := handled()
+	up, ups := handled()
+	g := d.NewInputGroup(simconnect.GROUP_PRIORITY_HIGHEST)
+	if err := g.BindUpDown(input, down, up); err != nil {
+		t.Fatal(err)
+	}
+	settle(t, d)
+
+	srv.PressInput(string(input))
+	expect(t, downs, "down event")
+	srv.ReleaseInput(string(input))
+	expect(t, ups, "up event")
+
+	if err := g.SetEnabled(false); err != nil {
+		t.Fatal(err)
+	}
+	settle(t, d)
+	srv.PressInput(string(input))
+	expectNone(t, downs, "down event of a disabled group")
+
+	// binding again replaces the handlers
+	again, agains := handled()
+	if err := g.SetEnabled(true); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Bind(input, again); err != nil {
+		t.Fatal(err)
+	}
+	settle(t, d)
+	srv.PressInput(string(input))
+	expect(t, agains, "down event of the new handler")
+	expectNone(t, downs, "down event of the replaced handler")
+
+	if err := g.Unbind(input); err != nil {
+		t.Fatal(err)
+	}
+	settle(t, d)
+	srv.PressInput(string(input))
+	expectNone(t, agains, "down event after Unbind")
+}
+
+// failingInputClient fails the SetInputGroupPriority call number failAt.
+type failingInputClient struct {
+	*simconnect.NetSimConnect
+	failAt int
+	calls  int
+}
+
+func (c *failingInputClient) SetInputGroupPriority(groupID, priority simconnect.DWORD) error {
+	c.calls++
+	if c.calls == c.failAt {
+		return errors.New("failed")
+	}
+	return c.NetSimConnect.SetInputGroupPriority(groupID, priority)
+}
+
+func TestInputGroupSetupFails(t *testing.T) {
+	srv := simtest.NewServer()
+	defer srv.Close()
+	s, err := simconnect.Dial("input", srv.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	c := &failingInputClient{NetSimConnect: s, failAt: 1}
+	d := simconnect.NewDispatcher(c)
+	d.Start(withTimeout(t))
+
+	first, second := simconnect.JoystickButton(0, 1), simconnect.JoystickButton(0, 2)
+	firstDown, firstDowns := handled()
+	secondDown, secondDowns := handled()
+	g := d.NewInputGroup(simconnect.GROUP_PRIORITY_STANDARD)
+	if err := g.Bind(first, firstDown); err == nil {
+		t.Fatal("Bind succeeded although setting the priority failed")
+	}
+
+	// the next binding sets up the group, the failed one is gone
+	if err := g.Bind(second, secondDown); err != nil {
+		t.Fatal(err)
+	}
+	if c.calls != 2 {
+		t.Errorf("SetInputGroupPriority called %d times, want 2", c.calls)
+	}
+	settle(t, d)
+
+	srv.PressInput(string(second))
+	expect(t, secondDowns, "down event")
+	srv.PressInput(string(first))
+	expectNone(t, firstDowns, "down event of the failed binding")
+	if err := g.Unbind(first); err == nil {
+		t.Error("the failed binding can be unbound")
+	}
+}
@@ -0,0 +1,402 @@
+// Package lvars reads and writes L:vars, triggers H:events and runs
+// calculator code through the MobiFlight WASM module.
+//
+// the module listens on client data areas: commands are strings written to
+// "<channel>.Command", answers come back in "<channel>.Response" and the
+// values of subscribed expressions are float32s in "<channel>.LVars", the
+// n-th subscription at offset 4*n. a client first registers its own channel
+// on the shared "MobiFlight" one:
+//
+//	MF.Clients.Add.<name>     answered with MF.Clients.Add.<name>.Finished
+//	MF.Ping                   answered with MF.Pong
+//	MF.SimVars.Add.<code>     subscribe to the value of code, e.g. (L:NAME)
+//	MF.SimVars.Clear          drop every subscription
+//	MF.SimVars.Set.<code>     execute code, e.g. 1 (>L:NAME) or (>H:NAME)
+//
+// see lvarstest for a fake module to test against.
+package lvars
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/supersidor/msfs2020-go/simconnect"
+)
+
+// DefaultChannel is the channel every client registers on.
+const DefaultChannel = "MobiFlight"
+
+const (
+	MessageSize = 1024 // size of the Command and Response areas
+	MaxVars     = 1024 // floats in the LVars area
+)
+
+// command is the layout of a Command area.
+type command struct {
+	Text [MessageSize]byte
+}
+
+// response is the layout of a Response area.
+type response struct {
+	simconnect.RecvClientData
+	Text [MessageSize]byte
+}
+
+// values marks the define ID of the subscribed values, its datums are added
+// one by one with AddToClientDataDefinition.
+type values struct{}
+
+// channel holds the client data IDs of a channel.
+type channel struct {
+	name     string
+	command  simconnect.DWORD
+	response simconnect.DWORD
+	values   simconnect.DWORD
+}
+
+// L returns the calculator code reading the L:var name, e.g.
+// L("A32NX_EFIS_L_OPTION") or L("XMLVAR_Baro1_Mode, number").
+func L(name string) string {
+	return "(L:" + name + ")"
+}
+
+// Bridge is a MobiFlight client on a Dispatcher, there can be one Bridge per
+// simconnect.Client. its methods wait for answers, the dispatcher has to be
+// running.
+type Bridge struct {
+	// OnChange is called on the dispatcher goroutine with the code passed to
+	// Subscribe and its new value.
+	OnChange func(code string, value float64)
+
+	d    *simconnect.Dispatcher
+	c    simconnect.Client
+	name string
+
+	responses chan string
+	waitMu    sync.Mutex // held while waiting for a response
+
+	mu                sync.Mutex
+	channel           *channel // nil until registered
+	responseRequestID simconnect.DWORD
+	valuesRequestID   simconnect.DWORD
+	vars              []string       // subscribed code by position in the LVars area
+	index             map[string]int // position of subscribed code
+	values            map[string]float64
+}
+
+// New returns a bridge registering as name, which also names its channel.
+func New(d *simconnect.Dispatcher, name string) *Bridge {
+	c := d.Client()
+	return &Bridge{
+		d:                 d,
+		c:                 c,
+		name:              name,
+		responses:         make(chan string, 16),
+		responseRequestID: c.GetRequestID(),
+		valuesRequestID:   c.GetRequestID(),
+		index:             map[string]int{},
+		values:            map[string]float64{},
+	}
+}
+
+// Register adds the client channel to the module and waits until it is
+// ready, the other methods fail before.
+func (b *Bridge) Register(ctx context.Context) error {
+	if err := b.c.RegisterClientDataDefinition(&command{}); err != nil {
+		return err
+	}
+	if err := b.c.RegisterClientDataDefinition(&response{}); err != nil {
+		return err
+	}
+
+	def, err := b.mapChannel(DefaultChannel)
+	if err != nil {
+		return err
+	}
+	defaultRequestID := b.c.GetRequestID()
+	if err := b.listen(def, defaultRequestID, simconnect.CLIENT_DATA_PERIOD_ON_SET); err != nil {
+		return err
+	}
+
+	cmd := "MF.Clients.Add." + b.name
+	if err := b.exec(ctx, def, cmd, cmd+".Finished"); err != nil {
+		return err
+	}
+	if err := b.listen(def, defaultRequestID, simconnect.CLIENT_DATA_PERIOD_NEVER); err != nil {
+		return err
+	}
+
+	ch, err := b.mapChannel(b.name)
+	if err != nil {
+		return err
+	}
+	if err := b.listen(ch, b.responseRequestID, simconnect.CLIENT_DATA_PERIOD_ON_SET); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	b.channel = ch
+	b.mu.Unlock()
+	return nil
+}
+
+// mapChannel maps the client data areas of the channel name.
+func (b *Bridge) mapChannel(name string) (*channel, error) {
+	ch := &channel{
+		name:     name,
+		command:  b.c.GetClientDataID(),
+		response: b.c.GetClientDataID(),
+		values:   b.c.GetClientDataID(),
+	}
+	if err := b.c.MapClientDataNameToID(name+".Command", ch.command); err != nil {
+		return nil, err
+	}
+	if err := b.c.MapClientDataNameToID(name+".Response", ch.response); err != nil {
+		return nil, err
+	}
+	if err := b.c.MapClientDataNameToID(name+".LVars", ch.values); err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+// listen starts or, with CLIENT_DATA_PERIOD_NEVER, stops the requestID
+// request of the responses of ch.
+func (b *Bridge) listen(ch *channel, requestID simconnect.DWORD, period simconnect.ClientDataPeriod) error {
+	if period == simconnect.CLIENT_DATA_PERIOD_NEVER {
+		b.d.HandleRequest(requestID, nil)
+	} else {
+		b.d.HandleRequest(requestID, b.handleResponse)
+	}
+	return b.c.RequestClientData(
+		ch.response,
+		requestID,
+		b.c.GetDefineID(&response{}),
+		period,
+		simconnect.CLIENT_DATA_REQUEST_FLAG_DEFAULT,
+		0, 0, 0,
+	)
+}
+
+func (b *Bridge) handleResponse(msg interface{}) {
+	data, ok := msg.(*simconnect.ClientData)
+	if !ok {
+		return
+	}
+	r, ok := data.Value.(*response)
+	if !ok {
+		return
+	}
+
+	text := string(r.Text[:])
+	if i := strings.IndexByte(text, 0); i >= 0 {
+		text = text[:i]
+	}
+
+	// nobody waits for responses nobody asked for, drop them
+	select {
+	case b.responses <- text:
+	default:
+	}
+}
+
+// registered returns the client channel.
+func (b *Bridge) registered() (*channel, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.channel == nil {
+		return nil, fmt.Errorf("lvars client %s is not registered", b.name)
+	}
+	return b.channel, nil
+}
+
+// send writes cmd to the command area of ch.
+func (b *Bridge) send(ch *channel, cmd string) error {
+	if len(cmd) >= MessageSize {
+		return fmt.Errorf("lvars command is longer than %d bytes: %s", MessageSize-1, cmd)
+	}
+	var c command
+	copy(c.Text[:], cmd)
+	return b.c.WriteClientData(ch.command, &c)
+}
+
+// exec sends cmd and waits for the response want.
+func (b *Bridge) exec(ctx context.Context, ch *channel, cmd, want string) error {
+	b.waitMu.Lock()
+	defer b.waitMu.Unlock()
+
+	// drop responses that arrived while nobody was waiting
+	for len(b.responses) > 0 {
+		<-b.responses
+	}
+
+	if err := b.send(ch, cmd); err != nil {
+		return err
+	}
+	for {
+		select {
+		case text := <-b.responses:
+			if text == want {
+				return nil
+			}
+		case <-ctx.Done():
+			return fmt.Errorf("lvars %s: no %s: %s", cmd, want, ctx.Err())
+		}
+	}
+}
+
+// Ping waits for the module to answer.
+func (b *Bridge) Ping(ctx context.Context) error {
+	ch, err := b.registered()
+	if err != nil {
+		return err
+	}
+	return b.exec(ctx, ch, "MF.Ping", "MF.Pong")
+}
+
+// Subscribe adds the value of code, e.g. L("NAME") or "(A:PLANE ALTITUDE,
+// feet)", to the values sent by the module. see Value and OnChange.
+func (b *Bridge) Subscribe(code string) error {
+	ch, err := b.registered()
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.index[code]; ok {
+		return nil
+	}
+	n := len(b.vars)
+	if n >= MaxVars {
+		return fmt.Errorf("lvars client %s can't subscribe to more than %d values", b.name, MaxVars)
+	}
+
+	// the datum and request go out before the command, the module sends the
+	// first value as soon as it sees it
+	defineID := b.c.GetDefineID(values{})
+	err = b.c.AddToClientDataDefinition(defineID, simconnect.DWORD(4*n), simconnect.CLIENT_DATA_TYPE_FLOAT32, 0, simconnect.DWORD(n))
+	if err != nil {
+		return err
+	}
+	b.d.HandleRequest(b.valuesRequestID, b.handleValues)
+	err = b.c.RequestClientData(
+		ch.values,
+		b.valuesRequestID,
+		defineID,
+		simconnect.CLIENT_DATA_PERIOD_ON_SET,
+		simconnect.CLIENT_DATA_REQUEST_FLAG_CHANGED|simconnect.CLIENT_DATA_REQUEST_FLAG_TAGGED,
+		0, 0, 0,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := b.send(ch, "MF.SimVars.Add."+code); err != nil {
+		return err
+	}
+	b.vars = append(b.vars, code)
+	b.index[code] = n
+	return nil
+}
+
+func (b *Bridge) handleValues(msg interface{}) {
+	data, ok := msg.(*simconnect.ClientData)
+	if !ok {
+		return
+	}
+
+	type change struct {
+		code  string
+		value float64
+	}
+	var changes []change
+
+	b.mu.Lock()
+	buf := data.Data
+	for i := simconnect.DWORD(0); i < data.DefineCount && len(buf) >= 8; i++ {
+		datumID := binary.LittleEndian.Uint32(buf)
+		value := float64(math.Float32frombits(binary.LittleEndian.Uint32(buf[4:])))
+		buf = buf[8:]
+
+		if int(datumID) >= len(b.vars) {
+			continue
+		}
+		code := b.vars[datumID]
+		b.values[code] = value
+		changes = append(changes, change{code, value})
+	}
+	onChange := b.OnChange
+	b.mu.Unlock()
+
+	if onChange != nil {
+		for _, c := range changes {
+			onChange(c.code, c.value)
+		}
+	}
+}
+
+// Value returns the last value of code received, false before the first one.
+func (b *Bridge) Value(code string) (float64, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	value, ok := b.values[code]
+	return value, ok
+}
+
+// Clear drops every subscription.
+func (b *Bridge) Clear() error {
+	ch, err := b.registered()
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.vars) == 0 {
+		return nil
+	}
+
+	defineID := b.c.GetDefineID(values{})
+	err = b.c.RequestClientData(ch.values, b.valuesRequestID, defineID, simconnect.CLIENT_DATA_PERIOD_NEVER, 0, 0, 0, 0)
+	if err != nil {
+		return err
+	}
+	if err := b.c.ClearClientDataDefinition(defineID); err != nil {
+		return err
+	}
+	if err := b.send(ch, "MF.SimVars.Clear"); err != nil {
+		return err
+	}
+
+	b.vars = nil
+	b.index = map[string]int{}
+	b.values = map[string]float64{}
+	return nil
+}
+
+// Execute runs calculator code in the simulator.
+func (b *Bridge) Execute(code string) error {
+	ch, err := b.registered()
+	if err != nil {
+		return err
+	}
+	return b.send(ch, "MF.SimVars.Set."+code)
+}
+
+// Set sets the L:var name to value.
+func (b *Bridge) Set(name string, value float64) error {
+	return b.Execute(strconv.FormatFloat(value, 'f', -1, 64) + " (>L:" + name + ")")
+}
+
+// TriggerH triggers the H:event name.
+func (b *Bridge) TriggerH(name string) error {
+	return b.Execute("(>H:" + name + ")")
+}
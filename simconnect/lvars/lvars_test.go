@@ -0,0 +1,196 @@
+package lvars_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/supersidor/msfs2020-go/simconnect"
+	"github.com/supersidor/msfs2020-go/simconnect/lvars"
+	"github.com/supersidor/msfs2020-go/simconnect/lvars/lvarstest"
+	"github.com/supersidor/msfs2020-go/simconnect/simtest"
+)
+
+const timeout = 2 * time.Second
+
+// start connects a peer and a bridge named "test" to a new server, the
+// bridge is not registered yet.
+func start(t *testing.T) (*lvarstest.Peer, *lvars.Bridge) {
+	sim := simtest.NewServer()
+	t.Cleanup(sim.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	dispatcher := func(name string) *simconnect.Dispatcher {
+		s, err := simconnect.Dial(name, sim.Addr())
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { s.Close() })
+		return simconnect.NewDispatcher(s)
+	}
+
+	pd := dispatcher("peer")
+	peer, err := lvarstest.NewPeer(pd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pd.Start(ctx)
+
+	// the server handles the packets of a client in order, once it answered
+	// this the channel of the peer is listening
+	wait, done := context.WithTimeout(ctx, timeout)
+	defer done()
+	if _, err := pd.RequestSystemState(wait, simconnect.SYSTEM_STATE_SIM); err != nil {
+		t.Fatal(err)
+	}
+
+	bd := dispatcher("bridge")
+	bd.Start(ctx)
+	return peer, lvars.New(bd, "test")
+}
+
+func register(t *testing.T, b *lvars.Bridge) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := b.Register(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// eventually fails the test if cond doesn't hold within the timeout.
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func hasValue(b *lvars.Bridge, code string, want float64) func() bool {
+	return func() bool {
+		v, ok := b.Value(code)
+		return ok && v == want
+	}
+}
+
+func TestRegister(t *testing.T) {
+	peer, b := start(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := b.Ping(ctx); err == nil {
+		t.Error("Ping before Register succeeded")
+	}
+	if err := b.Subscribe(lvars.L("A")); err == nil {
+		t.Error("Subscribe before Register succeeded")
+	}
+
+	register(t, b)
+	if clients := peer.Clients(); len(clients) != 1 || clients[0] != "test" {
+		t.Errorf("peer clients %q, want [test]", clients)
+	}
+	if err := b.Ping(ctx); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	peer, b := start(t)
+	register(t, b)
+
+	var mu sync.Mutex
+	changes := map[string][]float64{}
+	b.OnChange = func(code string, value float64) {
+		mu.Lock()
+		defer mu.Unlock()
+		changes[code] = append(changes[code], value)
+	}
+
+	a, c := lvars.L("A"), lvars.L("C, number")
+	peer.SetLVar("A", 1)
+	peer.SetLVar("C", 2)
+	if err := b.Subscribe(a); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Subscribe(c); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "the first values", func() bool {
+		return hasValue(b, a, 1)() && hasValue(b, c, 2)()
+	})
+
+	// only C changed, it arrives tagged with its datum ID
+	peer.SetLVar("C", 2.5)
+	eventually(t, "C to change", hasValue(b, c, 2.5))
+	if v, _ := b.Value(a); v != 1 {
+		t.Errorf("A is %v after C changed, want 1", v)
+	}
+
+	mu.Lock()
+	got := changes[c]
+	mu.Unlock()
+	if len(got) == 0 || got[len(got)-1] != 2.5 {
+		t.Errorf("OnChange got %v for C, want 2.5 last", got)
+	}
+
+	// subscribing twice keeps the first subscription
+	if err := b.Subscribe(a); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := b.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := b.Value(a); ok {
+		t.Error("A still has a value after Clear")
+	}
+
+	// after Clear C is the first value
+	if err := b.Subscribe(c); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "C after Clear", hasValue(b, c, 2.5))
+	if _, ok := b.Value(a); ok {
+		t.Error("A got a value after Clear")
+	}
+}
+
+func TestSet(t *testing.T) {
+	peer, b := start(t)
+	register(t, b)
+
+	a := lvars.L("A")
+	if err := b.Subscribe(a); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "the first value", hasValue(b, a, 0))
+
+	if err := b.Set("A", 4.5); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "the peer to set A", func() bool {
+		v, ok := peer.LVar("A")
+		return ok && v == 4.5
+	})
+	eventually(t, "the new value of A", hasValue(b, a, 4.5))
+
+	if err := b.TriggerH("AS1000_PFD_SOFTKEYS_1"); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "the H:event", func() bool {
+		events := peer.HEvents()
+		return len(events) == 1 && events[0] == "AS1000_PFD_SOFTKEYS_1"
+	})
+
+	executed := peer.Executed()
+	want := []string{"4.5 (>L:A)", "(>H:AS1000_PFD_SOFTKEYS_1)"}
+	if len(executed) != len(want) || executed[0] != want[0] || executed[1] != want[1] {
+		t.Errorf("executed %q, want %q", executed, want)
+	}
+}
@@ -0,0 +1,318 @@
+// Package lvarstest provides a fake MobiFlight WASM module for testing
+// package lvars.
+//
+// the peer is a SimConnect client itself, like the real module. connect it to
+// a simtest.Server, or any other server sharing client data areas with the
+// code under test:
+//
+//	sim := simtest.NewServer()
+//	pc, _ := simconnect.Dial("peer", sim.Addr())
+//	pd := simconnect.NewDispatcher(pc)
+//	peer, _ := lvarstest.NewPeer(pd)
+//	pd.Start(ctx)
+//
+// it keeps a table of L:vars and evaluates the calculator code it is sent
+// with a small subset of RPN: numbers, (L:NAME), (>L:NAME) and (>H:NAME).
+package lvarstest
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/supersidor/msfs2020-go/simconnect"
+	"github.com/supersidor/msfs2020-go/simconnect/lvars"
+)
+
+type command struct {
+	simconnect.RecvClientData
+	Text [lvars.MessageSize]byte
+}
+
+type response struct {
+	Text [lvars.MessageSize]byte
+}
+
+type values struct {
+	Values [lvars.MaxVars]float32
+}
+
+// channel is a channel of the peer, the default one or a client's.
+type channel struct {
+	name     string
+	command  simconnect.DWORD
+	response simconnect.DWORD
+	values   simconnect.DWORD
+	vars     []string // subscribed code
+}
+
+// Peer answers the commands of lvars.Bridge clients.
+type Peer struct {
+	d *simconnect.Dispatcher
+	c simconnect.Client
+
+	mu       sync.Mutex
+	lvars    map[string]float64 // by upper case name
+	hEvents  []string
+	executed []string
+	channels []*channel
+}
+
+// NewPeer creates the default channel on the client of d and answers the
+// commands sent to it once d runs.
+func NewPeer(d *simconnect.Dispatcher) (*Peer, error) {
+	p := &Peer{
+		d:     d,
+		c:     d.Client(),
+		lvars: map[string]float64{},
+	}
+
+	for _, v := range []interface{}{&command{}, &response{}, &values{}} {
+		if err := p.c.RegisterClientDataDefinition(v); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := p.addChannel(lvars.DefaultChannel); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// addChannel creates the client data areas of the channel name and listens
+// to its commands.
+func (p *Peer) addChannel(name string) (*channel, error) {
+	c := p.c
+	ch := &channel{
+		name:     name,
+		command:  c.GetClientDataID(),
+		response: c.GetClientDataID(),
+		values:   c.GetClientDataID(),
+	}
+
+	areas := []struct {
+		suffix string
+		id     simconnect.DWORD
+		size   simconnect.DWORD
+	}{
+		{".Command", ch.command, lvars.MessageSize},
+		{".Response", ch.response, lvars.MessageSize},
+		{".LVars", ch.values, 4 * lvars.MaxVars},
+	}
+	for _, a := range areas {
+		if err := c.MapClientDataNameToID(name+a.suffix, a.id); err != nil {
+			return nil, err
+		}
+		if err := c.CreateClientData(a.id, a.size, simconnect.CREATE_CLIENT_DATA_FLAG_DEFAULT); err != nil {
+			return nil, err
+		}
+	}
+
+	requestID := c.GetRequestID()
+	p.d.HandleRequest(requestID, func(msg interface{}) {
+		data, ok := msg.(*simconnect.ClientData)
+		if !ok {
+			return
+		}
+		if cmd, ok := data.Value.(*command); ok {
+			p.handle(ch, cString(cmd.Text[:]))
+		}
+	})
+	err := c.RequestClientData(
+		ch.command,
+		requestID,
+		c.GetDefineID(&command{}),
+		simconnect.CLIENT_DATA_PERIOD_ON_SET,
+		simconnect.CLIENT_DATA_REQUEST_FLAG_DEFAULT,
+		0, 0, 0,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	p.channels = append(p.channels, ch)
+	p.mu.Unlock()
+	return ch, nil
+}
+
+// handle runs a command sent to ch.
+func (p *Peer) handle(ch *channel, cmd string) {
+	switch {
+	case cmd == "MF.Ping":
+		p.respond(ch, "MF.Pong")
+
+	case strings.HasPrefix(cmd, "MF.Clients.Add."):
+		name := strings.TrimPrefix(cmd, "MF.Clients.Add.")
+		if _, err := p.addChannel(name); err != nil {
+			return
+		}
+		p.respond(ch, cmd+".Finished")
+
+	case strings.HasPrefix(cmd, "MF.SimVars.Add."):
+		p.mu.Lock()
+		ch.vars = append(ch.vars, strings.TrimPrefix(cmd, "MF.SimVars.Add."))
+		p.mu.Unlock()
+		p.writeValues(ch)
+
+	case cmd == "MF.SimVars.Clear":
+		p.mu.Lock()
+		ch.vars = nil
+		p.mu.Unlock()
+
+	case strings.HasPrefix(cmd, "MF.SimVars.Set."):
+		code := strings.TrimPrefix(cmd, "MF.SimVars.Set.")
+		p.mu.Lock()
+		p.executed = append(p.executed, code)
+		p.eval(code)
+		p.mu.Unlock()
+		p.writeAllValues()
+	}
+}
+
+func (p *Peer) respond(ch *channel, text string) {
+	var r response
+	copy(r.Text[:], text)
+	p.c.WriteClientData(ch.response, &r)
+}
+
+// writeValues writes the current values of the subscriptions of ch to its
+// LVars area.
+func (p *Peer) writeValues(ch *channel) {
+	var v values
+	p.mu.Lock()
+	for i, code := range ch.vars {
+		v.Values[i] = float32(p.eval(code))
+	}
+	p.mu.Unlock()
+
+	p.c.WriteClientData(ch.values, &v)
+}
+
+func (p *Peer) writeAllValues() {
+	p.mu.Lock()
+	channels := append([]*channel{}, p.channels...)
+	p.mu.Unlock()
+
+	for _, ch := range channels {
+		p.writeValues(ch)
+	}
+}
+
+// eval runs code and returns the value left on the stack, p.mu has to be
+// held.
+func (p *Peer) eval(code string) float64 {
+	var stack []float64
+	pop := func() float64 {
+		if len(stack) == 0 {
+			return 0
+		}
+		v := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		return v
+	}
+
+	for _, token := range tokens(code) {
+		switch {
+		case strings.HasPrefix(token, "(>L:"):
+			p.lvars[lvarKey(token[4:len(token)-1])] = pop()
+		case strings.HasPrefix(token, "(>H:"):
+			p.hEvents = append(p.hEvents, token[4:len(token)-1])
+		case strings.HasPrefix(token, "(L:"):
+			stack = append(stack, p.lvars[lvarKey(token[3:len(token)-1])])
+		default:
+			if v, err := strconv.ParseFloat(token, 64); err == nil {
+				stack = append(stack, v)
+			}
+		}
+	}
+	return pop()
+}
+
+// tokens splits calculator code at spaces outside of parentheses.
+func tokens(code string) []string {
+	var tokens []string
+	depth, start := 0, -1
+	for i, r := range code {
+		switch {
+		case r == '(':
+			depth += 1
+		case r == ')':
+			depth -= 1
+		}
+		if r == ' ' && depth == 0 {
+			if start >= 0 {
+				tokens = append(tokens, code[start:i])
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, code[start:])
+	}
+	return tokens
+}
+
+// lvarKey drops the unit of an L:var reference, L:vars are compared ignoring
+// case.
+func lvarKey(name string) string {
+	if i := strings.IndexByte(name, ','); i >= 0 {
+		name = name[:i]
+	}
+	return strings.ToUpper(strings.TrimSpace(name))
+}
+
+func cString(b []byte) string {
+	s := string(b)
+	if i := strings.IndexByte(s, 0); i >= 0 {
+		s = s[:i]
+	}
+	return s
+}
+
+// SetLVar sets the L:var name, like a gauge would, and sends the new values
+// to subscribed clients.
+func (p *Peer) SetLVar(name string, value float64) {
+	p.mu.Lock()
+	p.lvars[lvarKey(name)] = value
+	p.mu.Unlock()
+
+	p.writeAllValues()
+}
+
+// LVar returns the value of the L:var name, false if it was never set.
+func (p *Peer) LVar(name string) (float64, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	value, ok := p.lvars[lvarKey(name)]
+	return value, ok
+}
+
+// HEvents returns the H:events triggered so far, oldest first.
+func (p *Peer) HEvents() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string{}, p.hEvents...)
+}
+
+// Executed returns the calculator code executed so far, oldest first.
+func (p *Peer) Executed() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string{}, p.executed...)
+}
+
+// Clients returns the names of the registered clients.
+func (p *Peer) Clients() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var names []string
+	for _, ch := range p.channels {
+		if ch.name != lvars.DefaultChannel {
+			names = append(names, ch.name)
+		}
+	}
+	return names
+}
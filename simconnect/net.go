@@ -0,0 +1,921 @@
+package simconnect
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// NetSimConnect talks the SimConnect network protocol directly over TCP, it does
+// not need SimConnect.dll and works on every platform.
+//
+// the simulator has to be configured to accept remote clients, see the
+// SimConnect.xml section of the SDK documentation.
+type NetSimConnect struct {
+	registry
+	conn     net.Conn
+	protocol DWORD
+	timeout  time.Duration // for every write
+
+	writeMu    sync.Mutex
+	lastSendID DWORD
+
+	recv    chan []byte
+	pending []byte
+	done    chan struct{} // closed by Close, stops readLoop
+	once    sync.Once
+
+	errMu sync.Mutex
+	err   error
+}
+
+// Dialer contains options for connecting to a simulator over the network.
+type Dialer struct {
+	// Protocol is the SimConnect protocol version announced to the server,
+	// defaults to PROTOCOL_DEFAULT.
+	Protocol DWORD
+
+	// Timeout limits how long connecting, the open handshake and every
+	// write after it may take, defaults to 5 seconds. a stalled simulator
+	// fails the calls instead of blocking them.
+	Timeout time.Duration
+}
+
+// Dial connects to the SimConnect server listening on address ("host:port")
+// using the default Dialer.
+func Dial(name, address string) (*NetSimConnect, error) {
+	var d Dialer
+	return d.Dial(name, address)
+}
+
+// Dial connects to the SimConnect server listening on address and performs the
+// open handshake. the RECV_ID_OPEN reply is kept and returned by the first
+// GetNextDispatch call, same as with SimConnect.dll.
+func (d *Dialer) Dial(name, address string) (*NetSimConnect, error) {
+	protocol := d.Protocol
+	if protocol == 0 {
+		protocol = PROTOCOL_DEFAULT
+	}
+	timeout := d.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("SimConnect_Open error: %s", err)
+	}
+
+	s := &NetSimConnect{
+		registry: newRegistry(),
+		conn:     conn,
+		protocol: protocol,
+		timeout:  timeout,
+		recv:     make(chan []byte, 256),
+		done:     make(chan struct{}),
+	}
+	go s.readLoop()
+
+	if err := s.open(name); err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	select {
+	case buf, ok := <-s.recv:
+		if !ok {
+			s.Close()
+			return nil, fmt.Errorf("SimConnect_Open error: %s", s.readErr())
+		}
+		if id := DWORD(binary.LittleEndian.Uint32(buf[8:])); id != RECV_ID_OPEN {
+			s.Close()
+			return nil, fmt.Errorf("SimConnect_Open error: unexpected reply %d", id)
+		}
+		s.pending = buf
+
+	case <-time.After(timeout):
+		s.Close()
+		return nil, fmt.Errorf("SimConnect_Open error: no reply from %s", address)
+	}
+
+	return s, nil
+}
+
+func (s *NetSimConnect) open(name string) error {
+	p := newPacket().
+		string(name, protocolStringShort).
+		dword(0).
+		bytes([]byte{0})
+
+	switch s.protocol {
+	case PROTOCOL_KITTYHAWK:
+		p.bytes([]byte("HK\x00")).dword(11).dword(0).dword(62651).dword(3)
+	case PROTOCOL_FSX_RTM:
+		p.bytes([]byte("XSF")).dword(10).dword(0).dword(60905).dword(0)
+	case PROTOCOL_FSX_SP1:
+		p.bytes([]byte("XSF")).dword(10).dword(0).dword(61355).dword(0)
+	default:
+		p.bytes([]byte("XSF")).dword(10).dword(0).dword(61259).dword(0)
+	}
+
+	if err := s.send(PACKET_OPEN, p, name); err != nil {
+		return fmt.Errorf("SimConnect_Open error: %s", err)
+	}
+	return nil
+}
+
+// send writes the packet and records the calling method with its arguments
+// args under the send ID of the packet, see SentCall.
+func (s *NetSimConnect) send(packetType DWORD, p *packet, args ...interface{}) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	s.lastSendID += 1
+	buf := p.finish(s.protocol, packetType, s.lastSendID)
+	s.recordCall(s.lastSendID, 2, args)
+
+	s.conn.SetWriteDeadline(time.Now().Add(s.timeout))
+	_, err := s.conn.Write(buf)
+	return err
+}
+
+func (s *NetSimConnect) readLoop() {
+	defer close(s.recv)
+
+	var header [4]byte
+	for {
+		if _, err := io.ReadFull(s.conn, header[:]); err != nil {
+			s.setReadErr(err)
+			return
+		}
+
+		size := binary.LittleEndian.Uint32(header[:])
+		if size < uint32(unsafe.Sizeof(Recv{})) || size > maxRecvPacketSize {
+			s.setReadErr(fmt.Errorf("invalid packet size %d", size))
+			return
+		}
+
+		buf := make([]byte, size)
+		copy(buf, header[:])
+		if _, err := io.ReadFull(s.conn, buf[4:]); err != nil {
+			s.setReadErr(err)
+			return
+		}
+
+		select {
+		case s.recv <- buf:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *NetSimConnect) setReadErr(err error) {
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+func (s *NetSimConnect) readErr() error {
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+	return s.err
+}
+
+func (s *NetSimConnect) RegisterDataDefinition(a interface{}) error {
+	return s.registerDataDefinition(s, a)
+}
+
+func (s *NetSimConnect) SetData(objectID DWORD, v interface{}) error {
+	return s.setData(s, objectID, v)
+}
+
+func (s *NetSimConnect) SendEvent(event KeyEvent, data ...DWORD) error {
+	return s.sendEvent(s, event, data...)
+}
+
+func (s *NetSimConnect) RegisterClientDataDefinition(a interface{}) error {
+	return s.registerClientDataDefinition(s, a)
+}
+
+func (s *NetSimConnect) WriteClientData(clientDataID DWORD, v interface{}) error {
+	return s.writeClientData(s, clientDataID, v)
+}
+
+func (s *NetSimConnect) RegisterFacilityDefinition(a interface{}) error {
+	return s.registerFacilityDefinition(s, a)
+}
+
+func (s *NetSimConnect) Close() error {
+	s.once.Do(func() { close(s.done) })
+	if err := s.conn.Close(); err != nil {
+		return fmt.Errorf("SimConnect_Close error: %s", err)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) AddToDataDefinition(defineID DWORD, name, unit string, dataType DWORD, epsilon float32, datumID DWORD) error {
+	p := newPacket().
+		dword(defineID).
+		string(name, protocolStringShort).
+		string(unit, protocolStringShort).
+		dword(dataType).
+		float32(epsilon).
+		dword(datumID)
+
+	if err := s.send(PACKET_ADD_TO_DATA_DEFINITION, p, defineID, name, unit, dataType, epsilon, datumID); err != nil {
+		return fmt.Errorf("SimConnect_AddToDataDefinition for %s error: %s", name, err)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) ClearDataDefinition(defineID DWORD) error {
+	p := newPacket().
+		dword(defineID)
+
+	if err := s.send(PACKET_CLEAR_DATA_DEFINITION, p, defineID); err != nil {
+		return fmt.Errorf("SimConnect_ClearDataDefinition for defineID %d error: %s", defineID, err)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) SubscribeToSystemEvent(eventID DWORD, eventName string) error {
+	p := newPacket().
+		dword(eventID).
+		string(eventName, protocolStringShort)
+
+	if err := s.send(PACKET_SUBSCRIBE_TO_SYSTEM_EVENT, p, eventID, eventName); err != nil {
+		return fmt.Errorf("SimConnect_SubscribeToSystemEvent for %s error: %s", eventName, err)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) UnsubscribeFromSystemEvent(eventID DWORD) error {
+	p := newPacket().
+		dword(eventID)
+
+	if err := s.send(PACKET_UNSUBSCRIBE_FROM_SYSTEM_EVENT, p, eventID); err != nil {
+		return fmt.Errorf("SimConnect_UnsubscribeFromSystemEvent for eventID %d error: %s", eventID, err)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) SetSystemEventState(eventID, state DWORD) error {
+	p := newPacket().
+		dword(eventID).
+		dword(state)
+
+	if err := s.send(PACKET_SET_SYSTEM_EVENT_STATE, p, eventID, state); err != nil {
+		return fmt.Errorf("SimConnect_SetSystemEventState for eventID %d state %d error: %s", eventID, state, err)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) RequestDataOnSimObjectType(requestID, defineID, radius, simobjectType DWORD) error {
+	p := newPacket().
+		dword(requestID).
+		dword(defineID).
+		dword(radius).
+		dword(simobjectType)
+
+	if err := s.send(PACKET_REQUEST_DATA_ON_SIMOBJECT_TYPE, p, requestID, defineID, radius, simobjectType); err != nil {
+		return fmt.Errorf(
+			"SimConnect_RequestDataOnSimObjectType for requestID %d defineID %d error: %s",
+			requestID, defineID, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) RequestDataOnSimObject(requestID, defineID, objectID DWORD, period Period, flags DataRequestFlag, origin, interval, limit DWORD) error {
+	p := newPacket().
+		dword(requestID).
+		dword(defineID).
+		dword(objectID).
+		dword(DWORD(period)).
+		dword(DWORD(flags)).
+		dword(origin).
+		dword(interval).
+		dword(limit)
+
+	if err := s.send(PACKET_REQUEST_DATA_ON_SIMOBJECT, p, requestID, defineID, objectID, period, flags, origin, interval, limit); err != nil {
+		return fmt.Errorf(
+			"SimConnect_RequestDataOnSimObject for requestID %d defineID %d error: %s",
+			requestID, defineID, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) SetDataOnSimObject(defineID, simobjectType, flags, arrayCount, size DWORD, buf unsafe.Pointer) error {
+	count := arrayCount
+	if count == 0 {
+		count = 1 // a count of zero is interpreted as one element
+	}
+	total := int(count * size)
+
+	p := newPacket().
+		dword(defineID).
+		dword(simobjectType).
+		dword(flags).
+		dword(arrayCount).
+		dword(size).
+		bytes((*[maxRecvPacketSize]byte)(buf)[:total:total])
+
+	if err := s.send(PACKET_SET_DATA_ON_SIMOBJECT, p, defineID, simobjectType, flags, arrayCount, size); err != nil {
+		return fmt.Errorf(
+			"SimConnect_SetDataOnSimObject for defineID %d error: %s",
+			defineID, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) SubscribeToFacilities(facilityType, requestID DWORD) error {
+	p := newPacket().
+		dword(facilityType).
+		dword(requestID)
+
+	if err := s.send(PACKET_SUBSCRIBE_TO_FACILITIES, p, facilityType, requestID); err != nil {
+		return fmt.Errorf(
+			"SimConnect_SubscribeToFacilities for type %d error: %s",
+			facilityType, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) UnsubscribeToFacilities(facilityType DWORD) error {
+	p := newPacket().
+		dword(facilityType)
+
+	if err := s.send(PACKET_UNSUBSCRIBE_TO_FACILITIES, p, facilityType); err != nil {
+		return fmt.Errorf(
+			"UnsubscribeToFacilities for type %d error: %s",
+			facilityType, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) RequestFacilitiesList(facilityType, requestID DWORD) error {
+	p := newPacket().
+		dword(facilityType).
+		dword(requestID)
+
+	if err := s.send(PACKET_REQUEST_FACILITIES_LIST, p, facilityType, requestID); err != nil {
+		return fmt.Errorf(
+			"SimConnect_RequestFacilitiesList for type %d error: %s",
+			facilityType, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) SubscribeToFacilitiesEX1(facilityType, newElemInRangeRequestID, oldElemOutRangeRequestID DWORD) error {
+	p := newPacket().
+		dword(facilityType).
+		dword(newElemInRangeRequestID).
+		dword(oldElemOutRangeRequestID)
+
+	if err := s.send(PACKET_SUBSCRIBE_TO_FACILITIES_EX1, p, facilityType, newElemInRangeRequestID, oldElemOutRangeRequestID); err != nil {
+		return fmt.Errorf(
+			"SimConnect_SubscribeToFacilities_EX1 for type %d error: %s",
+			facilityType, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) UnsubscribeToFacilitiesEX1(facilityType DWORD, unsubscribeNewInRange, unsubscribeOldOutRange bool) error {
+	p := newPacket().
+		dword(facilityType).
+		dword(boolDWORD(unsubscribeNewInRange)).
+		dword(boolDWORD(unsubscribeOldOutRange))
+
+	if err := s.send(PACKET_UNSUBSCRIBE_TO_FACILITIES_EX1, p, facilityType, unsubscribeNewInRange, unsubscribeOldOutRange); err != nil {
+		return fmt.Errorf(
+			"SimConnect_UnsubscribeToFacilities_EX1 for type %d error: %s",
+			facilityType, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) RequestFacilitiesListEX1(facilityType, requestID DWORD) error {
+	p := newPacket().
+		dword(facilityType).
+		dword(requestID)
+
+	if err := s.send(PACKET_REQUEST_FACILITIES_LIST_EX1, p, facilityType, requestID); err != nil {
+		return fmt.Errorf(
+			"SimConnect_RequestFacilitiesList_EX1 for type %d error: %s",
+			facilityType, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) MapClientEventToSimEvent(eventID DWORD, eventName string) error {
+	p := newPacket().
+		dword(eventID).
+		string(eventName, protocolStringShort)
+
+	if err := s.send(PACKET_MAP_CLIENT_EVENT_TO_SIM_EVENT, p, eventID, eventName); err != nil {
+		return fmt.Errorf(
+			"SimConnect_MapClientEventToSimEvent for eventID %d error: %s",
+			eventID, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) TransmitClientEvent(objectID, eventID, data, groupID, flags DWORD) error {
+	p := newPacket().
+		dword(objectID).
+		dword(eventID).
+		dword(data).
+		dword(groupID).
+		dword(flags)
+
+	if err := s.send(PACKET_TRANSMIT_CLIENT_EVENT, p, objectID, eventID, data, groupID, flags); err != nil {
+		return fmt.Errorf(
+			"SimConnect_TransmitClientEvent for eventID %d error: %s",
+			eventID, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) MenuAddItem(menuItem string, menuEventID, Data DWORD) error {
+	p := newPacket().
+		string(menuItem, protocolStringShort).
+		dword(menuEventID).
+		dword(Data)
+
+	if err := s.send(PACKET_MENU_ADD_ITEM, p, menuItem, menuEventID, Data); err != nil {
+		return fmt.Errorf(
+			"SimConnect_MenuAddItem for menuEventID %d '%s' error: %s",
+			menuEventID, menuItem, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) MenuDeleteItem(menuItem string, menuEventID, Data DWORD) error {
+	p := newPacket().
+		dword(menuEventID)
+
+	if err := s.send(PACKET_MENU_DELETE_ITEM, p, menuItem, menuEventID, Data); err != nil {
+		return fmt.Errorf(
+			"SimConnect_MenuDeleteItem for menuEventID %d error: %s",
+			menuEventID, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) AddClientEventToNotificationGroup(groupID, eventID DWORD) error {
+	p := newPacket().
+		dword(groupID).
+		dword(eventID).
+		dword(0) // bMaskable = FALSE
+
+	if err := s.send(PACKET_ADD_CLIENT_EVENT_TO_NOTIFICATION_GROUP, p, groupID, eventID); err != nil {
+		return fmt.Errorf(
+			"SimConnect_AddClientEventToNotificationGroup for groupID %d eventID %d error: %s",
+			groupID, eventID, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) SetNotificationGroupPriority(groupID, priority DWORD) error {
+	p := newPacket().
+		dword(groupID).
+		dword(priority)
+
+	if err := s.send(PACKET_SET_NOTIFICATION_GROUP_PRIORITY, p, groupID, priority); err != nil {
+		return fmt.Errorf(
+			"SimConnect_SetNotificationGroupPriority for groupID %d priority %d error: %s",
+			groupID, priority, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) MapInputEventToClientEvent(groupID DWORD, inputDefinition string, downEventID, downValue, upEventID, upValue DWORD) error {
+	p := newPacket().
+		dword(groupID).
+		string(inputDefinition, protocolStringShort).
+		dword(downEventID).
+		dword(downValue).
+		dword(upEventID).
+		dword(upValue).
+		dword(0) // bMaskable = FALSE
+
+	if err := s.send(PACKET_MAP_INPUT_EVENT_TO_CLIENT_EVENT, p, groupID, inputDefinition, downEventID, downValue, upEventID, upValue); err != nil {
+		return fmt.Errorf(
+			"SimConnect_MapInputEventToClientEvent for groupID %d '%s' error: %s",
+			groupID, inputDefinition, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) RemoveInputEvent(groupID DWORD, inputDefinition string) error {
+	p := newPacket().
+		dword(groupID).
+		string(inputDefinition, protocolStringShort)
+
+	if err := s.send(PACKET_REMOVE_INPUT_EVENT, p, groupID, inputDefinition); err != nil {
+		return fmt.Errorf(
+			"SimConnect_RemoveInputEvent for groupID %d '%s' error: %s",
+			groupID, inputDefinition, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) ClearInputGroup(groupID DWORD) error {
+	p := newPacket().
+		dword(groupID)
+
+	if err := s.send(PACKET_CLEAR_INPUT_GROUP, p, groupID); err != nil {
+		return fmt.Errorf(
+			"SimConnect_ClearInputGroup for groupID %d error: %s",
+			groupID, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) SetInputGroupPriority(groupID, priority DWORD) error {
+	p := newPacket().
+		dword(groupID).
+		dword(priority)
+
+	if err := s.send(PACKET_SET_INPUT_GROUP_PRIORITY, p, groupID, priority); err != nil {
+		return fmt.Errorf(
+			"SimConnect_SetInputGroupPriority for groupID %d priority %d error: %s",
+			groupID, priority, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) SetInputGroupState(groupID, state DWORD) error {
+	p := newPacket().
+		dword(groupID).
+		dword(state)
+
+	if err := s.send(PACKET_SET_INPUT_GROUP_STATE, p, groupID, state); err != nil {
+		return fmt.Errorf(
+			"SimConnect_SetInputGroupState for groupID %d state %d error: %s",
+			groupID, state, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) MapClientDataNameToID(clientDataName string, clientDataID DWORD) error {
+	p := newPacket().
+		string(clientDataName, protocolStringShort).
+		dword(clientDataID)
+
+	if err := s.send(PACKET_MAP_CLIENT_DATA_NAME_TO_ID, p, clientDataName, clientDataID); err != nil {
+		return fmt.Errorf(
+			"SimConnect_MapClientDataNameToID for clientDataID %d '%s' error: %s",
+			clientDataID, clientDataName, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) CreateClientData(clientDataID, size, flags DWORD) error {
+	p := newPacket().
+		dword(clientDataID).
+		dword(size).
+		dword(flags)
+
+	if err := s.send(PACKET_CREATE_CLIENT_DATA, p, clientDataID, size, flags); err != nil {
+		return fmt.Errorf(
+			"SimConnect_CreateClientData for clientDataID %d error: %s",
+			clientDataID, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) AddToClientDataDefinition(defineID, offset, sizeOrType DWORD, epsilon float32, datumID DWORD) error {
+	p := newPacket().
+		dword(defineID).
+		dword(offset).
+		dword(sizeOrType).
+		float32(epsilon).
+		dword(datumID)
+
+	if err := s.send(PACKET_ADD_TO_CLIENT_DATA_DEFINITION, p, defineID, offset, sizeOrType, epsilon, datumID); err != nil {
+		return fmt.Errorf(
+			"SimConnect_AddToClientDataDefinition for defineID %d error: %s",
+			defineID, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) ClearClientDataDefinition(defineID DWORD) error {
+	p := newPacket().
+		dword(defineID)
+
+	if err := s.send(PACKET_CLEAR_CLIENT_DATA_DEFINITION, p, defineID); err != nil {
+		return fmt.Errorf(
+			"SimConnect_ClearClientDataDefinition for defineID %d error: %s",
+			defineID, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) RequestClientData(clientDataID, requestID, defineID DWORD, period ClientDataPeriod, flags ClientDataRequestFlag, origin, interval, limit DWORD) error {
+	p := newPacket().
+		dword(clientDataID).
+		dword(requestID).
+		dword(defineID).
+		dword(DWORD(period)).
+		dword(DWORD(flags)).
+		dword(origin).
+		dword(interval).
+		dword(limit)
+
+	if err := s.send(PACKET_REQUEST_CLIENT_DATA, p, clientDataID, requestID, defineID, period, flags, origin, interval, limit); err != nil {
+		return fmt.Errorf(
+			"SimConnect_RequestClientData for clientDataID %d requestID %d error: %s",
+			clientDataID, requestID, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) SetClientData(clientDataID, defineID, flags, size DWORD, buf unsafe.Pointer) error {
+	p := newPacket().
+		dword(clientDataID).
+		dword(defineID).
+		dword(flags).
+		dword(0). // dwReserved
+		dword(size).
+		bytes((*[maxRecvPacketSize]byte)(buf)[:size:size])
+
+	if err := s.send(PACKET_SET_CLIENT_DATA, p, clientDataID, defineID, flags, size); err != nil {
+		return fmt.Errorf(
+			"SimConnect_SetClientData for clientDataID %d defineID %d error: %s",
+			clientDataID, defineID, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) AddToFacilityDefinition(defineID DWORD, fieldName string) error {
+	p := newPacket().
+		dword(defineID).
+		string(fieldName, protocolStringShort)
+
+	if err := s.send(PACKET_ADD_TO_FACILITY_DEFINITION, p, defineID, fieldName); err != nil {
+		return fmt.Errorf(
+			"SimConnect_AddToFacilityDefinition for defineID %d '%s' error: %s",
+			defineID, fieldName, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) RequestFacilityData(defineID, requestID DWORD, icao, region string) error {
+	p := newPacket().
+		dword(defineID).
+		dword(requestID).
+		string(icao, protocolStringShort).
+		string(region, protocolStringShort)
+
+	if err := s.send(PACKET_REQUEST_FACILITY_DATA, p, defineID, requestID, icao, region); err != nil {
+		return fmt.Errorf(
+			"SimConnect_RequestFacilityData for defineID %d requestID %d '%s' error: %s",
+			defineID, requestID, icao, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) AICreateParkedATCAircraft(containerTitle, tailNumber, airportID string, requestID DWORD) error {
+	p := newPacket().
+		string(containerTitle, protocolStringShort).
+		string(tailNumber, 12).
+		string(airportID, 5).
+		dword(requestID)
+
+	if err := s.send(PACKET_AI_CREATE_PARKED_ATC_AIRCRAFT, p, containerTitle, tailNumber, airportID, requestID); err != nil {
+		return fmt.Errorf(
+			"SimConnect_AICreateParkedATCAircraft for '%s' at %s error: %s",
+			containerTitle, airportID, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) AICreateEnrouteATCAircraft(containerTitle, tailNumber string, flightNumber int32, flightPlanPath string, flightPlanPosition float64, touchAndGo bool, requestID DWORD) error {
+	p := newPacket().
+		string(containerTitle, protocolStringShort).
+		string(tailNumber, 12).
+		int32(flightNumber).
+		string(flightPlanPath, protocolStringPath).
+		float64(flightPlanPosition).
+		dword(boolDWORD(touchAndGo)).
+		dword(requestID)
+
+	if err := s.send(PACKET_AI_CREATE_ENROUTE_ATC_AIRCRAFT, p, containerTitle, tailNumber, flightNumber, flightPlanPath, flightPlanPosition, touchAndGo, requestID); err != nil {
+		return fmt.Errorf(
+			"SimConnect_AICreateEnrouteATCAircraft for '%s' error: %s",
+			containerTitle, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) AICreateNonATCAircraft(containerTitle, tailNumber string, initPos DataInitPosition, requestID DWORD) error {
+	p := newPacket().
+		string(containerTitle, protocolStringShort).
+		string(tailNumber, 12).
+		initPosition(initPos).
+		dword(requestID)
+
+	if err := s.send(PACKET_AI_CREATE_NON_ATC_AIRCRAFT, p, containerTitle, tailNumber, initPos, requestID); err != nil {
+		return fmt.Errorf(
+			"SimConnect_AICreateNonATCAircraft for '%s' error: %s",
+			containerTitle, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) AICreateSimulatedObject(containerTitle string, initPos DataInitPosition, requestID DWORD) error {
+	p := newPacket().
+		string(containerTitle, protocolStringShort).
+		initPosition(initPos).
+		dword(requestID)
+
+	if err := s.send(PACKET_AI_CREATE_SIMULATED_OBJECT, p, containerTitle, initPos, requestID); err != nil {
+		return fmt.Errorf(
+			"SimConnect_AICreateSimulatedObject for '%s' error: %s",
+			containerTitle, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) AIReleaseControl(objectID, requestID DWORD) error {
+	p := newPacket().
+		dword(objectID).
+		dword(requestID)
+
+	if err := s.send(PACKET_AI_RELEASE_CONTROL, p, objectID, requestID); err != nil {
+		return fmt.Errorf(
+			"SimConnect_AIReleaseControl for objectID %d error: %s",
+			objectID, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) AIRemoveObject(objectID, requestID DWORD) error {
+	p := newPacket().
+		dword(objectID).
+		dword(requestID)
+
+	if err := s.send(PACKET_AI_REMOVE_OBJECT, p, objectID, requestID); err != nil {
+		return fmt.Errorf(
+			"SimConnect_AIRemoveObject for objectID %d error: %s",
+			objectID, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) AISetAircraftFlightPlan(objectID DWORD, flightPlanPath string, requestID DWORD) error {
+	p := newPacket().
+		dword(objectID).
+		string(flightPlanPath, protocolStringPath).
+		dword(requestID)
+
+	if err := s.send(PACKET_AI_SET_AIRCRAFT_FLIGHT_PLAN, p, objectID, flightPlanPath, requestID); err != nil {
+		return fmt.Errorf(
+			"SimConnect_AISetAircraftFlightPlan for objectID %d '%s' error: %s",
+			objectID, flightPlanPath, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) RequestSystemState(requestID DWORD, state string) error {
+	p := newPacket().
+		dword(requestID).
+		string(state, protocolStringShort)
+
+	if err := s.send(PACKET_REQUEST_SYSTEM_STATE, p, requestID, state); err != nil {
+		return fmt.Errorf(
+			"SimConnect_RequestSystemState for requestID %d '%s' error: %s",
+			requestID, state, err,
+		)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) FlightLoad(fileName string) error {
+	p := newPacket().
+		string(fileName, protocolStringPath)
+
+	if err := s.send(PACKET_FLIGHT_LOAD, p, fileName); err != nil {
+		return fmt.Errorf("SimConnect_FlightLoad for '%s' error: %s", fileName, err)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) FlightSave(fileName, title, description string, flags DWORD) error {
+	p := newPacket().
+		string(fileName, protocolStringPath).
+		string(title, protocolStringPath).
+		string(description, 2048).
+		dword(flags)
+
+	if err := s.send(PACKET_FLIGHT_SAVE, p, fileName, title, description, flags); err != nil {
+		return fmt.Errorf("SimConnect_FlightSave for '%s' error: %s", fileName, err)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) FlightPlanLoad(fileName string) error {
+	p := newPacket().
+		string(fileName, protocolStringPath)
+
+	if err := s.send(PACKET_FLIGHT_PLAN_LOAD, p, fileName); err != nil {
+		return fmt.Errorf("SimConnect_FlightPlanLoad for '%s' error: %s", fileName, err)
+	}
+	return nil
+}
+
+func (s *NetSimConnect) ShowText(textType DWORD, duration float64, eventID DWORD, text string) error {
+	_text := []byte(text + "\x00")
+
+	p := newPacket().
+		dword(textType).
+		float32(float32(duration)).
+		dword(eventID).
+		dword(DWORD(len(_text))).
+		bytes(_text)
+
+	if err := s.send(PACKET_TEXT, p, textType, duration, eventID, text); err != nil {
+		return fmt.Errorf(
+			"SimConnect_Text for eventID %d textType %d text '%s' error: %s",
+			eventID, textType, text, err,
+		)
+	}
+	return nil
+}
+
+// GetNextDispatch returns the next received message, or E_FAIL when none is
+// pending. the returned memory is owned by the caller.
+func (s *NetSimConnect) GetNextDispatch() (unsafe.Pointer, int32, error) {
+	buf, err := s.NextDispatch()
+	if err != nil {
+		return nil, -1, err
+	}
+	if buf == nil {
+		fail := E_FAIL
+		return nil, int32(fail), nil
+	}
+
+	return unsafe.Pointer(&buf[0]), 0, nil
+}
+
+// NextDispatch returns the next received message, or nil when none is pending.
+func (s *NetSimConnect) NextDispatch() ([]byte, error) {
+	buf := s.pending
+	s.pending = nil
+	if buf != nil {
+		return buf, nil
+	}
+
+	select {
+	case b, ok := <-s.recv:
+		if !ok {
+			return nil, fmt.Errorf("SimConnect_GetNextDispatch error: connection closed: %s", s.readErr())
+		}
+		return b, nil
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,66 @@
+package simconnect_test
+
+import (
+	"encoding/binary"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/supersidor/msfs2020-go/simconnect"
+)
+
+// stalledServer answers the open handshake and then stops reading, like a
+// simulator that hangs.
+func stalledServer(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		t.Cleanup(func() { conn.Close() })
+
+		var open [12]byte
+		binary.LittleEndian.PutUint32(open[0:], 12)
+		binary.LittleEndian.PutUint32(open[4:], 4)
+		binary.LittleEndian.PutUint32(open[8:], uint32(simconnect.RECV_ID_OPEN))
+		conn.Write(open[:])
+	}()
+
+	return ln.Addr().String()
+}
+
+func TestWriteTimeout(t *testing.T) {
+	d := &simconnect.Dialer{Timeout: 200 * time.Millisecond}
+	s, err := d.Dial("stalled", stalledServer(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// fill the socket buffers until a write times out
+	failed := make(chan error, 1)
+	go func() {
+		for {
+			if err := s.MapClientDataNameToID("area", 1); err != nil {
+				failed <- err
+				return
+			}
+		}
+	}()
+
+	select {
+	case err := <-failed:
+		if !strings.Contains(err.Error(), "i/o timeout") {
+			t.Errorf("got %v, want a timeout", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("writes to a stalled server still block")
+	}
+}
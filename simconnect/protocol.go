@@ -0,0 +1,146 @@
+package simconnect
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// SimConnect network protocol, as spoken by SimConnect.dll to the simulator
+// when SimConnect.xml/SimConnect.cfg point it at a remote address.
+//
+// every packet sent to the server starts with a 16 byte header:
+//
+//   DWORD size      // total packet size including this header
+//   DWORD protocol  // PROTOCOL_*
+//   DWORD type      // PACKET_* | 0xF0000000
+//   DWORD sendID    // sequence number, reported back in RecvException.SendID
+//
+// packets received from the server are laid out exactly like the SIMCONNECT_RECV
+// structures returned by SimConnect_GetNextDispatch.
+
+const (
+	PROTOCOL_FSX_RTM    DWORD = 2
+	PROTOCOL_FSX_SP1    DWORD = 3
+	PROTOCOL_FSX_SP2    DWORD = 4 // also accepted by msfs2020
+	PROTOCOL_KITTYHAWK  DWORD = 5 // msfs2020
+	PROTOCOL_DEFAULT          = PROTOCOL_FSX_SP2
+	packetHeaderSize          = 16
+	packetTypeMask      DWORD = 0xF0000000
+	maxRecvPacketSize         = 1 << 20
+	protocolStringShort       = 256
+	protocolStringPath        = 260
+)
+
+// packet types of the client to server messages, one per SimConnect_* function
+const (
+	PACKET_OPEN                                   DWORD = 0x01
+	PACKET_MAP_CLIENT_EVENT_TO_SIM_EVENT          DWORD = 0x04
+	PACKET_TRANSMIT_CLIENT_EVENT                  DWORD = 0x05
+	PACKET_SET_SYSTEM_EVENT_STATE                 DWORD = 0x06
+	PACKET_ADD_CLIENT_EVENT_TO_NOTIFICATION_GROUP DWORD = 0x07
+	PACKET_REMOVE_CLIENT_EVENT                    DWORD = 0x08
+	PACKET_SET_NOTIFICATION_GROUP_PRIORITY        DWORD = 0x09
+	PACKET_CLEAR_NOTIFICATION_GROUP               DWORD = 0x0A
+	PACKET_REQUEST_NOTIFICATION_GROUP             DWORD = 0x0B
+	PACKET_ADD_TO_DATA_DEFINITION                 DWORD = 0x0C
+	PACKET_CLEAR_DATA_DEFINITION                  DWORD = 0x0D
+	PACKET_REQUEST_DATA_ON_SIMOBJECT              DWORD = 0x0E
+	PACKET_REQUEST_DATA_ON_SIMOBJECT_TYPE         DWORD = 0x0F
+	PACKET_SET_DATA_ON_SIMOBJECT                  DWORD = 0x10
+	PACKET_MAP_INPUT_EVENT_TO_CLIENT_EVENT        DWORD = 0x11
+	PACKET_SET_INPUT_GROUP_PRIORITY               DWORD = 0x12
+	PACKET_REMOVE_INPUT_EVENT                     DWORD = 0x13
+	PACKET_CLEAR_INPUT_GROUP                      DWORD = 0x14
+	PACKET_SET_INPUT_GROUP_STATE                  DWORD = 0x15
+	PACKET_REQUEST_RESERVED_KEY                   DWORD = 0x16
+	PACKET_SUBSCRIBE_TO_SYSTEM_EVENT              DWORD = 0x17
+	PACKET_UNSUBSCRIBE_FROM_SYSTEM_EVENT          DWORD = 0x18
+	PACKET_AI_CREATE_PARKED_ATC_AIRCRAFT          DWORD = 0x27
+	PACKET_AI_CREATE_ENROUTE_ATC_AIRCRAFT         DWORD = 0x28
+	PACKET_AI_CREATE_NON_ATC_AIRCRAFT             DWORD = 0x29
+	PACKET_AI_CREATE_SIMULATED_OBJECT             DWORD = 0x2A
+	PACKET_AI_RELEASE_CONTROL                     DWORD = 0x2B
+	PACKET_AI_REMOVE_OBJECT                       DWORD = 0x2C
+	PACKET_AI_SET_AIRCRAFT_FLIGHT_PLAN            DWORD = 0x2D
+	PACKET_MENU_ADD_ITEM                          DWORD = 0x31
+	PACKET_MENU_DELETE_ITEM                       DWORD = 0x32
+	PACKET_REQUEST_SYSTEM_STATE                   DWORD = 0x35
+	PACKET_SET_SYSTEM_STATE                       DWORD = 0x36
+	PACKET_MAP_CLIENT_DATA_NAME_TO_ID             DWORD = 0x37
+	PACKET_CREATE_CLIENT_DATA                     DWORD = 0x38
+	PACKET_ADD_TO_CLIENT_DATA_DEFINITION          DWORD = 0x39
+	PACKET_CLEAR_CLIENT_DATA_DEFINITION           DWORD = 0x3A
+	PACKET_REQUEST_CLIENT_DATA                    DWORD = 0x3B
+	PACKET_SET_CLIENT_DATA                        DWORD = 0x3C
+	PACKET_FLIGHT_LOAD                            DWORD = 0x3D
+	PACKET_FLIGHT_SAVE                            DWORD = 0x3E
+	PACKET_FLIGHT_PLAN_LOAD                       DWORD = 0x3F
+	PACKET_TEXT                                   DWORD = 0x40
+	PACKET_SUBSCRIBE_TO_FACILITIES                DWORD = 0x41
+	PACKET_UNSUBSCRIBE_TO_FACILITIES              DWORD = 0x42
+	PACKET_REQUEST_FACILITIES_LIST                DWORD = 0x43
+	PACKET_ADD_TO_FACILITY_DEFINITION             DWORD = 0x45
+	PACKET_REQUEST_FACILITY_DATA                  DWORD = 0x46
+	PACKET_SUBSCRIBE_TO_FACILITIES_EX1            DWORD = 0x47
+	PACKET_UNSUBSCRIBE_TO_FACILITIES_EX1          DWORD = 0x48
+	PACKET_REQUEST_FACILITIES_LIST_EX1            DWORD = 0x49
+)
+
+// packet builds the body of a client to server message. the header is
+// filled in by the connection when the packet is sent.
+type packet struct {
+	buf []byte
+}
+
+func newPacket() *packet {
+	return &packet{buf: make([]byte, packetHeaderSize, 128)}
+}
+
+func (p *packet) dword(v DWORD) *packet {
+	p.buf = append(p.buf, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(p.buf[len(p.buf)-4:], uint32(v))
+	return p
+}
+
+func (p *packet) int32(v int32) *packet {
+	return p.dword(DWORD(v))
+}
+
+func (p *packet) uint64(v uint64) *packet {
+	p.buf = append(p.buf, 0, 0, 0, 0, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint64(p.buf[len(p.buf)-8:], v)
+	return p
+}
+
+func (p *packet) float32(v float32) *packet {
+	return p.dword(DWORD(math.Float32bits(v)))
+}
+
+func (p *packet) float64(v float64) *packet {
+	p.buf = append(p.buf, 0, 0, 0, 0, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint64(p.buf[len(p.buf)-8:], math.Float64bits(v))
+	return p
+}
+
+// string appends s as a zero padded fixed size field, truncating it if needed
+// so the terminating zero always fits.
+func (p *packet) string(s string, size int) *packet {
+	field := make([]byte, size)
+	copy(field[:size-1], s)
+	p.buf = append(p.buf, field...)
+	return p
+}
+
+func (p *packet) bytes(b []byte) *packet {
+	p.buf = append(p.buf, b...)
+	return p
+}
+
+// finish writes the packet header and returns the wire bytes.
+func (p *packet) finish(protocol, packetType, sendID DWORD) []byte {
+	binary.LittleEndian.PutUint32(p.buf[0:], uint32(len(p.buf)))
+	binary.LittleEndian.PutUint32(p.buf[4:], uint32(protocol))
+	binary.LittleEndian.PutUint32(p.buf[8:], uint32(packetType|packetTypeMask))
+	binary.LittleEndian.PutUint32(p.buf[12:], uint32(sendID))
+	return p.buf
+}
@@ -0,0 +1,252 @@
+package simconnect
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// a recording is a gzip compressed stream of recordingMagic followed by
+// records:
+//
+//	byte       kind // record*
+//	uvarint    time // microseconds since the previous record
+//	uvarint    size
+//	[size]byte data
+//
+// recordMessage holds a message as returned by NextDispatch, recordDatum the
+// defineID, name, unit, data type, epsilon and datumID of an
+// AddToDataDefinition call, recordClearDefinition the defineID of a
+// ClearDataDefinition call and recordSystemEvent the eventID and name of a
+// SubscribeToSystemEvent call.
+const recordingMagic = "SCRC0002"
+
+const (
+	recordMessage byte = iota + 1
+	recordDatum
+	recordSystemEvent
+	recordClearDefinition
+)
+
+// Recorder is a Client writing a recording of every message read through
+// NextDispatch or GetNextDispatch, with the time it arrived, along with the
+// data definitions and system event subscriptions made through it. NewReplay
+// plays it back.
+//
+// like the Supervisor it keeps the IDs and data definitions itself, every
+// call is passed on to the recorded client.
+type Recorder struct {
+	Client
+	registry
+
+	mu      sync.Mutex
+	w       *bufio.Writer
+	gz      *gzip.Writer
+	last    time.Time // time of the last record
+	flushed time.Time
+	err     error // first write error
+}
+
+// NewRecorder records the messages of c to w, w is not closed by Close.
+func NewRecorder(c Client, w io.Writer) (*Recorder, error) {
+	gz := gzip.NewWriter(w)
+	r := &Recorder{
+		Client:   c,
+		registry: newRegistry(),
+		w:        bufio.NewWriter(gz),
+		gz:       gz,
+		last:     time.Now(),
+	}
+	if _, err := r.w.WriteString(recordingMagic); err != nil {
+		return nil, fmt.Errorf("recording error: %s", err)
+	}
+	return r, nil
+}
+
+// write appends a record.
+func (r *Recorder) write(kind byte, data []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return
+	}
+
+	elapsed := time.Since(r.last) / time.Microsecond
+	r.last = r.last.Add(elapsed * time.Microsecond)
+
+	var head [1 + 2*binary.MaxVarintLen64]byte
+	head[0] = kind
+	n := 1
+	n += binary.PutUvarint(head[n:], uint64(elapsed))
+	n += binary.PutUvarint(head[n:], uint64(len(data)))
+
+	if _, err := r.w.Write(head[:n]); err != nil {
+		r.err = err
+		return
+	}
+	if _, err := r.w.Write(data); err != nil {
+		r.err = err
+	}
+}
+
+// flush writes the buffered records through to w while no messages are
+// pending, at most once a second to keep the compression effective.
+func (r *Recorder) flush() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil || r.w.Buffered() == 0 || time.Since(r.flushed) < time.Second {
+		return
+	}
+	r.flushed = time.Now()
+	if r.err = r.w.Flush(); r.err == nil {
+		r.err = r.gz.Flush()
+	}
+}
+
+func (r *Recorder) GetDefineID(a interface{}) DWORD {
+	return r.registry.GetDefineID(a)
+}
+
+func (r *Recorder) GetRequestID() DWORD {
+	return r.registry.GetRequestID()
+}
+
+func (r *Recorder) GetEventID() DWORD {
+	return r.registry.GetEventID()
+}
+
+func (r *Recorder) GetGroupID() DWORD {
+	return r.registry.GetGroupID()
+}
+
+func (r *Recorder) GetClientDataID() DWORD {
+	return r.registry.GetClientDataID()
+}
+
+func (r *Recorder) Decode(buf []byte) (interface{}, error) {
+	return r.registry.Decode(buf)
+}
+
+func (r *Recorder) SentCall(sendID DWORD) (SentCall, bool) {
+	return r.Client.SentCall(sendID)
+}
+
+func (r *Recorder) RegisterDataDefinition(a interface{}) error {
+	return r.registerDataDefinition(r, a)
+}
+
+func (r *Recorder) SetData(objectID DWORD, v interface{}) error {
+	return r.setData(r, objectID, v)
+}
+
+func (r *Recorder) SendEvent(event KeyEvent, data ...DWORD) error {
+	return r.sendEvent(r, event, data...)
+}
+
+func (r *Recorder) RegisterClientDataDefinition(a interface{}) error {
+	return r.registerClientDataDefinition(r, a)
+}
+
+func (r *Recorder) WriteClientData(clientDataID DWORD, v interface{}) error {
+	return r.writeClientData(r, clientDataID, v)
+}
+
+func (r *Recorder) RegisterFacilityDefinition(a interface{}) error {
+	return r.registerFacilityDefinition(r, a)
+}
+
+func (r *Recorder) AddToDataDefinition(defineID DWORD, name, unit string, dataType DWORD, epsilon float32, datumID DWORD) error {
+	if err := r.Client.AddToDataDefinition(defineID, name, unit, dataType, epsilon, datumID); err != nil {
+		return err
+	}
+
+	p := newPacket().
+		dword(defineID).
+		string(name, protocolStringShort).
+		string(unit, protocolStringShort).
+		dword(dataType).
+		float32(epsilon).
+		dword(datumID)
+	r.write(recordDatum, p.buf[packetHeaderSize:])
+	return nil
+}
+
+func (r *Recorder) ClearDataDefinition(defineID DWORD) error {
+	if err := r.Client.ClearDataDefinition(defineID); err != nil {
+		return err
+	}
+
+	p := newPacket().
+		dword(defineID)
+	r.write(recordClearDefinition, p.buf[packetHeaderSize:])
+	return nil
+}
+
+func (r *Recorder) SubscribeToSystemEvent(eventID DWORD, eventName string) error {
+	if err := r.Client.SubscribeToSystemEvent(eventID, eventName); err != nil {
+		return err
+	}
+
+	p := newPacket().
+		dword(eventID).
+		string(eventName, protocolStringShort)
+	r.write(recordSystemEvent, p.buf[packetHeaderSize:])
+	return nil
+}
+
+func (r *Recorder) NextDispatch() ([]byte, error) {
+	buf, err := r.Client.NextDispatch()
+	if err != nil {
+		return nil, err
+	}
+	if buf == nil {
+		r.flush()
+		return nil, nil
+	}
+
+	r.write(recordMessage, buf)
+	return buf, nil
+}
+
+func (r *Recorder) GetNextDispatch() (unsafe.Pointer, int32, error) {
+	ppData, r1, err := r.Client.GetNextDispatch()
+	if err != nil {
+		return nil, r1, err
+	}
+	if ppData == nil {
+		r.flush()
+		return nil, r1, nil
+	}
+
+	size := (*Recv)(ppData).Size
+	r.write(recordMessage, (*[1 << 30]byte)(ppData)[:size:size])
+	return ppData, r1, nil
+}
+
+// Close closes the recorded client and completes the recording, it returns
+// the first error writing it.
+func (r *Recorder) Close() error {
+	err := r.Client.Close()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err == nil {
+		r.err = r.w.Flush()
+	}
+	if r.err == nil {
+		r.err = r.gz.Close()
+	}
+	if r.err != nil {
+		return fmt.Errorf("recording error: %s", r.err)
+	}
+	return err
+}
+
+var _ Client = (*Recorder)(nil)
@@ -0,0 +1,328 @@
+package simconnect
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+// registry hands out the client side IDs for data definitions, requests,
+// events, notification groups and client data areas, and keeps the
+// registered data definitions. it is shared by every transport and safe for
+// concurrent use.
+type registry struct {
+	mu sync.Mutex
+
+	defineIDs    map[reflect.Type]DWORD
+	lastDefineID DWORD
+
+	lastRequestID    DWORD
+	lastEventID      DWORD
+	lastGroupID      DWORD
+	lastClientDataID DWORD
+
+	definitions         map[DWORD]*dataDefinition
+	clientDefinitions   map[DWORD]*clientDataDefinition
+	facilityDefinitions map[DWORD]*facilityDefinition
+
+	keyEventMu sync.Mutex // held while a key event is mapped
+	keyEvents  map[KeyEvent]DWORD
+
+	sentMu sync.Mutex
+	sent   [sentCalls]SentCall
+}
+
+func newRegistry() registry {
+	return registry{
+		defineIDs:           map[reflect.Type]DWORD{},
+		definitions:         map[DWORD]*dataDefinition{},
+		clientDefinitions:   map[DWORD]*clientDataDefinition{},
+		facilityDefinitions: map[DWORD]*facilityDefinition{},
+		keyEvents:           map[KeyEvent]DWORD{},
+	}
+}
+
+// next returns *last and increments it, the registry lock has to be held.
+func next(last *DWORD) DWORD {
+	id := *last
+	*last += 1
+	return id
+}
+
+// GetDefineID returns the data definition ID of the struct type of a, a
+// pointer to a struct or a struct. every type gets its own ID, even types with
+// the same name from different packages.
+func (s *registry) GetDefineID(a interface{}) DWORD {
+	t := reflect.TypeOf(a)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, ok := s.defineIDs[t]
+	if !ok {
+		id = next(&s.lastDefineID)
+		s.defineIDs[t] = id
+	}
+	return id
+}
+
+// GetRequestID returns a new request ID, e.g. for RequestDataOnSimObject.
+// a definition can have any number of requests in flight, each with its own
+// request ID.
+func (s *registry) GetRequestID() DWORD {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return next(&s.lastRequestID)
+}
+
+// GetEventID returns a new client event ID.
+func (s *registry) GetEventID() DWORD {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return next(&s.lastEventID)
+}
+
+// GetGroupID returns a new notification or input group ID.
+func (s *registry) GetGroupID() DWORD {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return next(&s.lastGroupID)
+}
+
+// GetClientDataID returns a new client data area ID.
+func (s *registry) GetClientDataID() DWORD {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return next(&s.lastClientDataID)
+}
+
+func (s *registry) definition(defineID DWORD) (*dataDefinition, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	def, ok := s.definitions[defineID]
+	return def, ok
+}
+
+func (s *registry) clientDefinition(defineID DWORD) (*clientDataDefinition, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	def, ok := s.clientDefinitions[defineID]
+	return def, ok
+}
+
+func (s *registry) facilityDefinition(defineID DWORD) (*facilityDefinition, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	def, ok := s.facilityDefinitions[defineID]
+	return def, ok
+}
+
+type dataDefinitionAdder interface {
+	AddToDataDefinition(defineID DWORD, name, unit string, dataType DWORD, epsilon float32, datumID DWORD) error
+	ClearDataDefinition(defineID DWORD) error
+}
+
+// registerDataDefinition adds the datums of a to its data definition. types
+// that are already registered are skipped. when adding a datum fails the
+// definition is cleared, a retry starts over.
+func (s *registry) registerDataDefinition(c dataDefinitionAdder, a interface{}) error {
+	def, err := parseDataDefinition(a)
+	if err != nil {
+		return err
+	}
+
+	defineID := s.GetDefineID(a)
+
+	s.mu.Lock()
+	if _, ok := s.definitions[defineID]; ok {
+		s.mu.Unlock()
+		return nil
+	}
+	if _, ok := s.clientDefinitions[defineID]; ok {
+		s.mu.Unlock()
+		return fmt.Errorf("%s is registered as client data definition", def.typ)
+	}
+	s.definitions[defineID] = def
+	s.mu.Unlock()
+
+	for _, d := range def.datums {
+		if err := c.AddToDataDefinition(defineID, d.name, d.unit, d.dataType, d.epsilon, d.datumID); err != nil {
+			c.ClearDataDefinition(defineID)
+			s.mu.Lock()
+			delete(s.definitions, defineID)
+			s.mu.Unlock()
+			return err
+		}
+	}
+
+	return nil
+}
+
+type dataSetter interface {
+	SetDataOnSimObject(defineID, objectID, flags, arrayCount, size DWORD, buf unsafe.Pointer) error
+}
+
+// setData writes v, a registered data definition struct or a pointer to it,
+// to objectID.
+func (s *registry) setData(c dataSetter, objectID DWORD, v interface{}) error {
+	t := reflect.TypeOf(v)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	s.mu.Lock()
+	defineID, ok := s.defineIDs[t]
+	def := s.definitions[defineID]
+	s.mu.Unlock()
+	if !ok || def == nil {
+		return fmt.Errorf("%s is not registered, call RegisterDataDefinition first", t)
+	}
+
+	data, arrayCount, size, err := def.marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.SetDataOnSimObject(defineID, objectID, 0, arrayCount, size, unsafe.Pointer(&data[0]))
+}
+
+type eventTransmitter interface {
+	MapClientEventToSimEvent(eventID DWORD, eventName string) error
+	TransmitClientEvent(objectID, eventID, data, groupID, flags DWORD) error
+}
+
+// sendEvent transmits event to the user aircraft with the highest priority,
+// the client event ID it is mapped to is cached.
+func (s *registry) sendEvent(c eventTransmitter, event KeyEvent, data ...DWORD) error {
+	if len(data) > 1 {
+		return fmt.Errorf("%s takes at most one parameter, got %d", event, len(data))
+	}
+	var param DWORD
+	if len(data) == 1 {
+		param = data[0]
+	}
+
+	s.keyEventMu.Lock()
+	eventID, ok := s.keyEvents[event]
+	if !ok {
+		eventID = s.GetEventID()
+		if err := c.MapClientEventToSimEvent(eventID, event.name); err != nil {
+			s.keyEventMu.Unlock()
+			return err
+		}
+		s.keyEvents[event] = eventID
+	}
+	s.keyEventMu.Unlock()
+
+	return c.TransmitClientEvent(OBJECT_ID_USER, eventID, param, GROUP_PRIORITY_HIGHEST, EVENT_FLAG_GROUPID_IS_PRIORITY)
+}
+
+type clientDataDefinitionAdder interface {
+	AddToClientDataDefinition(defineID, offset, sizeOrType DWORD, epsilon float32, datumID DWORD) error
+}
+
+// registerClientDataDefinition adds the datums of a to its client data
+// definition. types that are already registered are skipped.
+func (s *registry) registerClientDataDefinition(c clientDataDefinitionAdder, a interface{}) error {
+	def, err := parseClientDataDefinition(a)
+	if err != nil {
+		return err
+	}
+
+	defineID := s.GetDefineID(a)
+
+	s.mu.Lock()
+	if _, ok := s.clientDefinitions[defineID]; ok {
+		s.mu.Unlock()
+		return nil
+	}
+	if _, ok := s.definitions[defineID]; ok {
+		s.mu.Unlock()
+		return fmt.Errorf("%s is registered as simobject data definition", def.typ)
+	}
+	s.clientDefinitions[defineID] = def
+	s.mu.Unlock()
+
+	for _, d := range def.datums {
+		if err := c.AddToClientDataDefinition(defineID, d.offset, d.sizeOrType, d.epsilon, d.datumID); err != nil {
+			s.mu.Lock()
+			delete(s.clientDefinitions, defineID)
+			s.mu.Unlock()
+			return err
+		}
+	}
+
+	return nil
+}
+
+type clientDataSetter interface {
+	SetClientData(clientDataID, defineID, flags, size DWORD, buf unsafe.Pointer) error
+}
+
+// writeClientData writes v, a registered client data definition struct or a
+// pointer to it, to the client data area clientDataID.
+func (s *registry) writeClientData(c clientDataSetter, clientDataID DWORD, v interface{}) error {
+	t := reflect.TypeOf(v)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	s.mu.Lock()
+	defineID, ok := s.defineIDs[t]
+	def := s.clientDefinitions[defineID]
+	s.mu.Unlock()
+	if !ok || def == nil {
+		return fmt.Errorf("%s is not registered, call RegisterClientDataDefinition first", t)
+	}
+
+	data, err := def.marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.SetClientData(clientDataID, defineID, CLIENT_DATA_SET_FLAG_DEFAULT, DWORD(len(data)), unsafe.Pointer(&data[0]))
+}
+
+type facilityDefinitionAdder interface {
+	AddToFacilityDefinition(defineID DWORD, fieldName string) error
+}
+
+// registerFacilityDefinition adds the OPEN, CLOSE and field entries of a to
+// its facility definition. types that are already registered are skipped.
+func (s *registry) registerFacilityDefinition(c facilityDefinitionAdder, a interface{}) error {
+	def, err := parseFacilityDefinition(a)
+	if err != nil {
+		return err
+	}
+
+	defineID := s.GetDefineID(a)
+
+	s.mu.Lock()
+	if _, ok := s.facilityDefinitions[defineID]; ok {
+		s.mu.Unlock()
+		return nil
+	}
+	if _, ok := s.definitions[defineID]; ok {
+		s.mu.Unlock()
+		return fmt.Errorf("%s is registered as simobject data definition", def.root.typ)
+	}
+	if _, ok := s.clientDefinitions[defineID]; ok {
+		s.mu.Unlock()
+		return fmt.Errorf("%s is registered as client data definition", def.root.typ)
+	}
+	s.facilityDefinitions[defineID] = def
+	s.mu.Unlock()
+
+	for _, entry := range def.entries {
+		if err := c.AddToFacilityDefinition(defineID, entry); err != nil {
+			s.mu.Lock()
+			delete(s.facilityDefinitions, defineID)
+			s.mu.Unlock()
+			return err
+		}
+	}
+
+	return nil
+}
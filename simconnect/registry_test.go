@@ -0,0 +1,76 @@
+package simconnect_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/supersidor/msfs2020-go/simconnect"
+	"github.com/supersidor/msfs2020-go/simconnect/simtest"
+)
+
+// failingClient fails the AddToDataDefinition call number failAt.
+type failingClient struct {
+	*simconnect.NetSimConnect
+	failAt int
+	calls  int
+}
+
+func (c *failingClient) AddToDataDefinition(defineID simconnect.DWORD, name, unit string, dataType simconnect.DWORD, epsilon float32, datumID simconnect.DWORD) error {
+	c.calls++
+	if c.calls == c.failAt {
+		return errors.New("failed")
+	}
+	return c.NetSimConnect.AddToDataDefinition(defineID, name, unit, dataType, epsilon, datumID)
+}
+
+type position struct {
+	simconnect.RecvSimobjectDataByType
+	Latitude  float64 `name:"PLANE LATITUDE" unit:"degrees"`
+	Longitude float64 `name:"PLANE LONGITUDE" unit:"degrees"`
+	Altitude  float64 `name:"PLANE ALTITUDE" unit:"feet"`
+}
+
+func TestRegisterDataDefinitionRetry(t *testing.T) {
+	srv := simtest.NewServer()
+	defer srv.Close()
+	srv.SetSimVar(simtest.UserObjectID, "PLANE LATITUDE", 47.5)
+	srv.SetSimVar(simtest.UserObjectID, "PLANE LONGITUDE", 8.5)
+	srv.SetSimVar(simtest.UserObjectID, "PLANE ALTITUDE", 1500)
+
+	s, err := simconnect.Dial("registry", srv.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// the recorder registers through the failing client
+	c := &failingClient{NetSimConnect: s, failAt: 3}
+	r, err := simconnect.NewRecorder(c, ioutil.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.RegisterDataDefinition(&position{}); err == nil {
+		t.Fatal("registering succeeded although adding the altitude failed")
+	}
+	if err := r.RegisterDataDefinition(&position{}); err != nil {
+		t.Fatal(err)
+	}
+
+	d := simconnect.NewDispatcher(r)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	d.Start(ctx)
+
+	// without clearing, the definition would start with the datums of the
+	// failed attempt
+	var p position
+	if err := d.RequestOnce(withTimeout(t), &p, simconnect.OBJECT_ID_USER); err != nil {
+		t.Fatal(err)
+	}
+	if p.Latitude != 47.5 || p.Longitude != 8.5 || p.Altitude != 1500 {
+		t.Errorf("got %v %v %v, want 47.5 8.5 1500", p.Latitude, p.Longitude, p.Altitude)
+	}
+}
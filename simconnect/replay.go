@@ -0,0 +1,558 @@
+package simconnect
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// ErrReplayEnded is returned by NextDispatch and GetNextDispatch of a Replay
+// once every recorded message was played back. a Supervisor stops on it
+// instead of reconnecting.
+var ErrReplayEnded = errors.New("end of the recording")
+
+// recordedDatum is a datum of a data definition, simobject data is replayed
+// to data definitions with the same datums.
+type recordedDatum struct {
+	name     string
+	unit     string
+	dataType DWORD
+	epsilon  float32
+	datumID  DWORD
+}
+
+// replayRequest is a data request made to a Replay.
+type replayRequest struct {
+	requestID DWORD
+	defineID  DWORD
+	once      bool // PERIOD_ONCE or by type, answered by a single message or set of entries
+}
+
+// Replay is a Client playing back a recording written by a Recorder, e.g. to
+// run against a recorded flight without the simulator.
+//
+// the client registers its data definitions and makes its requests as usual:
+// simobject data is played back to the requests for data definitions with
+// the same datums, recorded requests are taken in the order they were first
+// answered and skipped if no such request was made. a PERIOD_ONCE or by type
+// request takes a single recorded answer. system events are played back to
+// the subscriptions of the same name, every other message as it was
+// recorded. the remaining calls are accepted and ignored.
+type Replay struct {
+	registry
+
+	// Speed is the playback speed, 2 plays twice as fast as recorded.
+	// defaults to 1, math.Inf(1) plays back without waiting.
+	Speed float64
+
+	mu    sync.Mutex
+	r     *bufio.Reader
+	gz    *gzip.Reader
+	file  *os.File      // closed by Close, see OpenReplay
+	start time.Time     // playback start, set by the first NextDispatch
+	at    time.Duration // recording time of the last record read
+	next  []byte        // next recorded message, nil if not read yet
+
+	recorded     map[DWORD][]recordedDatum // recorded data definitions by defineID
+	systemEvents map[DWORD]string          // recorded system events by eventID
+
+	definitions map[DWORD][]recordedDatum // data definitions of the client by defineID
+	subscribed  map[string]DWORD          // system events of the client by name
+	requests    map[DWORD]replayRequest   // requests of the client by recorded requestID
+	wanted      []replayRequest           // requests of the client not answered yet
+}
+
+// NewReplay reads the recording r, it is not closed by Close.
+func NewReplay(r io.Reader) (*Replay, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("recording error: %s", err)
+	}
+
+	s := &Replay{
+		registry:     newRegistry(),
+		Speed:        1,
+		r:            bufio.NewReader(gz),
+		gz:           gz,
+		recorded:     map[DWORD][]recordedDatum{},
+		systemEvents: map[DWORD]string{},
+		definitions:  map[DWORD][]recordedDatum{},
+		subscribed:   map[string]DWORD{},
+		requests:     map[DWORD]replayRequest{},
+	}
+
+	magic := make([]byte, len(recordingMagic))
+	if _, err := io.ReadFull(s.r, magic); err != nil || string(magic) != recordingMagic {
+		return nil, fmt.Errorf("recording error: not a recording")
+	}
+	return s, nil
+}
+
+// OpenReplay plays back the recording in the file name.
+func OpenReplay(name string) (*Replay, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("recording error: %s", err)
+	}
+
+	s, err := NewReplay(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	s.file = f
+	return s, nil
+}
+
+// read reads records up to the next recorded message, the replay lock has to
+// be held.
+func (s *Replay) read() error {
+	for s.next == nil {
+		kind, err := s.r.ReadByte()
+		if err == io.EOF {
+			return ErrReplayEnded
+		}
+		if err != nil {
+			return fmt.Errorf("recording error: %s", err)
+		}
+		elapsed, err := binary.ReadUvarint(s.r)
+		if err != nil {
+			return fmt.Errorf("recording error: %s", err)
+		}
+		size, err := binary.ReadUvarint(s.r)
+		if err != nil {
+			return fmt.Errorf("recording error: %s", err)
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(s.r, data); err != nil {
+			return fmt.Errorf("recording error: %s", err)
+		}
+		s.at += time.Duration(elapsed) * time.Microsecond
+
+		r := &recvReader{buf: data}
+		switch kind {
+		case recordMessage:
+			s.next = data
+		case recordDatum:
+			defineID := r.dword()
+			d := recordedDatum{
+				name:     r.string(protocolStringShort),
+				unit:     r.string(protocolStringShort),
+				dataType: r.dword(),
+				epsilon:  r.float32(),
+				datumID:  r.dword(),
+			}
+			s.recorded[defineID] = append(s.recorded[defineID], d)
+		case recordClearDefinition:
+			delete(s.recorded, r.dword())
+		case recordSystemEvent:
+			eventID := r.dword()
+			s.systemEvents[eventID] = r.string(protocolStringShort)
+		}
+		if r.err != nil {
+			return fmt.Errorf("recording error: %s", r.err)
+		}
+	}
+	return nil
+}
+
+// due reports whether the playback reached the next recorded message.
+func (s *Replay) due() bool {
+	if math.IsInf(s.Speed, 1) {
+		return true
+	}
+	speed := s.Speed
+	if speed <= 0 {
+		speed = 1
+	}
+	return float64(time.Since(s.start)) >= float64(s.at)/speed
+}
+
+// rewrite returns the recorded message buf with the IDs of the client, nil if
+// the client did not ask for it. the replay lock has to be held.
+func (s *Replay) rewrite(buf []byte) []byte {
+	if len(buf) < 12 {
+		return buf
+	}
+
+	switch id := DWORD(binary.LittleEndian.Uint32(buf[8:])); id {
+	case RECV_ID_SIMOBJECT_DATA, RECV_ID_SIMOBJECT_DATA_BYTYPE:
+		if len(buf) < 24 {
+			return buf
+		}
+		requestID := DWORD(binary.LittleEndian.Uint32(buf[12:]))
+		req, ok := s.requests[requestID]
+		if !ok {
+			defineID := DWORD(binary.LittleEndian.Uint32(buf[20:]))
+			if req, ok = s.want(defineID); !ok {
+				return nil
+			}
+			s.requests[requestID] = req
+		}
+		binary.LittleEndian.PutUint32(buf[12:], uint32(req.requestID))
+		binary.LittleEndian.PutUint32(buf[20:], uint32(req.defineID))
+
+		// a request answered once leaves the next recorded answers to a
+		// later request, by type once the last entry arrived
+		last := id == RECV_ID_SIMOBJECT_DATA || len(buf) < 36 ||
+			binary.LittleEndian.Uint32(buf[28:]) >= binary.LittleEndian.Uint32(buf[32:])
+		if req.once && last {
+			delete(s.requests, requestID)
+		}
+
+	case RECV_ID_EVENT, RECV_ID_EVENT_FILENAME, RECV_ID_EVENT_FRAME, RECV_ID_EVENT_OBJECT_ADDREMOVE:
+		if len(buf) < 20 {
+			return buf
+		}
+		name, ok := s.systemEvents[DWORD(binary.LittleEndian.Uint32(buf[16:]))]
+		if !ok {
+			return buf
+		}
+		eventID, ok := s.subscribed[name]
+		if !ok {
+			return nil
+		}
+		binary.LittleEndian.PutUint32(buf[16:], uint32(eventID))
+	}
+	return buf
+}
+
+// want takes the first unanswered request of the client for a data
+// definition with the datums of the recorded one defineID.
+func (s *Replay) want(defineID DWORD) (replayRequest, bool) {
+	recorded, ok := s.recorded[defineID]
+	if !ok {
+		return replayRequest{}, false
+	}
+	for i, req := range s.wanted {
+		if sameDatums(s.definitions[req.defineID], recorded) {
+			s.wanted = append(s.wanted[:i], s.wanted[i+1:]...)
+			return req, true
+		}
+	}
+	return replayRequest{}, false
+}
+
+func sameDatums(a, b []recordedDatum) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// request adds a data request of the client, PERIOD_NEVER stops it.
+func (s *Replay) request(requestID, defineID DWORD, period Period) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for recorded, req := range s.requests {
+		if req.requestID == requestID {
+			if period == PERIOD_NEVER {
+				delete(s.requests, recorded)
+			}
+			return
+		}
+	}
+	for i, req := range s.wanted {
+		if req.requestID == requestID {
+			if period == PERIOD_NEVER {
+				s.wanted = append(s.wanted[:i], s.wanted[i+1:]...)
+			}
+			return
+		}
+	}
+	if period != PERIOD_NEVER {
+		s.wanted = append(s.wanted, replayRequest{requestID: requestID, defineID: defineID, once: period == PERIOD_ONCE})
+	}
+}
+
+// NextDispatch returns the next recorded message once it is due, or nil
+// when none is. it returns ErrReplayEnded at the end of the recording.
+func (s *Replay) NextDispatch() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.start.IsZero() {
+		s.start = time.Now()
+	}
+	for {
+		if err := s.read(); err != nil {
+			return nil, err
+		}
+		if !s.due() {
+			return nil, nil
+		}
+
+		buf := s.rewrite(s.next)
+		s.next = nil
+		if buf != nil {
+			return buf, nil
+		}
+	}
+}
+
+func (s *Replay) GetNextDispatch() (unsafe.Pointer, int32, error) {
+	buf, err := s.NextDispatch()
+	if err != nil {
+		return nil, -1, err
+	}
+	if buf == nil {
+		fail := E_FAIL
+		return nil, int32(fail), nil
+	}
+	return unsafe.Pointer(&buf[0]), 0, nil
+}
+
+func (s *Replay) Close() error {
+	if s.file != nil {
+		s.file.Close()
+	}
+	if err := s.gz.Close(); err != nil {
+		return fmt.Errorf("recording error: %s", err)
+	}
+	return nil
+}
+
+func (s *Replay) RegisterDataDefinition(a interface{}) error {
+	return s.registerDataDefinition(s, a)
+}
+
+func (s *Replay) SetData(objectID DWORD, v interface{}) error {
+	return s.setData(s, objectID, v)
+}
+
+func (s *Replay) SendEvent(event KeyEvent, data ...DWORD) error {
+	return s.sendEvent(s, event, data...)
+}
+
+func (s *Replay) RegisterClientDataDefinition(a interface{}) error {
+	return s.registerClientDataDefinition(s, a)
+}
+
+func (s *Replay) WriteClientData(clientDataID DWORD, v interface{}) error {
+	return s.writeClientData(s, clientDataID, v)
+}
+
+func (s *Replay) RegisterFacilityDefinition(a interface{}) error {
+	return s.registerFacilityDefinition(s, a)
+}
+
+func (s *Replay) AddToDataDefinition(defineID DWORD, name, unit string, dataType DWORD, epsilon float32, datumID DWORD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := recordedDatum{name: name, unit: unit, dataType: dataType, epsilon: epsilon, datumID: datumID}
+	s.definitions[defineID] = append(s.definitions[defineID], d)
+	return nil
+}
+
+func (s *Replay) ClearDataDefinition(defineID DWORD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.definitions, defineID)
+	return nil
+}
+
+func (s *Replay) SubscribeToSystemEvent(eventID DWORD, eventName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.subscribed[eventName] = eventID
+	return nil
+}
+
+func (s *Replay) UnsubscribeFromSystemEvent(eventID DWORD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for name, id := range s.subscribed {
+		if id == eventID {
+			delete(s.subscribed, name)
+		}
+	}
+	return nil
+}
+
+func (s *Replay) RequestDataOnSimObjectType(requestID, defineID, radius, simobjectType DWORD) error {
+	s.request(requestID, defineID, PERIOD_ONCE)
+	return nil
+}
+
+func (s *Replay) RequestDataOnSimObject(requestID, defineID, objectID DWORD, period Period, flags DataRequestFlag, origin, interval, limit DWORD) error {
+	s.request(requestID, defineID, period)
+	return nil
+}
+
+// the calls below change nothing in a recording.
+
+func (s *Replay) SetSystemEventState(eventID, state DWORD) error {
+	return nil
+}
+
+func (s *Replay) SetDataOnSimObject(defineID, simobjectType, flags, arrayCount, size DWORD, buf unsafe.Pointer) error {
+	return nil
+}
+
+func (s *Replay) SubscribeToFacilities(facilityType, requestID DWORD) error {
+	return nil
+}
+
+func (s *Replay) UnsubscribeToFacilities(facilityType DWORD) error {
+	return nil
+}
+
+func (s *Replay) RequestFacilitiesList(facilityType, requestID DWORD) error {
+	return nil
+}
+
+func (s *Replay) SubscribeToFacilitiesEX1(facilityType, newElemInRangeRequestID, oldElemOutRangeRequestID DWORD) error {
+	return nil
+}
+
+func (s *Replay) UnsubscribeToFacilitiesEX1(facilityType DWORD, unsubscribeNewInRange, unsubscribeOldOutRange bool) error {
+	return nil
+}
+
+func (s *Replay) RequestFacilitiesListEX1(facilityType, requestID DWORD) error {
+	return nil
+}
+
+func (s *Replay) MapClientEventToSimEvent(eventID DWORD, eventName string) error {
+	return nil
+}
+
+func (s *Replay) TransmitClientEvent(objectID, eventID, data, groupID, flags DWORD) error {
+	return nil
+}
+
+func (s *Replay) MenuAddItem(menuItem string, menuEventID, Data DWORD) error {
+	return nil
+}
+
+func (s *Replay) MenuDeleteItem(menuItem string, menuEventID, Data DWORD) error {
+	return nil
+}
+
+func (s *Replay) AddClientEventToNotificationGroup(groupID, eventID DWORD) error {
+	return nil
+}
+
+func (s *Replay) SetNotificationGroupPriority(groupID, priority DWORD) error {
+	return nil
+}
+
+func (s *Replay) MapInputEventToClientEvent(groupID DWORD, inputDefinition string, downEventID, downValue, upEventID, upValue DWORD) error {
+	return nil
+}
+
+func (s *Replay) RemoveInputEvent(groupID DWORD, inputDefinition string) error {
+	return nil
+}
+
+func (s *Replay) ClearInputGroup(groupID DWORD) error {
+	return nil
+}
+
+func (s *Replay) SetInputGroupPriority(groupID, priority DWORD) error {
+	return nil
+}
+
+func (s *Replay) SetInputGroupState(groupID, state DWORD) error {
+	return nil
+}
+
+func (s *Replay) MapClientDataNameToID(clientDataName string, clientDataID DWORD) error {
+	return nil
+}
+
+func (s *Replay) CreateClientData(clientDataID, size, flags DWORD) error {
+	return nil
+}
+
+func (s *Replay) AddToClientDataDefinition(defineID, offset, sizeOrType DWORD, epsilon float32, datumID DWORD) error {
+	return nil
+}
+
+func (s *Replay) ClearClientDataDefinition(defineID DWORD) error {
+	return nil
+}
+
+func (s *Replay) RequestClientData(clientDataID, requestID, defineID DWORD, period ClientDataPeriod, flags ClientDataRequestFlag, origin, interval, limit DWORD) error {
+	return nil
+}
+
+func (s *Replay) SetClientData(clientDataID, defineID, flags, size DWORD, buf unsafe.Pointer) error {
+	return nil
+}
+
+func (s *Replay) AddToFacilityDefinition(defineID DWORD, fieldName string) error {
+	return nil
+}
+
+func (s *Replay) RequestFacilityData(defineID, requestID DWORD, icao, region string) error {
+	return nil
+}
+
+func (s *Replay) AICreateParkedATCAircraft(containerTitle, tailNumber, airportID string, requestID DWORD) error {
+	return nil
+}
+
+func (s *Replay) AICreateEnrouteATCAircraft(containerTitle, tailNumber string, flightNumber int32, flightPlanPath string, flightPlanPosition float64, touchAndGo bool, requestID DWORD) error {
+	return nil
+}
+
+func (s *Replay) AICreateNonATCAircraft(containerTitle, tailNumber string, initPos DataInitPosition, requestID DWORD) error {
+	return nil
+}
+
+func (s *Replay) AICreateSimulatedObject(containerTitle string, initPos DataInitPosition, requestID DWORD) error {
+	return nil
+}
+
+func (s *Replay) AIReleaseControl(objectID, requestID DWORD) error {
+	return nil
+}
+
+func (s *Replay) AIRemoveObject(objectID, requestID DWORD) error {
+	return nil
+}
+
+func (s *Replay) AISetAircraftFlightPlan(objectID DWORD, flightPlanPath string, requestID DWORD) error {
+	return nil
+}
+
+func (s *Replay) RequestSystemState(requestID DWORD, state string) error {
+	return nil
+}
+
+func (s *Replay) FlightLoad(fileName string) error {
+	return nil
+}
+
+func (s *Replay) FlightSave(fileName, title, description string, flags DWORD) error {
+	return nil
+}
+
+func (s *Replay) FlightPlanLoad(fileName string) error {
+	return nil
+}
+
+func (s *Replay) ShowText(textType DWORD, duration float64, eventID DWORD, text string) error {
+	return nil
+}
+
+var _ Client = (*Replay)(nil)
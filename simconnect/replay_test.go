@@ -0,0 +1,152 @@
+package simconnect_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/supersidor/msfs2020-go/simconnect"
+	"github.com/supersidor/msfs2020-go/simconnect/simtest"
+)
+
+// record runs session against srv through a Recorder and returns the
+// recording.
+func record(t *testing.T, srv *simtest.Server, session func(d *simconnect.Dispatcher)) []byte {
+	s, err := simconnect.Dial("record", srv.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	r, err := simconnect.NewRecorder(s, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := simconnect.NewDispatcher(r)
+	ctx, cancel := context.WithCancel(context.Background())
+	d.Start(ctx)
+	session(d)
+	cancel()
+	d.Wait()
+
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// replay returns a running dispatcher playing back recording at half speed,
+// answers due before the replayed request was made would be skipped.
+func replay(t *testing.T, recording []byte) *simconnect.Dispatcher {
+	r, err := simconnect.NewReplay(bytes.NewReader(recording))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Speed = 0.5
+	t.Cleanup(func() { r.Close() })
+
+	d := simconnect.NewDispatcher(r)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	d.Start(ctx)
+	return d
+}
+
+// requests asks for the altitude of the user aircraft twice and for every
+// aircraft once, some time apart.
+func requests(t *testing.T, d *simconnect.Dispatcher) []float64 {
+	var altitudes []float64
+	for i := 0; i < 2; i++ {
+		var a altitude
+		if err := d.RequestOnce(withTimeout(t), &a, simconnect.OBJECT_ID_USER); err != nil {
+			t.Fatal(err)
+		}
+		altitudes = append(altitudes, a.Altitude)
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	var all []altitude
+	if err := d.RequestByType(withTimeout(t), &all, 10000, simconnect.SIMOBJECT_TYPE_AIRCRAFT); err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range all {
+		altitudes = append(altitudes, a.Altitude)
+	}
+	return altitudes
+}
+
+func TestReplay(t *testing.T) {
+	srv := simtest.NewServer()
+	defer srv.Close()
+	srv.SetSimVar(simtest.UserObjectID, "PLANE ALTITUDE", 1000)
+	srv.AddObject(simtest.FirstAIObjectID, simconnect.SIMOBJECT_TYPE_AIRCRAFT)
+	srv.SetSimVar(simtest.FirstAIObjectID, "PLANE ALTITUDE", 3000)
+
+	var recorded []float64
+	recording := record(t, srv, func(d *simconnect.Dispatcher) {
+		recorded = requests(t, d)
+		srv.SetSimVar(simtest.UserObjectID, "PLANE ALTITUDE", 2000)
+		recorded = append(recorded, requests(t, d)...)
+	})
+	want := []float64{1000, 1000, 1000, 3000, 2000, 2000, 2000, 3000}
+	if !equal(recorded, want) {
+		t.Fatalf("recorded %v, want %v", recorded, want)
+	}
+
+	// every request takes its own recorded answer
+	d := replay(t, recording)
+	replayed := requests(t, d)
+	replayed = append(replayed, requests(t, d)...)
+	if !equal(replayed, want) {
+		t.Errorf("replayed %v, want %v", replayed, want)
+	}
+}
+
+func TestReplayPolling(t *testing.T) {
+	srv := simtest.NewServer()
+	defer srv.Close()
+
+	// recorded as one subscription, replayed by asking again and again
+	recording := record(t, srv, func(d *simconnect.Dispatcher) {
+		reports := make(chan altitude, 8)
+		if err := d.Subscribe(withTimeout(t), reports, simconnect.OBJECT_ID_USER, simconnect.PERIOD_SECOND, nil); err != nil {
+			t.Fatal(err)
+		}
+		for _, alt := range []float64{1000, 2000, 3000} {
+			time.Sleep(50 * time.Millisecond)
+			srv.SetSimVar(simtest.UserObjectID, "PLANE ALTITUDE", alt)
+			srv.Tick()
+			select {
+			case <-reports:
+			case <-withTimeout(t).Done():
+				t.Fatal("no report")
+			}
+		}
+	})
+
+	d := replay(t, recording)
+	var replayed []float64
+	for i := 0; i < 3; i++ {
+		var a altitude
+		if err := d.RequestOnce(withTimeout(t), &a, simconnect.OBJECT_ID_USER); err != nil {
+			t.Fatalf("request %d: %s", i+1, err)
+		}
+		replayed = append(replayed, a.Altitude)
+	}
+	if want := []float64{1000, 2000, 3000}; !equal(replayed, want) {
+		t.Errorf("replayed %v, want %v", replayed, want)
+	}
+}
+
+func equal(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,125 @@
+package simconnect
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// RequestOnce registers v, a pointer to a data definition struct, requests
+// its simulation variables of objectID once and waits for them to fill v. the
+// dispatcher has to be running.
+func (d *Dispatcher) RequestOnce(ctx context.Context, v interface{}, objectID DWORD) error {
+	if reflect.TypeOf(v).Kind() != reflect.Ptr {
+		return fmt.Errorf("%T is not a pointer", v)
+	}
+	c := d.client
+	if err := c.RegisterDataDefinition(v); err != nil {
+		return err
+	}
+
+	requestID := c.GetRequestID()
+	result := make(chan interface{}, 1)
+	d.HandleRequest(requestID, func(msg interface{}) {
+		if m, ok := msg.(*SimobjectData); ok && m.Value != nil {
+			select {
+			case result <- m.Value:
+			default:
+			}
+		}
+	})
+	defer d.HandleRequest(requestID, nil)
+
+	if err := c.RequestDataOnSimObject(requestID, c.GetDefineID(v), objectID, PERIOD_ONCE, 0, 0, 0, 0); err != nil {
+		return err
+	}
+
+	select {
+	case value := <-result:
+		reflect.ValueOf(v).Elem().Set(reflect.ValueOf(value).Elem())
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RequestByType registers the element type of v, a pointer to a slice of
+// data definition structs or pointers to them, requests its simulation
+// variables of every object of simobjectType (SIMOBJECT_TYPE_*) within
+// radius meters of the user aircraft and sets v to one element per object,
+// in the order of their entry numbers. the dispatcher has to be running.
+//
+// the simulator may not answer at all when there is no such object, ctx
+// bounds the wait.
+func (d *Dispatcher) RequestByType(ctx context.Context, v interface{}, radius, simobjectType DWORD) error {
+	slice := reflect.ValueOf(v)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("%T is not a pointer to a slice", v)
+	}
+	elem := slice.Type().Elem().Elem()
+	structType := elem
+	if elem.Kind() == reflect.Ptr {
+		structType = elem.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("%T is not a pointer to a slice of structs", v)
+	}
+
+	c := d.client
+	def := reflect.New(structType).Interface()
+	if err := c.RegisterDataDefinition(def); err != nil {
+		return err
+	}
+
+	// handlers run on the pump goroutine, one at a time
+	requestID := c.GetRequestID()
+	result := make(chan []interface{}, 1)
+	var entries []interface{}
+	d.HandleRequest(requestID, func(msg interface{}) {
+		m, ok := msg.(*SimobjectData)
+		if !ok || (m.Value == nil && m.OutOf > 0) {
+			return
+		}
+		if m.OutOf == 0 {
+			entries = nil
+		} else {
+			if entries == nil {
+				entries = make([]interface{}, m.OutOf)
+			}
+			if m.EntryNumber < 1 || int(m.EntryNumber) > len(entries) {
+				return
+			}
+			entries[m.EntryNumber-1] = m.Value
+			for _, e := range entries {
+				if e == nil {
+					return
+				}
+			}
+		}
+		select {
+		case result <- entries:
+		default:
+		}
+	})
+	defer d.HandleRequest(requestID, nil)
+
+	if err := c.RequestDataOnSimObjectType(requestID, c.GetDefineID(def), radius, simobjectType); err != nil {
+		return err
+	}
+
+	select {
+	case values := <-result:
+		out := reflect.MakeSlice(slice.Type().Elem(), len(values), len(values))
+		for i, value := range values {
+			if elem.Kind() == reflect.Ptr {
+				out.Index(i).Set(reflect.ValueOf(value))
+			} else {
+				out.Index(i).Set(reflect.ValueOf(value).Elem())
+			}
+		}
+		slice.Elem().Set(out)
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -1,6 +1,9 @@
+//go:build windows
+// +build windows
+
 package simconnect
 
-//go:generate go-bindata -pkg simconnect -o bindata.go -modtime 1 -prefix "../_vendor" "../_vendor/MSFS-SDK/SimConnect SDK/lib/SimConnect.dll"
+//go:generate go-bindata -pkg simconnect -tags windows -o bindata.go -modtime 1 -prefix "../_vendor" "../_vendor/MSFS-SDK/SimConnect SDK/lib/SimConnect.dll"
 
 // MSFS-SDK/SimConnect\ SDK/include/SimConnect.h
 // MSFS-SDK/SimConnect\ SDK/lib/SimConnect.dll
@@ -8,9 +11,9 @@ package simconnect
 import (
 	"fmt"
 	"io/ioutil"
+	"math"
 	"os"
 	"path/filepath"
-	"reflect"
 	"syscall"
 	"unsafe"
 )
@@ -18,31 +21,61 @@ import (
 var proc_SimConnect_Open *syscall.LazyProc
 var proc_SimConnect_Close *syscall.LazyProc
 var proc_SimConnect_AddToDataDefinition *syscall.LazyProc
+var proc_SimConnect_ClearDataDefinition *syscall.LazyProc
 var proc_SimConnect_SubscribeToSystemEvent *syscall.LazyProc
+var proc_SimConnect_UnsubscribeFromSystemEvent *syscall.LazyProc
+var proc_SimConnect_SetSystemEventState *syscall.LazyProc
 var proc_SimConnect_GetNextDispatch *syscall.LazyProc
+var proc_SimConnect_GetLastSentPacketID *syscall.LazyProc
 var proc_SimConnect_RequestDataOnSimObject *syscall.LazyProc
 var proc_SimConnect_RequestDataOnSimObjectType *syscall.LazyProc
 var proc_SimConnect_SetDataOnSimObject *syscall.LazyProc
 var proc_SimConnect_SubscribeToFacilities *syscall.LazyProc
 var proc_SimConnect_UnsubscribeToFacilities *syscall.LazyProc
 var proc_SimConnect_RequestFacilitiesList *syscall.LazyProc
+var proc_SimConnect_SubscribeToFacilities_EX1 *syscall.LazyProc
+var proc_SimConnect_UnsubscribeToFacilities_EX1 *syscall.LazyProc
+var proc_SimConnect_RequestFacilitiesList_EX1 *syscall.LazyProc
 var proc_SimConnect_MapClientEventToSimEvent *syscall.LazyProc
+var proc_SimConnect_TransmitClientEvent *syscall.LazyProc
 var proc_SimConnect_MenuAddItem *syscall.LazyProc
 var proc_SimConnect_MenuDeleteItem *syscall.LazyProc
 var proc_SimConnect_AddClientEventToNotificationGroup *syscall.LazyProc
 var proc_SimConnect_SetNotificationGroupPriority *syscall.LazyProc
+var proc_SimConnect_MapInputEventToClientEvent *syscall.LazyProc
+var proc_SimConnect_RemoveInputEvent *syscall.LazyProc
+var proc_SimConnect_ClearInputGroup *syscall.LazyProc
+var proc_SimConnect_SetInputGroupPriority *syscall.LazyProc
+var proc_SimConnect_SetInputGroupState *syscall.LazyProc
+var proc_SimConnect_MapClientDataNameToID *syscall.LazyProc
+var proc_SimConnect_CreateClientData *syscall.LazyProc
+var proc_SimConnect_AddToClientDataDefinition *syscall.LazyProc
+var proc_SimConnect_ClearClientDataDefinition *syscall.LazyProc
+var proc_SimConnect_RequestClientData *syscall.LazyProc
+var proc_SimConnect_SetClientData *syscall.LazyProc
+var proc_SimConnect_AddToFacilityDefinition *syscall.LazyProc
+var proc_SimConnect_RequestFacilityData *syscall.LazyProc
+var proc_SimConnect_AICreateParkedATCAircraft *syscall.LazyProc
+var proc_SimConnect_AICreateEnrouteATCAircraft *syscall.LazyProc
+var proc_SimConnect_AICreateNonATCAircraft *syscall.LazyProc
+var proc_SimConnect_AICreateSimulatedObject *syscall.LazyProc
+var proc_SimConnect_AIReleaseControl *syscall.LazyProc
+var proc_SimConnect_AIRemoveObject *syscall.LazyProc
+var proc_SimConnect_AISetAircraftFlightPlan *syscall.LazyProc
+var proc_SimConnect_RequestSystemState *syscall.LazyProc
+var proc_SimConnect_FlightLoad *syscall.LazyProc
+var proc_SimConnect_FlightSave *syscall.LazyProc
+var proc_SimConnect_FlightPlanLoad *syscall.LazyProc
 var proc_SimConnect_Text *syscall.LazyProc
 
 type SimConnect struct {
-	handle      unsafe.Pointer
-	DefineMap   map[string]DWORD
-	LastEventID DWORD
+	registry
+	handle unsafe.Pointer
 }
 
 func New(name string) (*SimConnect, error) {
 	s := &SimConnect{
-		DefineMap:   map[string]DWORD{"_last": 0},
-		LastEventID: 0,
+		registry: newRegistry(),
 	}
 
 	if proc_SimConnect_Open == nil {
@@ -68,19 +101,51 @@ func New(name string) (*SimConnect, error) {
 		proc_SimConnect_Open = mod.NewProc("SimConnect_Open")
 		proc_SimConnect_Close = mod.NewProc("SimConnect_Close")
 		proc_SimConnect_AddToDataDefinition = mod.NewProc("SimConnect_AddToDataDefinition")
+		proc_SimConnect_ClearDataDefinition = mod.NewProc("SimConnect_ClearDataDefinition")
 		proc_SimConnect_SubscribeToSystemEvent = mod.NewProc("SimConnect_SubscribeToSystemEvent")
+		proc_SimConnect_UnsubscribeFromSystemEvent = mod.NewProc("SimConnect_UnsubscribeFromSystemEvent")
+		proc_SimConnect_SetSystemEventState = mod.NewProc("SimConnect_SetSystemEventState")
 		proc_SimConnect_GetNextDispatch = mod.NewProc("SimConnect_GetNextDispatch")
+		proc_SimConnect_GetLastSentPacketID = mod.NewProc("SimConnect_GetLastSentPacketID")
 		proc_SimConnect_RequestDataOnSimObject = mod.NewProc("SimConnect_RequestDataOnSimObject")
 		proc_SimConnect_RequestDataOnSimObjectType = mod.NewProc("SimConnect_RequestDataOnSimObjectType")
 		proc_SimConnect_SetDataOnSimObject = mod.NewProc("SimConnect_SetDataOnSimObject")
 		proc_SimConnect_SubscribeToFacilities = mod.NewProc("SimConnect_SubscribeToFacilities")
 		proc_SimConnect_UnsubscribeToFacilities = mod.NewProc("SimConnect_UnsubscribeToFacilities")
 		proc_SimConnect_RequestFacilitiesList = mod.NewProc("SimConnect_RequestFacilitiesList")
+		proc_SimConnect_SubscribeToFacilities_EX1 = mod.NewProc("SimConnect_SubscribeToFacilities_EX1")
+		proc_SimConnect_UnsubscribeToFacilities_EX1 = mod.NewProc("SimConnect_UnsubscribeToFacilities_EX1")
+		proc_SimConnect_RequestFacilitiesList_EX1 = mod.NewProc("SimConnect_RequestFacilitiesList_EX1")
 		proc_SimConnect_MapClientEventToSimEvent = mod.NewProc("SimConnect_MapClientEventToSimEvent")
+		proc_SimConnect_TransmitClientEvent = mod.NewProc("SimConnect_TransmitClientEvent")
 		proc_SimConnect_MenuAddItem = mod.NewProc("SimConnect_MenuAddItem")
 		proc_SimConnect_MenuDeleteItem = mod.NewProc("SimConnect_MenuDeleteItem")
 		proc_SimConnect_AddClientEventToNotificationGroup = mod.NewProc("SimConnect_AddClientEventToNotificationGroup")
 		proc_SimConnect_SetNotificationGroupPriority = mod.NewProc("SimConnect_SetNotificationGroupPriority")
+		proc_SimConnect_MapInputEventToClientEvent = mod.NewProc("SimConnect_MapInputEventToClientEvent")
+		proc_SimConnect_RemoveInputEvent = mod.NewProc("SimConnect_RemoveInputEvent")
+		proc_SimConnect_ClearInputGroup = mod.NewProc("SimConnect_ClearInputGroup")
+		proc_SimConnect_SetInputGroupPriority = mod.NewProc("SimConnect_SetInputGroupPriority")
+		proc_SimConnect_SetInputGroupState = mod.NewProc("SimConnect_SetInputGroupState")
+		proc_SimConnect_MapClientDataNameToID = mod.NewProc("SimConnect_MapClientDataNameToID")
+		proc_SimConnect_CreateClientData = mod.NewProc("SimConnect_CreateClientData")
+		proc_SimConnect_AddToClientDataDefinition = mod.NewProc("SimConnect_AddToClientDataDefinition")
+		proc_SimConnect_ClearClientDataDefinition = mod.NewProc("SimConnect_ClearClientDataDefinition")
+		proc_SimConnect_RequestClientData = mod.NewProc("SimConnect_RequestClientData")
+		proc_SimConnect_SetClientData = mod.NewProc("SimConnect_SetClientData")
+		proc_SimConnect_AddToFacilityDefinition = mod.NewProc("SimConnect_AddToFacilityDefinition")
+		proc_SimConnect_RequestFacilityData = mod.NewProc("SimConnect_RequestFacilityData")
+		proc_SimConnect_AICreateParkedATCAircraft = mod.NewProc("SimConnect_AICreateParkedATCAircraft")
+		proc_SimConnect_AICreateEnrouteATCAircraft = mod.NewProc("SimConnect_AICreateEnrouteATCAircraft")
+		proc_SimConnect_AICreateNonATCAircraft = mod.NewProc("SimConnect_AICreateNonATCAircraft")
+		proc_SimConnect_AICreateSimulatedObject = mod.NewProc("SimConnect_AICreateSimulatedObject")
+		proc_SimConnect_AIReleaseControl = mod.NewProc("SimConnect_AIReleaseControl")
+		proc_SimConnect_AIRemoveObject = mod.NewProc("SimConnect_AIRemoveObject")
+		proc_SimConnect_AISetAircraftFlightPlan = mod.NewProc("SimConnect_AISetAircraftFlightPlan")
+		proc_SimConnect_RequestSystemState = mod.NewProc("SimConnect_RequestSystemState")
+		proc_SimConnect_FlightLoad = mod.NewProc("SimConnect_FlightLoad")
+		proc_SimConnect_FlightSave = mod.NewProc("SimConnect_FlightSave")
+		proc_SimConnect_FlightPlanLoad = mod.NewProc("SimConnect_FlightPlanLoad")
 		proc_SimConnect_Text = mod.NewProc("SimConnect_Text")
 	}
 
@@ -109,53 +174,38 @@ func New(name string) (*SimConnect, error) {
 	return s, nil
 }
 
-func (s *SimConnect) GetEventID() DWORD {
-	id := s.LastEventID
-	s.LastEventID += 1
-	return id
-}
-
-func (s *SimConnect) GetDefineID(a interface{}) DWORD {
-	structName := reflect.TypeOf(a).Elem().Name()
+var _ Client = (*SimConnect)(nil)
 
-	id, ok := s.DefineMap[structName]
-	if !ok {
-		id = s.DefineMap["_last"]
-		s.DefineMap[structName] = id
-		s.DefineMap["_last"] = id + 1
+func openLocal(name string) (Client, error) {
+	s, err := New(name)
+	if err != nil {
+		return nil, err
 	}
-
-	return id
+	return s, nil
 }
 
 func (s *SimConnect) RegisterDataDefinition(a interface{}) error {
-	defineID := s.GetDefineID(a)
-
-	v := reflect.ValueOf(a).Elem()
-	for j := 1; j < v.NumField(); j++ {
-		fieldName := v.Type().Field(j).Name
-		nameTag, _ := v.Type().Field(j).Tag.Lookup("name")
-		unitTag, _ := v.Type().Field(j).Tag.Lookup("unit")
+	return s.registerDataDefinition(s, a)
+}
 
-		fieldType := v.Field(j).Kind().String()
-		if fieldType == "array" {
-			fieldType = fmt.Sprintf("[%d]byte", v.Field(j).Type().Len())
-		}
+func (s *SimConnect) SetData(objectID DWORD, v interface{}) error {
+	return s.setData(s, objectID, v)
+}
 
-		if nameTag == "" {
-			return fmt.Errorf("%s name tag not found", fieldName)
-		}
+func (s *SimConnect) SendEvent(event KeyEvent, data ...DWORD) error {
+	return s.sendEvent(s, event, data...)
+}
 
-		dataType, err := derefDataType(fieldType)
-		if err != nil {
-			return err
-		}
+func (s *SimConnect) RegisterClientDataDefinition(a interface{}) error {
+	return s.registerClientDataDefinition(s, a)
+}
 
-		s.AddToDataDefinition(defineID, nameTag, unitTag, dataType)
-		//fmt.Printf("fieldName: %s  fieldType: %s  nameTag: %s unitTag: %s\n", fieldName, fieldType, nameTag, unitTag)
-	}
+func (s *SimConnect) WriteClientData(clientDataID DWORD, v interface{}) error {
+	return s.writeClientData(s, clientDataID, v)
+}
 
-	return nil
+func (s *SimConnect) RegisterFacilityDefinition(a interface{}) error {
+	return s.registerFacilityDefinition(s, a)
 }
 
 func (s *SimConnect) Close() error {
@@ -165,12 +215,11 @@ func (s *SimConnect) Close() error {
 	r1, _, err := proc_SimConnect_Close.Call(uintptr(s.handle))
 	if int32(r1) < 0 {
 		return fmt.Errorf("SimConnect_Close error: %d %s", int32(r1), err)
-		panic("failed")
 	}
 	return nil
 }
 
-func (s *SimConnect) AddToDataDefinition(defineID DWORD, name, unit string, dataType DWORD) error {
+func (s *SimConnect) AddToDataDefinition(defineID DWORD, name, unit string, dataType DWORD, epsilon float32, datumID DWORD) error {
 	// SimConnect_AddToDataDefinition(
 	//   HANDLE hSimConnect,
 	//   SIMCONNECT_DATA_DEFINITION_ID DefineID,
@@ -190,8 +239,8 @@ func (s *SimConnect) AddToDataDefinition(defineID DWORD, name, unit string, data
 		uintptr(unsafe.Pointer(&_name[0])),
 		uintptr(0),
 		uintptr(dataType),
-		uintptr(float32(0)),
-		uintptr(UNUSED),
+		uintptr(math.Float32bits(epsilon)),
+		uintptr(datumID),
 	}
 	if unit != "" {
 		args[3] = uintptr(unsafe.Pointer(&_unit[0]))
@@ -202,6 +251,29 @@ func (s *SimConnect) AddToDataDefinition(defineID DWORD, name, unit string, data
 		return fmt.Errorf("SimConnect_AddToDataDefinition for %s error: %d %s", name, r1, err)
 	}
 
+	s.sent(defineID, name, unit, dataType, epsilon, datumID)
+
+	return nil
+}
+
+func (s *SimConnect) ClearDataDefinition(defineID DWORD) error {
+	// SimConnect_ClearDataDefinition(
+	//   HANDLE hSimConnect,
+	//   SIMCONNECT_DATA_DEFINITION_ID DefineID
+	// );
+
+	args := []uintptr{
+		uintptr(s.handle),
+		uintptr(defineID),
+	}
+
+	r1, _, err := proc_SimConnect_ClearDataDefinition.Call(args...)
+	if int32(r1) < 0 {
+		return fmt.Errorf("SimConnect_ClearDataDefinition for defineID %d error: %d %s", defineID, r1, err)
+	}
+
+	s.sent(defineID)
+
 	return nil
 }
 
@@ -225,6 +297,52 @@ func (s *SimConnect) SubscribeToSystemEvent(eventID DWORD, eventName string) err
 		return fmt.Errorf("SimConnect_SubscribeToSystemEvent for %s error: %d %s", eventName, r1, err)
 	}
 
+	s.sent(eventID, eventName)
+
+	return nil
+}
+
+func (s *SimConnect) UnsubscribeFromSystemEvent(eventID DWORD) error {
+	// SimConnect_UnsubscribeFromSystemEvent(
+	//   HANDLE hSimConnect,
+	//   SIMCONNECT_CLIENT_EVENT_ID EventID
+	// );
+
+	args := []uintptr{
+		uintptr(s.handle),
+		uintptr(eventID),
+	}
+
+	r1, _, err := proc_SimConnect_UnsubscribeFromSystemEvent.Call(args...)
+	if int32(r1) < 0 {
+		return fmt.Errorf("SimConnect_UnsubscribeFromSystemEvent for eventID %d error: %d %s", eventID, r1, err)
+	}
+
+	s.sent(eventID)
+
+	return nil
+}
+
+func (s *SimConnect) SetSystemEventState(eventID, state DWORD) error {
+	// SimConnect_SetSystemEventState(
+	//   HANDLE hSimConnect,
+	//   SIMCONNECT_CLIENT_EVENT_ID EventID,
+	//   SIMCONNECT_STATE dwState
+	// );
+
+	args := []uintptr{
+		uintptr(s.handle),
+		uintptr(eventID),
+		uintptr(state),
+	}
+
+	r1, _, err := proc_SimConnect_SetSystemEventState.Call(args...)
+	if int32(r1) < 0 {
+		return fmt.Errorf("SimConnect_SetSystemEventState for eventID %d state %d error: %d %s", eventID, state, r1, err)
+	}
+
+	s.sent(eventID, state)
+
 	return nil
 }
 
@@ -252,10 +370,12 @@ func (s *SimConnect) RequestDataOnSimObjectType(requestID, defineID, radius, sim
 		)
 	}
 
+	s.sent(requestID, defineID, radius, simobjectType)
+
 	return nil
 }
 
-func (s *SimConnect) RequestDataOnSimObject(requestID, defineID, objectID, period, flags, origin, interval, limit DWORD) error {
+func (s *SimConnect) RequestDataOnSimObject(requestID, defineID, objectID DWORD, period Period, flags DataRequestFlag, origin, interval, limit DWORD) error {
 	// SimConnect_RequestDataOnSimObject(
 	//   HANDLE hSimConnect,
 	//   SIMCONNECT_DATA_REQUEST_ID RequestID,
@@ -288,6 +408,8 @@ func (s *SimConnect) RequestDataOnSimObject(requestID, defineID, objectID, perio
 		)
 	}
 
+	s.sent(requestID, defineID, objectID, period, flags, origin, interval, limit)
+
 	return nil
 }
 
@@ -321,6 +443,8 @@ func (s *SimConnect) SetDataOnSimObject(defineID, simobjectType, flags, arrayCou
 		)
 	}
 
+	s.sent(defineID, simobjectType, flags, arrayCount, size)
+
 	return nil
 }
 
@@ -345,6 +469,8 @@ func (s *SimConnect) SubscribeToFacilities(facilityType, requestID DWORD) error
 		)
 	}
 
+	s.sent(facilityType, requestID)
+
 	return nil
 }
 
@@ -367,6 +493,8 @@ func (s *SimConnect) UnsubscribeToFacilities(facilityType DWORD) error {
 		)
 	}
 
+	s.sent(facilityType)
+
 	return nil
 }
 
@@ -391,6 +519,90 @@ func (s *SimConnect) RequestFacilitiesList(facilityType, requestID DWORD) error
 		)
 	}
 
+	s.sent(facilityType, requestID)
+
+	return nil
+}
+
+func (s *SimConnect) SubscribeToFacilitiesEX1(facilityType, newElemInRangeRequestID, oldElemOutRangeRequestID DWORD) error {
+	// SimConnect_SubscribeToFacilities_EX1(
+	//   HANDLE hSimConnect,
+	//   SIMCONNECT_FACILITY_LIST_TYPE type,
+	//   SIMCONNECT_DATA_REQUEST_ID newElemInRangeRequestID,
+	//   SIMCONNECT_DATA_REQUEST_ID oldElemOutRangeRequestID
+	// );
+
+	args := []uintptr{
+		uintptr(s.handle),
+		uintptr(facilityType),
+		uintptr(newElemInRangeRequestID),
+		uintptr(oldElemOutRangeRequestID),
+	}
+
+	r1, _, err := proc_SimConnect_SubscribeToFacilities_EX1.Call(args...)
+	if int32(r1) < 0 {
+		return fmt.Errorf(
+			"SimConnect_SubscribeToFacilities_EX1 for type %d error: %d %s",
+			facilityType, r1, err,
+		)
+	}
+
+	s.sent(facilityType, newElemInRangeRequestID, oldElemOutRangeRequestID)
+
+	return nil
+}
+
+func (s *SimConnect) UnsubscribeToFacilitiesEX1(facilityType DWORD, unsubscribeNewInRange, unsubscribeOldOutRange bool) error {
+	// SimConnect_UnsubscribeToFacilities_EX1(
+	//   HANDLE hSimConnect,
+	//   SIMCONNECT_FACILITY_LIST_TYPE type,
+	//   bool bUnsubscribeNewInRange,
+	//   bool bUnsubscribeOldOutRange
+	// );
+
+	args := []uintptr{
+		uintptr(s.handle),
+		uintptr(facilityType),
+		uintptr(boolDWORD(unsubscribeNewInRange)),
+		uintptr(boolDWORD(unsubscribeOldOutRange)),
+	}
+
+	r1, _, err := proc_SimConnect_UnsubscribeToFacilities_EX1.Call(args...)
+	if int32(r1) < 0 {
+		return fmt.Errorf(
+			"SimConnect_UnsubscribeToFacilities_EX1 for type %d error: %d %s",
+			facilityType, r1, err,
+		)
+	}
+
+	s.sent(facilityType, unsubscribeNewInRange, unsubscribeOldOutRange)
+
+	return nil
+}
+
+func (s *SimConnect) RequestFacilitiesListEX1(facilityType, requestID DWORD) error {
+	// SimConnect_RequestFacilitiesList_EX1(
+	//   HANDLE hSimConnect,
+	//   SIMCONNECT_FACILITY_LIST_TYPE type,
+	//   SIMCONNECT_DATA_REQUEST_ID RequestID
+	// );
+
+	args := []uintptr{
+		uintptr(s.handle),
+		uintptr(facilityType),
+		uintptr(requestID),
+	}
+
+	r1, _, err := proc_SimConnect_RequestFacilitiesList_EX1.Call(args...)
+	if int32(r1) < 0 {
+		return fmt.Errorf(
+			"SimConnect_RequestFacilitiesList_EX1 for type %d error: %d %s",
+			facilityType, r1, err,
+		)
+	}
+
+	s.sent(facilityType, requestID)
+
 	return nil
 }
 
@@ -417,6 +629,40 @@ func (s *SimConnect) MapClientEventToSimEvent(eventID DWORD, eventName string) e
 		)
 	}
 
+	s.sent(eventID, eventName)
+
+	return nil
+}
+
+func (s *SimConnect) TransmitClientEvent(objectID, eventID, data, groupID, flags DWORD) error {
+	// SimConnect_TransmitClientEvent(
+	//   HANDLE hSimConnect,
+	//   SIMCONNECT_OBJECT_ID ObjectID,
+	//   SIMCONNECT_CLIENT_EVENT_ID EventID,
+	//   DWORD dwData,
+	//   SIMCONNECT_NOTIFICATION_GROUP_ID GroupID,
+	//   SIMCONNECT_EVENT_FLAG Flags
+	// );
+
+	args := []uintptr{
+		uintptr(s.handle),
+		uintptr(objectID),
+		uintptr(eventID),
+		uintptr(data),
+		uintptr(groupID),
+		uintptr(flags),
+	}
+
+	r1, _, err := proc_SimConnect_TransmitClientEvent.Call(args...)
+	if int32(r1) < 0 {
+		return fmt.Errorf(
+			"SimConnect_TransmitClientEvent for eventID %d error: %d %s",
+			eventID, r1, err,
+		)
+	}
+
+	s.sent(objectID, eventID, data, groupID, flags)
+
 	return nil
 }
 
@@ -445,6 +691,8 @@ func (s *SimConnect) MenuAddItem(menuItem string, menuEventID, Data DWORD) error
 		)
 	}
 
+	s.sent(menuItem, menuEventID, Data)
+
 	return nil
 }
 
@@ -467,6 +715,8 @@ func (s *SimConnect) MenuDeleteItem(menuItem string, menuEventID, Data DWORD) er
 		)
 	}
 
+	s.sent(menuItem, menuEventID, Data)
+
 	return nil
 }
 
@@ -492,6 +742,8 @@ func (s *SimConnect) AddClientEventToNotificationGroup(groupID, eventID DWORD) e
 		)
 	}
 
+	s.sent(groupID, eventID)
+
 	return nil
 }
 
@@ -516,42 +768,802 @@ func (s *SimConnect) SetNotificationGroupPriority(groupID, priority DWORD) error
 		)
 	}
 
+	s.sent(groupID, priority)
+
 	return nil
 }
 
-func (s *SimConnect) ShowText(textType DWORD, duration float64, eventID DWORD, text string) error {
-	// SimConnect_Text(
+func (s *SimConnect) MapInputEventToClientEvent(groupID DWORD, inputDefinition string, downEventID, downValue, upEventID, upValue DWORD) error {
+	// SimConnect_MapInputEventToClientEvent(
 	//   HANDLE hSimConnect,
-	//   SIMCONNECT_TEXT_TYPE type,
-	//   float fTimeSeconds,
-	//   SIMCONNECT_CLIENT_EVENT_ID EventID,
-	//   DWORD cbUnitSize,
-	//   void * pDataSet
+	//   SIMCONNECT_INPUT_GROUP_ID GroupID,
+	//   const char * pszInputDefinition,
+	//   SIMCONNECT_CLIENT_EVENT_ID DownEventID,
+	//   DWORD DownValue = 0,
+	//   SIMCONNECT_CLIENT_EVENT_ID UpEventID = (SIMCONNECT_CLIENT_EVENT_ID)SIMCONNECT_UNUSED,
+	//   DWORD UpValue = 0,
+	//   BOOL bMaskable = FALSE
 	// );
 
-	_text := []byte(text + "\x00")
+	_inputDefinition := []byte(inputDefinition + "\x00")
 
 	args := []uintptr{
 		uintptr(s.handle),
-		uintptr(textType),
-		uintptr(duration),
-		uintptr(eventID),
-		uintptr(DWORD(len(_text))),
-		uintptr(unsafe.Pointer(&_text[0])),
+		uintptr(groupID),
+		uintptr(unsafe.Pointer(&_inputDefinition[0])),
+		uintptr(downEventID),
+		uintptr(downValue),
+		uintptr(upEventID),
+		uintptr(upValue),
+		0,
 	}
 
-	r1, _, err := proc_SimConnect_Text.Call(args...)
+	r1, _, err := proc_SimConnect_MapInputEventToClientEvent.Call(args...)
 	if int32(r1) < 0 {
 		return fmt.Errorf(
-			"SimConnect_Text for eventID %d textType %d text '%s' error: %d %s",
-			eventID, textType, text, r1, err,
+			"SimConnect_MapInputEventToClientEvent for groupID %d '%s' error: %d %s",
+			groupID, inputDefinition, r1, err,
 		)
 	}
 
+	s.sent(groupID, inputDefinition, downEventID, downValue, upEventID, upValue)
+
 	return nil
 }
 
-func (s *SimConnect) GetNextDispatch() (unsafe.Pointer, int32, error) {
+func (s *SimConnect) RemoveInputEvent(groupID DWORD, inputDefinition string) error {
+	// SimConnect_RemoveInputEvent(
+	//   HANDLE hSimConnect,
+	//   SIMCONNECT_INPUT_GROUP_ID GroupID,
+	//   const char * pszInputDefinition
+	// );
+
+	_inputDefinition := []byte(inputDefinition + "\x00")
+
+	args := []uintptr{
+		uintptr(s.handle),
+		uintptr(groupID),
+		uintptr(unsafe.Pointer(&_inputDefinition[0])),
+	}
+
+	r1, _, err := proc_SimConnect_RemoveInputEvent.Call(args...)
+	if int32(r1) < 0 {
+		return fmt.Errorf(
+			"SimConnect_RemoveInputEvent for groupID %d '%s' error: %d %s",
+			groupID, inputDefinition, r1, err,
+		)
+	}
+
+	s.sent(groupID, inputDefinition)
+
+	return nil
+}
+
+func (s *SimConnect) ClearInputGroup(groupID DWORD) error {
+	// SimConnect_ClearInputGroup(
+	//   HANDLE hSimConnect,
+	//   SIMCONNECT_INPUT_GROUP_ID GroupID
+	// );
+
+	args := []uintptr{
+		uintptr(s.handle),
+		uintptr(groupID),
+	}
+
+	r1, _, err := proc_SimConnect_ClearInputGroup.Call(args...)
+	if int32(r1) < 0 {
+		return fmt.Errorf(
+			"SimConnect_ClearInputGroup for groupID %d error: %d %s",
+			groupID, r1, err,
+		)
+	}
+
+	s.sent(groupID)
+
+	return nil
+}
+
+func (s *SimConnect) SetInputGroupPriority(groupID, priority DWORD) error {
+	// SimConnect_SetInputGroupPriority(
+	//   HANDLE hSimConnect,
+	//   SIMCONNECT_INPUT_GROUP_ID GroupID,
+	//   DWORD uPriority
+	// );
+
+	args := []uintptr{
+		uintptr(s.handle),
+		uintptr(groupID),
+		uintptr(priority),
+	}
+
+	r1, _, err := proc_SimConnect_SetInputGroupPriority.Call(args...)
+	if int32(r1) < 0 {
+		return fmt.Errorf(
+			"SimConnect_SetInputGroupPriority for groupID %d priority %d error: %d %s",
+			groupID, priority, r1, err,
+		)
+	}
+
+	s.sent(groupID, priority)
+
+	return nil
+}
+
+func (s *SimConnect) SetInputGroupState(groupID, state DWORD) error {
+	// SimConnect_SetInputGroupState(
+	//   HANDLE hSimConnect,
+	//   SIMCONNECT_INPUT_GROUP_ID GroupID,
+	//   DWORD dwState
+	// );
+
+	args := []uintptr{
+		uintptr(s.handle),
+		uintptr(groupID),
+		uintptr(state),
+	}
+
+	r1, _, err := proc_SimConnect_SetInputGroupState.Call(args...)
+	if int32(r1) < 0 {
+		return fmt.Errorf(
+			"SimConnect_SetInputGroupState for groupID %d state %d error: %d %s",
+			groupID, state, r1, err,
+		)
+	}
+
+	s.sent(groupID, state)
+
+	return nil
+}
+
+func (s *SimConnect) MapClientDataNameToID(clientDataName string, clientDataID DWORD) error {
+	// SimConnect_MapClientDataNameToID(
+	//   HANDLE hSimConnect,
+	//   const char * szClientDataName,
+	//   SIMCONNECT_CLIENT_DATA_ID ClientDataID
+	// );
+
+	_clientDataName := []byte(clientDataName + "\x00")
+
+	args := []uintptr{
+		uintptr(s.handle),
+		uintptr(unsafe.Pointer(&_clientDataName[0])),
+		uintptr(clientDataID),
+	}
+
+	r1, _, err := proc_SimConnect_MapClientDataNameToID.Call(args...)
+	if int32(r1) < 0 {
+		return fmt.Errorf(
+			"SimConnect_MapClientDataNameToID for clientDataID %d '%s' error: %d %s",
+			clientDataID, clientDataName, r1, err,
+		)
+	}
+
+	s.sent(clientDataName, clientDataID)
+
+	return nil
+}
+
+func (s *SimConnect) CreateClientData(clientDataID, size, flags DWORD) error {
+	// SimConnect_CreateClientData(
+	//   HANDLE hSimConnect,
+	//   SIMCONNECT_CLIENT_DATA_ID ClientDataID,
+	//   DWORD dwSize,
+	//   SIMCONNECT_CREATE_CLIENT_DATA_FLAG Flags
+	// );
+
+	args := []uintptr{
+		uintptr(s.handle),
+		uintptr(clientDataID),
+		uintptr(size),
+		uintptr(flags),
+	}
+
+	r1, _, err := proc_SimConnect_CreateClientData.Call(args...)
+	if int32(r1) < 0 {
+		return fmt.Errorf(
+			"SimConnect_CreateClientData for clientDataID %d error: %d %s",
+			clientDataID, r1, err,
+		)
+	}
+
+	s.sent(clientDataID, size, flags)
+
+	return nil
+}
+
+func (s *SimConnect) AddToClientDataDefinition(defineID, offset, sizeOrType DWORD, epsilon float32, datumID DWORD) error {
+	// SimConnect_AddToClientDataDefinition(
+	//   HANDLE hSimConnect,
+	//   SIMCONNECT_CLIENT_DATA_DEFINITION_ID DefineID,
+	//   DWORD dwOffset,
+	//   DWORD dwSizeOrType,
+	//   float fEpsilon = 0,
+	//   DWORD DatumID = SIMCONNECT_UNUSED
+	// );
+
+	args := []uintptr{
+		uintptr(s.handle),
+		uintptr(defineID),
+		uintptr(offset),
+		uintptr(sizeOrType),
+		uintptr(math.Float32bits(epsilon)),
+		uintptr(datumID),
+	}
+
+	r1, _, err := proc_SimConnect_AddToClientDataDefinition.Call(args...)
+	if int32(r1) < 0 {
+		return fmt.Errorf(
+			"SimConnect_AddToClientDataDefinition for defineID %d error: %d %s",
+			defineID, r1, err,
+		)
+	}
+
+	s.sent(defineID, offset, sizeOrType, epsilon, datumID)
+
+	return nil
+}
+
+func (s *SimConnect) ClearClientDataDefinition(defineID DWORD) error {
+	// SimConnect_ClearClientDataDefinition(
+	//   HANDLE hSimConnect,
+	//   SIMCONNECT_CLIENT_DATA_DEFINITION_ID DefineID
+	// );
+
+	args := []uintptr{
+		uintptr(s.handle),
+		uintptr(defineID),
+	}
+
+	r1, _, err := proc_SimConnect_ClearClientDataDefinition.Call(args...)
+	if int32(r1) < 0 {
+		return fmt.Errorf(
+			"SimConnect_ClearClientDataDefinition for defineID %d error: %d %s",
+			defineID, r1, err,
+		)
+	}
+
+	s.sent(defineID)
+
+	return nil
+}
+
+func (s *SimConnect) RequestClientData(clientDataID, requestID, defineID DWORD, period ClientDataPeriod, flags ClientDataRequestFlag, origin, interval, limit DWORD) error {
+	// SimConnect_RequestClientData(
+	//   HANDLE hSimConnect,
+	//   SIMCONNECT_CLIENT_DATA_ID ClientDataID,
+	//   SIMCONNECT_DATA_REQUEST_ID RequestID,
+	//   SIMCONNECT_CLIENT_DATA_DEFINITION_ID DefineID,
+	//   SIMCONNECT_CLIENT_DATA_PERIOD Period = SIMCONNECT_CLIENT_DATA_PERIOD_ONCE,
+	//   SIMCONNECT_CLIENT_DATA_REQUEST_FLAG Flags = 0,
+	//   DWORD origin = 0,
+	//   DWORD interval = 0,
+	//   DWORD limit = 0
+	// );
+
+	args := []uintptr{
+		uintptr(s.handle),
+		uintptr(clientDataID),
+		uintptr(requestID),
+		uintptr(defineID),
+		uintptr(period),
+		uintptr(flags),
+		uintptr(origin),
+		uintptr(interval),
+		uintptr(limit),
+	}
+
+	r1, _, err := proc_SimConnect_RequestClientData.Call(args...)
+	if int32(r1) < 0 {
+		return fmt.Errorf(
+			"SimConnect_RequestClientData for clientDataID %d requestID %d error: %d %s",
+			clientDataID, requestID, r1, err,
+		)
+	}
+
+	s.sent(clientDataID, requestID, defineID, period, flags, origin, interval, limit)
+
+	return nil
+}
+
+func (s *SimConnect) SetClientData(clientDataID, defineID, flags, size DWORD, buf unsafe.Pointer) error {
+	// SimConnect_SetClientData(
+	//   HANDLE hSimConnect,
+	//   SIMCONNECT_CLIENT_DATA_ID ClientDataID,
+	//   SIMCONNECT_CLIENT_DATA_DEFINITION_ID DefineID,
+	//   SIMCONNECT_CLIENT_DATA_SET_FLAG Flags,
+	//   DWORD dwReserved,
+	//   DWORD cbUnitSize,
+	//   void * pDataSet
+	// );
+
+	args := []uintptr{
+		uintptr(s.handle),
+		uintptr(clientDataID),
+		uintptr(defineID),
+		uintptr(flags),
+		0,
+		uintptr(size),
+		uintptr(buf),
+	}
+
+	r1, _, err := proc_SimConnect_SetClientData.Call(args...)
+	if int32(r1) < 0 {
+		return fmt.Errorf(
+			"SimConnect_SetClientData for clientDataID %d defineID %d error: %d %s",
+			clientDataID, defineID, r1, err,
+		)
+	}
+
+	s.sent(clientDataID, defineID, flags, size)
+
+	return nil
+}
+
+func (s *SimConnect) AddToFacilityDefinition(defineID DWORD, fieldName string) error {
+	// SimConnect_AddToFacilityDefinition(
+	//   HANDLE hSimConnect,
+	//   SIMCONNECT_DATA_DEFINITION_ID DefineID,
+	//   const char * FieldName
+	// );
+
+	_fieldName := []byte(fieldName + "\x00")
+
+	args := []uintptr{
+		uintptr(s.handle),
+		uintptr(defineID),
+		uintptr(unsafe.Pointer(&_fieldName[0])),
+	}
+
+	r1, _, err := proc_SimConnect_AddToFacilityDefinition.Call(args...)
+	if int32(r1) < 0 {
+		return fmt.Errorf(
+			"SimConnect_AddToFacilityDefinition for defineID %d '%s' error: %d %s",
+			defineID, fieldName, r1, err,
+		)
+	}
+
+	s.sent(defineID, fieldName)
+
+	return nil
+}
+
+func (s *SimConnect) RequestFacilityData(defineID, requestID DWORD, icao, region string) error {
+	// SimConnect_RequestFacilityData(
+	//   HANDLE hSimConnect,
+	//   SIMCONNECT_DATA_DEFINITION_ID DefineID,
+	//   SIMCONNECT_DATA_REQUEST_ID RequestID,
+	//   const char * ICAO,
+	//   const char * Region = ""
+	// );
+
+	_icao := []byte(icao + "\x00")
+	_region := []byte(region + "\x00")
+
+	args := []uintptr{
+		uintptr(s.handle),
+		uintptr(defineID),
+		uintptr(requestID),
+		uintptr(unsafe.Pointer(&_icao[0])),
+		uintptr(unsafe.Pointer(&_region[0])),
+	}
+
+	r1, _, err := proc_SimConnect_RequestFacilityData.Call(args...)
+	if int32(r1) < 0 {
+		return fmt.Errorf(
+			"SimConnect_RequestFacilityData for defineID %d requestID %d '%s' error: %d %s",
+			defineID, requestID, icao, r1, err,
+		)
+	}
+
+	s.sent(defineID, requestID, icao, region)
+
+	return nil
+}
+
+func (s *SimConnect) AICreateParkedATCAircraft(containerTitle, tailNumber, airportID string, requestID DWORD) error {
+	// SimConnect_AICreateParkedATCAircraft(
+	//   HANDLE hSimConnect,
+	//   const char * szContainerTitle,
+	//   const char * szTailNumber,
+	//   const char * szAirportID,
+	//   SIMCONNECT_DATA_REQUEST_ID RequestID
+	// );
+
+	_containerTitle := []byte(containerTitle + "\x00")
+	_tailNumber := []byte(tailNumber + "\x00")
+	_airportID := []byte(airportID + "\x00")
+
+	args := []uintptr{
+		uintptr(s.handle),
+		uintptr(unsafe.Pointer(&_containerTitle[0])),
+		uintptr(unsafe.Pointer(&_tailNumber[0])),
+		uintptr(unsafe.Pointer(&_airportID[0])),
+		uintptr(requestID),
+	}
+
+	r1, _, err := proc_SimConnect_AICreateParkedATCAircraft.Call(args...)
+	if int32(r1) < 0 {
+		return fmt.Errorf(
+			"SimConnect_AICreateParkedATCAircraft for '%s' at %s error: %d %s",
+			containerTitle, airportID, r1, err,
+		)
+	}
+
+	s.sent(containerTitle, tailNumber, airportID, requestID)
+
+	return nil
+}
+
+func (s *SimConnect) AICreateEnrouteATCAircraft(containerTitle, tailNumber string, flightNumber int32, flightPlanPath string, flightPlanPosition float64, touchAndGo bool, requestID DWORD) error {
+	// SimConnect_AICreateEnrouteATCAircraft(
+	//   HANDLE hSimConnect,
+	//   const char * szContainerTitle,
+	//   const char * szTailNumber,
+	//   int iFlightNumber,
+	//   const char * szFlightPlanPath,
+	//   double dFlightPlanPosition,
+	//   BOOL bTouchAndGo,
+	//   SIMCONNECT_DATA_REQUEST_ID RequestID
+	// );
+
+	_containerTitle := []byte(containerTitle + "\x00")
+	_tailNumber := []byte(tailNumber + "\x00")
+	_flightPlanPath := []byte(flightPlanPath + "\x00")
+
+	args := []uintptr{
+		uintptr(s.handle),
+		uintptr(unsafe.Pointer(&_containerTitle[0])),
+		uintptr(unsafe.Pointer(&_tailNumber[0])),
+		uintptr(flightNumber),
+		uintptr(unsafe.Pointer(&_flightPlanPath[0])),
+		uintptr(math.Float64bits(flightPlanPosition)),
+		uintptr(boolDWORD(touchAndGo)),
+		uintptr(requestID),
+	}
+
+	r1, _, err := proc_SimConnect_AICreateEnrouteATCAircraft.Call(args...)
+	if int32(r1) < 0 {
+		return fmt.Errorf(
+			"SimConnect_AICreateEnrouteATCAircraft for '%s' error: %d %s",
+			containerTitle, r1, err,
+		)
+	}
+
+	s.sent(containerTitle, tailNumber, flightNumber, flightPlanPath, flightPlanPosition, touchAndGo, requestID)
+
+	return nil
+}
+
+func (s *SimConnect) AICreateNonATCAircraft(containerTitle, tailNumber string, initPos DataInitPosition, requestID DWORD) error {
+	// SimConnect_AICreateNonATCAircraft(
+	//   HANDLE hSimConnect,
+	//   const char * szContainerTitle,
+	//   const char * szTailNumber,
+	//   SIMCONNECT_DATA_INITPOSITION InitPos,
+	//   SIMCONNECT_DATA_REQUEST_ID RequestID
+	// );
+
+	_containerTitle := []byte(containerTitle + "\x00")
+	_tailNumber := []byte(tailNumber + "\x00")
+
+	// structs bigger than 8 bytes are passed by reference on x64
+	args := []uintptr{
+		uintptr(s.handle),
+		uintptr(unsafe.Pointer(&_containerTitle[0])),
+		uintptr(unsafe.Pointer(&_tailNumber[0])),
+		uintptr(unsafe.Pointer(&initPos)),
+		uintptr(requestID),
+	}
+
+	r1, _, err := proc_SimConnect_AICreateNonATCAircraft.Call(args...)
+	if int32(r1) < 0 {
+		return fmt.Errorf(
+			"SimConnect_AICreateNonATCAircraft for '%s' error: %d %s",
+			containerTitle, r1, err,
+		)
+	}
+
+	s.sent(containerTitle, tailNumber, initPos, requestID)
+
+	return nil
+}
+
+func (s *SimConnect) AICreateSimulatedObject(containerTitle string, initPos DataInitPosition, requestID DWORD) error {
+	// SimConnect_AICreateSimulatedObject(
+	//   HANDLE hSimConnect,
+	//   const char * szContainerTitle,
+	//   SIMCONNECT_DATA_INITPOSITION InitPos,
+	//   SIMCONNECT_DATA_REQUEST_ID RequestID
+	// );
+
+	_containerTitle := []byte(containerTitle + "\x00")
+
+	args := []uintptr{
+		uintptr(s.handle),
+		uintptr(unsafe.Pointer(&_containerTitle[0])),
+		uintptr(unsafe.Pointer(&initPos)),
+		uintptr(requestID),
+	}
+
+	r1, _, err := proc_SimConnect_AICreateSimulatedObject.Call(args...)
+	if int32(r1) < 0 {
+		return fmt.Errorf(
+			"SimConnect_AICreateSimulatedObject for '%s' error: %d %s",
+			containerTitle, r1, err,
+		)
+	}
+
+	s.sent(containerTitle, initPos, requestID)
+
+	return nil
+}
+
+func (s *SimConnect) AIReleaseControl(objectID, requestID DWORD) error {
+	// SimConnect_AIReleaseControl(
+	//   HANDLE hSimConnect,
+	//   SIMCONNECT_OBJECT_ID ObjectID,
+	//   SIMCONNECT_DATA_REQUEST_ID RequestID
+	// );
+
+	args := []uintptr{
+		uintptr(s.handle),
+		uintptr(objectID),
+		uintptr(requestID),
+	}
+
+	r1, _, err := proc_SimConnect_AIReleaseControl.Call(args...)
+	if int32(r1) < 0 {
+		return fmt.Errorf(
+			"SimConnect_AIReleaseControl for objectID %d error: %d %s",
+			objectID, r1, err,
+		)
+	}
+
+	s.sent(objectID, requestID)
+
+	return nil
+}
+
+func (s *SimConnect) AIRemoveObject(objectID, requestID DWORD) error {
+	// SimConnect_AIRemoveObject(
+	//   HANDLE hSimConnect,
+	//   SIMCONNECT_OBJECT_ID ObjectID,
+	//   SIMCONNECT_DATA_REQUEST_ID RequestID
+	// );
+
+	args := []uintptr{
+		uintptr(s.handle),
+		uintptr(objectID),
+		uintptr(requestID),
+	}
+
+	r1, _, err := proc_SimConnect_AIRemoveObject.Call(args...)
+	if int32(r1) < 0 {
+		return fmt.Errorf(
+			"SimConnect_AIRemoveObject for objectID %d error: %d %s",
+			objectID, r1, err,
+		)
+	}
+
+	s.sent(objectID, requestID)
+
+	return nil
+}
+
+func (s *SimConnect) AISetAircraftFlightPlan(objectID DWORD, flightPlanPath string, requestID DWORD) error {
+	// SimConnect_AISetAircraftFlightPlan(
+	//   HANDLE hSimConnect,
+	//   SIMCONNECT_OBJECT_ID ObjectID,
+	//   const char * szFlightPlanPath,
+	//   SIMCONNECT_DATA_REQUEST_ID RequestID
+	// );
+
+	_flightPlanPath := []byte(flightPlanPath + "\x00")
+
+	args := []uintptr{
+		uintptr(s.handle),
+		uintptr(objectID),
+		uintptr(unsafe.Pointer(&_flightPlanPath[0])),
+		uintptr(requestID),
+	}
+
+	r1, _, err := proc_SimConnect_AISetAircraftFlightPlan.Call(args...)
+	if int32(r1) < 0 {
+		return fmt.Errorf(
+			"SimConnect_AISetAircraftFlightPlan for objectID %d '%s' error: %d %s",
+			objectID, flightPlanPath, r1, err,
+		)
+	}
+
+	s.sent(objectID, flightPlanPath, requestID)
+
+	return nil
+}
+
+func (s *SimConnect) RequestSystemState(requestID DWORD, state string) error {
+	// SimConnect_RequestSystemState(
+	//   HANDLE hSimConnect,
+	//   SIMCONNECT_DATA_REQUEST_ID RequestID,
+	//   const char * szState
+	// );
+
+	_state := []byte(state + "\x00")
+
+	args := []uintptr{
+		uintptr(s.handle),
+		uintptr(requestID),
+		uintptr(unsafe.Pointer(&_state[0])),
+	}
+
+	r1, _, err := proc_SimConnect_RequestSystemState.Call(args...)
+	if int32(r1) < 0 {
+		return fmt.Errorf(
+			"SimConnect_RequestSystemState for requestID %d '%s' error: %d %s",
+			requestID, state, r1, err,
+		)
+	}
+
+	s.sent(requestID, state)
+
+	return nil
+}
+
+func (s *SimConnect) FlightLoad(fileName string) error {
+	// SimConnect_FlightLoad(
+	//   HANDLE hSimConnect,
+	//   const char * szFileName
+	// );
+
+	_fileName := []byte(fileName + "\x00")
+
+	args := []uintptr{
+		uintptr(s.handle),
+		uintptr(unsafe.Pointer(&_fileName[0])),
+	}
+
+	r1, _, err := proc_SimConnect_FlightLoad.Call(args...)
+	if int32(r1) < 0 {
+		return fmt.Errorf("SimConnect_FlightLoad for '%s' error: %d %s", fileName, r1, err)
+	}
+
+	s.sent(fileName)
+
+	return nil
+}
+
+func (s *SimConnect) FlightSave(fileName, title, description string, flags DWORD) error {
+	// SimConnect_FlightSave(
+	//   HANDLE hSimConnect,
+	//   const char * szFileName,
+	//   const char * szTitle,
+	//   const char * szDescription,
+	//   DWORD Flags
+	// );
+
+	_fileName := []byte(fileName + "\x00")
+	_title := []byte(title + "\x00")
+	_description := []byte(description + "\x00")
+
+	args := []uintptr{
+		uintptr(s.handle),
+		uintptr(unsafe.Pointer(&_fileName[0])),
+		uintptr(unsafe.Pointer(&_title[0])),
+		uintptr(unsafe.Pointer(&_description[0])),
+		uintptr(flags),
+	}
+
+	r1, _, err := proc_SimConnect_FlightSave.Call(args...)
+	if int32(r1) < 0 {
+		return fmt.Errorf("SimConnect_FlightSave for '%s' error: %d %s", fileName, r1, err)
+	}
+
+	s.sent(fileName, title, description, flags)
+
+	return nil
+}
+
+func (s *SimConnect) FlightPlanLoad(fileName string) error {
+	// SimConnect_FlightPlanLoad(
+	//   HANDLE hSimConnect,
+	//   const char * szFileName
+	// );
+
+	_fileName := []byte(fileName + "\x00")
+
+	args := []uintptr{
+		uintptr(s.handle),
+		uintptr(unsafe.Pointer(&_fileName[0])),
+	}
+
+	r1, _, err := proc_SimConnect_FlightPlanLoad.Call(args...)
+	if int32(r1) < 0 {
+		return fmt.Errorf("SimConnect_FlightPlanLoad for '%s' error: %d %s", fileName, r1, err)
+	}
+
+	s.sent(fileName)
+
+	return nil
+}
+
+func (s *SimConnect) ShowText(textType DWORD, duration float64, eventID DWORD, text string) error {
+	// SimConnect_Text(
+	//   HANDLE hSimConnect,
+	//   SIMCONNECT_TEXT_TYPE type,
+	//   float fTimeSeconds,
+	//   SIMCONNECT_CLIENT_EVENT_ID EventID,
+	//   DWORD cbUnitSize,
+	//   void * pDataSet
+	// );
+
+	_text := []byte(text + "\x00")
+
+	args := []uintptr{
+		uintptr(s.handle),
+		uintptr(textType),
+		uintptr(duration),
+		uintptr(eventID),
+		uintptr(DWORD(len(_text))),
+		uintptr(unsafe.Pointer(&_text[0])),
+	}
+
+	r1, _, err := proc_SimConnect_Text.Call(args...)
+	if int32(r1) < 0 {
+		return fmt.Errorf(
+			"SimConnect_Text for eventID %d textType %d text '%s' error: %d %s",
+			eventID, textType, text, r1, err,
+		)
+	}
+
+	s.sent(textType, duration, eventID, text)
+
+	return nil
+}
+
+// sent records the call of the calling method with its arguments args under
+// the send ID of the packet it sent, see SentCall. calls made from several
+// goroutines at once may be recorded under each other's send ID.
+func (s *SimConnect) sent(args ...interface{}) {
+	// SimConnect_GetLastSentPacketID(
+	//   HANDLE hSimConnect,
+	//   DWORD * pdwError
+	// );
+
+	var sendID DWORD
+	r1, _, _ := proc_SimConnect_GetLastSentPacketID.Call(
+		uintptr(s.handle),
+		uintptr(unsafe.Pointer(&sendID)),
+	)
+	if int32(r1) < 0 {
+		return
+	}
+
+	s.recordCall(sendID, 2, args)
+}
+
+func (s *SimConnect) GetNextDispatch() (unsafe.Pointer, int32, error) {
+	ppData, _, r1, err := s.getNextDispatch()
+	return ppData, r1, err
+}
+
+// NextDispatch returns a copy of the next received message, or nil when none
+// is pending. unlike GetNextDispatch the returned buffer is owned by the caller
+// and stays valid after the next call.
+func (s *SimConnect) NextDispatch() ([]byte, error) {
+	ppData, ppDataLength, r1, err := s.getNextDispatch()
+	if r1 < 0 {
+		if uint32(r1) == E_FAIL {
+			// no new messages
+			return nil, nil
+		}
+		return nil, fmt.Errorf("SimConnect_GetNextDispatch error: %d %s", r1, err)
+	}
+
+	buf := make([]byte, ppDataLength)
+	copy(buf, (*[maxRecvPacketSize]byte)(ppData)[:ppDataLength:ppDataLength])
+	return buf, nil
+}
+
+func (s *SimConnect) getNextDispatch() (unsafe.Pointer, DWORD, int32, error) {
 	var ppData unsafe.Pointer
 	var ppDataLength DWORD
 
@@ -561,5 +1573,5 @@ func (s *SimConnect) GetNextDispatch() (unsafe.Pointer, int32, error) {
 		uintptr(unsafe.Pointer(&ppDataLength)),
 	)
 
-	return ppData, int32(r1), err
+	return ppData, ppDataLength, int32(r1), err
 }
@@ -0,0 +1,13 @@
+//go:build !windows
+// +build !windows
+
+package simconnect
+
+import (
+	"fmt"
+	"runtime"
+)
+
+func openLocal(name string) (Client, error) {
+	return nil, fmt.Errorf("SimConnect.dll is not available on %s, connect over the network instead", runtime.GOOS)
+}
@@ -0,0 +1,74 @@
+package simtest
+
+import "github.com/supersidor/msfs2020-go/simconnect"
+
+// createAI adds an AI object of simobjectType with the simvars vars and
+// answers requestID with its object ID.
+func (c *conn) createAI(requestID, simobjectType simconnect.DWORD, flightPlan string, vars map[string]interface{}) {
+	s := c.server
+	s.mu.Lock()
+	s.lastAIObjectID += 1
+	objectID := s.lastAIObjectID
+	s.objects[objectID] = &object{
+		simobjectType: simobjectType,
+		vars:          map[string]interface{}{},
+		flightPlan:    flightPlan,
+	}
+	for name, value := range vars {
+		s.setSimVar(objectID, name, value)
+	}
+	s.mu.Unlock()
+
+	c.send(newRecv(simconnect.RECV_ID_ASSIGNED_OBJECT_ID).
+		dword(requestID).
+		dword(objectID))
+	s.FireObjectEvent(simconnect.SYSTEM_EVENT_OBJECT_ADDED, objectID, simobjectType)
+}
+
+// removeAI removes the AI object objectID, other objects are left alone.
+func (s *Server) removeAI(objectID simconnect.DWORD) {
+	s.mu.Lock()
+	o, ok := s.objects[objectID]
+	if !ok || objectID < FirstAIObjectID {
+		s.mu.Unlock()
+		return
+	}
+	delete(s.objects, objectID)
+	s.mu.Unlock()
+
+	s.FireObjectEvent(simconnect.SYSTEM_EVENT_OBJECT_REMOVED, objectID, o.simobjectType)
+}
+
+// initPositionVars returns the simvars of an object placed at pos.
+func initPositionVars(pos simconnect.DataInitPosition) map[string]interface{} {
+	return map[string]interface{}{
+		"PLANE LATITUDE":             pos.Latitude,
+		"PLANE LONGITUDE":            pos.Longitude,
+		"PLANE ALTITUDE":             pos.Altitude,
+		"PLANE PITCH DEGREES":        pos.Pitch,
+		"PLANE BANK DEGREES":         pos.Bank,
+		"PLANE HEADING DEGREES TRUE": pos.Heading,
+		"SIM ON GROUND":              pos.OnGround,
+		"AIRSPEED TRUE":              pos.Airspeed,
+	}
+}
+
+// setFlightPlan sets the AI flight plan of objectID.
+func (s *Server) setFlightPlan(objectID simconnect.DWORD, path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if o, ok := s.objects[objectID]; ok {
+		o.flightPlan = path
+	}
+}
+
+// FlightPlan returns the flight plan path of the AI object objectID, "" if it
+// has none.
+func (s *Server) FlightPlan(objectID simconnect.DWORD) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if o, ok := s.objects[objectID]; ok {
+		return o.flightPlan
+	}
+	return ""
+}
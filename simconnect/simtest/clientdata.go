@@ -0,0 +1,284 @@
+package simtest
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/supersidor/msfs2020-go/simconnect"
+)
+
+type clientDatum struct {
+	offset  int
+	size    int
+	datumID simconnect.DWORD
+}
+
+type clientDataRequest struct {
+	requestID    simconnect.DWORD
+	clientDataID simconnect.DWORD
+	defineID     simconnect.DWORD
+	period       simconnect.ClientDataPeriod
+	flags        simconnect.ClientDataRequestFlag
+	interval     simconnect.DWORD
+	limit        simconnect.DWORD
+
+	ticks int
+	sent  int
+	last  [][]byte // last value sent, per datum
+}
+
+// clientDatumSize returns the size of a CLIENT_DATA_TYPE_* or the size in
+// bytes given instead of a type.
+func clientDatumSize(sizeOrType simconnect.DWORD) int {
+	switch sizeOrType {
+	case simconnect.CLIENT_DATA_TYPE_INT8:
+		return 1
+	case simconnect.CLIENT_DATA_TYPE_INT16:
+		return 2
+	case simconnect.CLIENT_DATA_TYPE_INT32, simconnect.CLIENT_DATA_TYPE_FLOAT32:
+		return 4
+	case simconnect.CLIENT_DATA_TYPE_INT64, simconnect.CLIENT_DATA_TYPE_FLOAT64:
+		return 8
+	}
+	return int(sizeOrType)
+}
+
+// SetClientData writes data at offset into the client data area name, the
+// way a WASM module would, creating or growing the area as needed. clients
+// with CLIENT_DATA_PERIOD_ON_SET requests on the area are sent the new data.
+func (s *Server) SetClientData(name string, offset int, data []byte) {
+	s.mu.Lock()
+	s.writeClientData(name, offset, data)
+	s.mu.Unlock()
+
+	s.clientDataSet(name)
+}
+
+// ClientData returns a copy of the client data area name, nil if it was
+// neither created nor written.
+func (s *Server) ClientData(name string) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	area, ok := s.clientData[name]
+	if !ok {
+		return nil
+	}
+	return append([]byte{}, area...)
+}
+
+// createClientData creates the client data area name if it doesn't exist,
+// s.mu has to be held.
+func (s *Server) createClientData(name string, size int) {
+	if _, ok := s.clientData[name]; !ok {
+		s.clientData[name] = make([]byte, size)
+	}
+}
+
+// writeClientData copies data into the client data area name, s.mu has to be
+// held.
+func (s *Server) writeClientData(name string, offset int, data []byte) {
+	area := s.clientData[name]
+	if end := offset + len(data); end > len(area) {
+		area = append(area, make([]byte, end-len(area))...)
+	}
+	copy(area[offset:], data)
+	s.clientData[name] = area
+}
+
+func (s *Server) clientDataSet(name string) {
+	for _, c := range s.connections() {
+		c.clientDataSet(name)
+	}
+}
+
+// clientDatums returns the datums of defineID.
+func (c *conn) clientDatums(defineID simconnect.DWORD) []clientDatum {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]clientDatum{}, c.clientDefinitions[defineID]...)
+}
+
+// clientDataName returns the name clientDataID is mapped to.
+func (c *conn) clientDataName(clientDataID simconnect.DWORD) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	name, ok := c.clientDataNames[clientDataID]
+	return name, ok
+}
+
+// setClientData handles a SetClientData data block, untagged or prefixed by
+// datum IDs with CLIENT_DATA_SET_FLAG_TAGGED.
+func (c *conn) setClientData(clientDataID, defineID, flags simconnect.DWORD, r *reader) {
+	name, ok := c.clientDataName(clientDataID)
+	datums := c.clientDatums(defineID)
+
+	type write struct {
+		offset int
+		data   []byte
+	}
+	var writes []write
+	if flags&simconnect.CLIENT_DATA_SET_FLAG_TAGGED == 0 {
+		for _, d := range datums {
+			writes = append(writes, write{d.offset, r.next(d.size)})
+		}
+	} else {
+		for len(r.buf) > 0 && !r.err {
+			datumID := r.dword()
+			for _, d := range datums {
+				if d.datumID == datumID {
+					writes = append(writes, write{d.offset, r.next(d.size)})
+					break
+				}
+			}
+		}
+	}
+	if r.err || !ok {
+		return
+	}
+
+	c.server.mu.Lock()
+	for _, w := range writes {
+		c.server.writeClientData(name, w.offset, w.data)
+	}
+	c.server.mu.Unlock()
+
+	c.server.clientDataSet(name)
+}
+
+// requestClientData handles a RequestClientData for req.period.
+func (c *conn) requestClientData(req *clientDataRequest) {
+	switch req.period {
+	case simconnect.CLIENT_DATA_PERIOD_NEVER:
+		c.mu.Lock()
+		delete(c.clientRequests, req.requestID)
+		c.mu.Unlock()
+	case simconnect.CLIENT_DATA_PERIOD_ONCE:
+		c.sendClientData(req, false)
+	default:
+		c.mu.Lock()
+		c.clientRequests[req.requestID] = req
+		c.mu.Unlock()
+	}
+}
+
+// clientDataSet answers the CLIENT_DATA_PERIOD_ON_SET requests on the client
+// data area name.
+func (c *conn) clientDataSet(name string) {
+	for _, req := range c.clientDataRequests() {
+		if req.period != simconnect.CLIENT_DATA_PERIOD_ON_SET {
+			continue
+		}
+		if n, ok := c.clientDataName(req.clientDataID); ok && n == name {
+			c.sendClientData(req, true)
+		}
+	}
+}
+
+// clientDataTick answers the CLIENT_DATA_PERIOD_VISUAL_FRAME and
+// CLIENT_DATA_PERIOD_SECOND requests, both advance once per Tick.
+func (c *conn) clientDataTick() {
+	for _, req := range c.clientDataRequests() {
+		if req.period == simconnect.CLIENT_DATA_PERIOD_ON_SET {
+			continue
+		}
+		req.ticks += 1
+		if req.interval > 0 && (req.ticks-1)%int(req.interval+1) != 0 {
+			continue
+		}
+		c.sendClientData(req, true)
+	}
+}
+
+func (c *conn) clientDataRequests() []*clientDataRequest {
+	c.mu.Lock()
+	requests := make([]*clientDataRequest, 0, len(c.clientRequests))
+	for _, req := range c.clientRequests {
+		requests = append(requests, req)
+	}
+	c.mu.Unlock()
+
+	sort.Slice(requests, func(i, j int) bool { return requests[i].requestID < requests[j].requestID })
+	return requests
+}
+
+// sendClientData sends the datums of req as RECV_ID_CLIENT_DATA, periodic
+// requests honour CLIENT_DATA_REQUEST_FLAG_CHANGED and their limit.
+func (c *conn) sendClientData(req *clientDataRequest, periodic bool) {
+	c.clientDataMu.Lock()
+	defer c.clientDataMu.Unlock()
+
+	name, ok := c.clientDataName(req.clientDataID)
+	if !ok {
+		return
+	}
+	datums := c.clientDatums(req.defineID)
+
+	values := make([][]byte, len(datums))
+	c.server.mu.Lock()
+	area := c.server.clientData[name]
+	for i, d := range datums {
+		values[i] = make([]byte, d.size)
+		if d.offset < len(area) {
+			copy(values[i], area[d.offset:])
+		}
+	}
+	c.server.mu.Unlock()
+
+	send := make([]bool, len(datums))
+	changed := false
+	for i := range datums {
+		send[i] = !periodic ||
+			req.flags&simconnect.CLIENT_DATA_REQUEST_FLAG_CHANGED == 0 ||
+			len(req.last) != len(values) ||
+			!bytes.Equal(req.last[i], values[i])
+		changed = changed || send[i]
+	}
+	if !changed {
+		return
+	}
+
+	tagged := req.flags&simconnect.CLIENT_DATA_REQUEST_FLAG_TAGGED != 0
+	if !tagged {
+		for i := range send {
+			send[i] = true
+		}
+	}
+	if len(req.last) != len(values) {
+		req.last = make([][]byte, len(values))
+	}
+
+	w := &writer{}
+	count := simconnect.DWORD(0)
+	for i, d := range datums {
+		if !send[i] {
+			continue
+		}
+		if tagged {
+			w.dword(d.datumID)
+		}
+		w.bytes(values[i])
+		req.last[i] = values[i]
+		count += 1
+	}
+
+	c.send(newRecv(simconnect.RECV_ID_CLIENT_DATA).
+		dword(req.requestID).
+		dword(req.clientDataID).
+		dword(req.defineID).
+		dword(simconnect.DWORD(req.flags)).
+		dword(1).
+		dword(1).
+		dword(count).
+		bytes(w.buf))
+
+	if !periodic {
+		return
+	}
+	req.sent += 1
+	if req.limit > 0 && req.sent >= int(req.limit) {
+		c.mu.Lock()
+		delete(c.clientRequests, req.requestID)
+		c.mu.Unlock()
+	}
+}
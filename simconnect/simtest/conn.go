@@ -0,0 +1,840 @@
+package simtest
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"net"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/supersidor/msfs2020-go/simconnect"
+)
+
+const (
+	unknownGroup     simconnect.DWORD = 0xffffffff
+	maxPacketSize                     = 1 << 20
+	packetTypeMask   simconnect.DWORD = 0xF0000000
+	packetHeaderSize                  = 16
+)
+
+type datum struct {
+	name     string
+	unit     string
+	dataType simconnect.DWORD
+	epsilon  float32
+	datumID  simconnect.DWORD
+}
+
+type inputEvent struct {
+	downEventID simconnect.DWORD
+	downValue   simconnect.DWORD
+	upEventID   simconnect.DWORD
+	upValue     simconnect.DWORD
+}
+
+type inputGroup struct {
+	state  simconnect.DWORD
+	events map[string]inputEvent // by input definition
+}
+
+type dataRequest struct {
+	requestID simconnect.DWORD
+	defineID  simconnect.DWORD
+	objectID  simconnect.DWORD
+	flags     simconnect.DataRequestFlag
+	interval  simconnect.DWORD
+	limit     simconnect.DWORD
+
+	ticks int
+	sent  int
+	last  []interface{} // last value sent, per datum
+}
+
+// conn is the server side of one client connection.
+type conn struct {
+	net.Conn
+	server *Server
+
+	writeMu  sync.Mutex
+	protocol simconnect.DWORD
+
+	mu           sync.Mutex
+	definitions  map[simconnect.DWORD][]datum
+	systemEvents map[simconnect.DWORD]*systemEvent
+	clientEvents map[simconnect.DWORD]string
+	inputGroups  map[simconnect.DWORD]*inputGroup
+	requests     map[simconnect.DWORD]*dataRequest
+
+	clientDataMu      sync.Mutex // serializes sendClientData, ON_SET requests are answered from any connection
+	clientDataNames   map[simconnect.DWORD]string
+	clientDefinitions map[simconnect.DWORD][]clientDatum
+	clientRequests    map[simconnect.DWORD]*clientDataRequest
+
+	facilitySubscriptions    map[simconnect.DWORD]facilitySubscription // by FACILITY_LIST_TYPE_*
+	facilitySubscriptionsEX1 map[simconnect.DWORD]facilitySubscription
+	facilityDefinitions      map[simconnect.DWORD][]string // field names by define ID
+}
+
+func newConn(s *Server, nc net.Conn) *conn {
+	return &conn{
+		Conn:         nc,
+		server:       s,
+		definitions:  map[simconnect.DWORD][]datum{},
+		systemEvents: map[simconnect.DWORD]*systemEvent{},
+		clientEvents: map[simconnect.DWORD]string{},
+		inputGroups:  map[simconnect.DWORD]*inputGroup{},
+		requests:     map[simconnect.DWORD]*dataRequest{},
+
+		clientDataNames:   map[simconnect.DWORD]string{},
+		clientDefinitions: map[simconnect.DWORD][]clientDatum{},
+		clientRequests:    map[simconnect.DWORD]*clientDataRequest{},
+
+		facilitySubscriptions:    map[simconnect.DWORD]facilitySubscription{},
+		facilitySubscriptionsEX1: map[simconnect.DWORD]facilitySubscription{},
+		facilityDefinitions:      map[simconnect.DWORD][]string{},
+	}
+}
+
+func (c *conn) serve() {
+	defer c.Close()
+
+	header := make([]byte, packetHeaderSize)
+	for {
+		if _, err := io.ReadFull(c, header); err != nil {
+			return
+		}
+
+		size := binary.LittleEndian.Uint32(header[0:])
+		if size < packetHeaderSize || size > maxPacketSize {
+			return
+		}
+
+		body := make([]byte, size-packetHeaderSize)
+		if _, err := io.ReadFull(c, body); err != nil {
+			return
+		}
+
+		c.writeMu.Lock()
+		c.protocol = simconnect.DWORD(binary.LittleEndian.Uint32(header[4:]))
+		c.writeMu.Unlock()
+
+		packetType := simconnect.DWORD(binary.LittleEndian.Uint32(header[8:])) &^ packetTypeMask
+		sendID := simconnect.DWORD(binary.LittleEndian.Uint32(header[12:]))
+
+		if exception, ok := c.server.takeFailure(packetType); ok {
+			c.sendException(exception, sendID, 0)
+			continue
+		}
+
+		r := &reader{buf: body}
+		c.handle(packetType, r)
+		if r.err {
+			c.sendException(simconnect.EXCEPTION_SIZE_MISMATCH, sendID, 0)
+		}
+	}
+}
+
+func (c *conn) handle(packetType simconnect.DWORD, r *reader) {
+	switch packetType {
+	case simconnect.PACKET_OPEN:
+		c.send(newRecv(simconnect.RECV_ID_OPEN).
+			string(c.server.ApplicationName, 256).
+			dword(11).dword(0).dword(62651).dword(3). // application version and build
+			dword(11).dword(0).dword(62651).dword(3). // simconnect version and build
+			dword(0).dword(0))
+
+	case simconnect.PACKET_ADD_TO_DATA_DEFINITION:
+		defineID := r.dword()
+		d := datum{name: r.string(256), unit: r.string(256), dataType: r.dword()}
+		d.epsilon = r.float32()
+		d.datumID = r.dword()
+
+		c.mu.Lock()
+		c.definitions[defineID] = append(c.definitions[defineID], d)
+		c.mu.Unlock()
+
+	case simconnect.PACKET_CLEAR_DATA_DEFINITION:
+		defineID := r.dword()
+
+		c.mu.Lock()
+		delete(c.definitions, defineID)
+		c.mu.Unlock()
+
+	case simconnect.PACKET_SUBSCRIBE_TO_SYSTEM_EVENT:
+		eventID := r.dword()
+		name := r.string(256)
+
+		c.mu.Lock()
+		c.systemEvents[eventID] = &systemEvent{name: name, state: simconnect.STATE_ON}
+		c.mu.Unlock()
+
+	case simconnect.PACKET_UNSUBSCRIBE_FROM_SYSTEM_EVENT:
+		eventID := r.dword()
+
+		c.mu.Lock()
+		delete(c.systemEvents, eventID)
+		c.mu.Unlock()
+
+	case simconnect.PACKET_SET_SYSTEM_EVENT_STATE:
+		eventID := r.dword()
+		state := r.dword()
+		if r.err {
+			break
+		}
+
+		c.mu.Lock()
+		if e, ok := c.systemEvents[eventID]; ok {
+			e.state = state
+		}
+		c.mu.Unlock()
+
+	case simconnect.PACKET_MAP_CLIENT_EVENT_TO_SIM_EVENT:
+		eventID := r.dword()
+		name := r.string(256)
+
+		c.mu.Lock()
+		c.clientEvents[eventID] = name
+		c.mu.Unlock()
+
+	case simconnect.PACKET_TRANSMIT_CLIENT_EVENT:
+		e := Event{ObjectID: c.server.resolveObject(r.dword())}
+		eventID := r.dword()
+		e.Data = r.dword()
+		if r.err {
+			break
+		}
+
+		c.mu.Lock()
+		e.Name = c.clientEvents[eventID]
+		c.mu.Unlock()
+
+		c.server.mu.Lock()
+		c.server.events = append(c.server.events, e)
+		c.server.mu.Unlock()
+
+	case simconnect.PACKET_MAP_INPUT_EVENT_TO_CLIENT_EVENT:
+		groupID := r.dword()
+		input := r.string(256)
+		e := inputEvent{downEventID: r.dword(), downValue: r.dword(), upEventID: r.dword(), upValue: r.dword()}
+		r.dword() // maskable
+		if r.err {
+			break
+		}
+
+		c.mu.Lock()
+		c.inputGroup(groupID).events[inputKey(input)] = e
+		c.mu.Unlock()
+
+	case simconnect.PACKET_REMOVE_INPUT_EVENT:
+		groupID := r.dword()
+		input := r.string(256)
+
+		c.mu.Lock()
+		delete(c.inputGroup(groupID).events, inputKey(input))
+		c.mu.Unlock()
+
+	case simconnect.PACKET_CLEAR_INPUT_GROUP:
+		groupID := r.dword()
+
+		c.mu.Lock()
+		c.inputGroup(groupID).events = map[string]inputEvent{}
+		c.mu.Unlock()
+
+	case simconnect.PACKET_SET_INPUT_GROUP_PRIORITY:
+		r.dword() // group
+		r.dword() // priority
+
+	case simconnect.PACKET_SET_INPUT_GROUP_STATE:
+		groupID := r.dword()
+		state := r.dword()
+
+		c.mu.Lock()
+		c.inputGroup(groupID).state = state
+		c.mu.Unlock()
+
+	case simconnect.PACKET_REQUEST_DATA_ON_SIMOBJECT:
+		req := &dataRequest{
+			requestID: r.dword(),
+			defineID:  r.dword(),
+			objectID:  c.server.resolveObject(r.dword()),
+		}
+		period := simconnect.Period(r.dword())
+		req.flags = simconnect.DataRequestFlag(r.dword())
+		r.dword() // origin
+		req.interval = r.dword()
+		req.limit = r.dword()
+
+		switch period {
+		case simconnect.PERIOD_NEVER:
+			c.mu.Lock()
+			delete(c.requests, req.requestID)
+			c.mu.Unlock()
+		case simconnect.PERIOD_ONCE:
+			c.sendData(simconnect.RECV_ID_SIMOBJECT_DATA, req, req.objectID, 1, 1)
+		default:
+			c.mu.Lock()
+			c.requests[req.requestID] = req
+			c.mu.Unlock()
+		}
+
+	case simconnect.PACKET_REQUEST_DATA_ON_SIMOBJECT_TYPE:
+		req := &dataRequest{requestID: r.dword(), defineID: r.dword()}
+		radius := r.dword()
+		simobjectType := r.dword()
+
+		objectIDs := c.server.objectsOfType(simobjectType, radius)
+		for i, objectID := range objectIDs {
+			c.sendData(simconnect.RECV_ID_SIMOBJECT_DATA_BYTYPE, req, objectID, simconnect.DWORD(i+1), simconnect.DWORD(len(objectIDs)))
+		}
+
+	case simconnect.PACKET_SET_DATA_ON_SIMOBJECT:
+		defineID := r.dword()
+		objectID := r.dword()
+		r.dword() // flags
+		arrayCount := r.dword()
+		r.dword() // unit size
+		if arrayCount == 0 {
+			arrayCount = 1
+		}
+
+		c.mu.Lock()
+		datums := c.definitions[defineID]
+		c.mu.Unlock()
+
+		values := make([][]interface{}, len(datums))
+		for i := simconnect.DWORD(0); i < arrayCount && !r.err; i++ {
+			for j, d := range datums {
+				values[j] = append(values[j], decodeDatum(r, d.dataType))
+			}
+		}
+		if r.err {
+			break
+		}
+
+		c.server.mu.Lock()
+		for j, d := range datums {
+			if arrayCount == 1 {
+				c.server.setSimVar(objectID, d.name, values[j][0])
+			} else {
+				c.server.setSimVar(objectID, d.name, arrayValue(d.dataType, values[j]))
+			}
+		}
+		c.server.mu.Unlock()
+
+	case simconnect.PACKET_SUBSCRIBE_TO_FACILITIES:
+		facilityType := r.dword()
+		requestID := r.dword()
+		if r.err {
+			break
+		}
+
+		c.mu.Lock()
+		c.facilitySubscriptions[facilityType] = facilitySubscription{inRange: requestID, outRange: simconnect.UNUSED}
+		c.mu.Unlock()
+
+		c.sendFacilities(facilityType, requestID, c.server.facilityList(facilityType))
+
+	case simconnect.PACKET_UNSUBSCRIBE_TO_FACILITIES:
+		facilityType := r.dword()
+
+		c.mu.Lock()
+		delete(c.facilitySubscriptions, facilityType)
+		c.mu.Unlock()
+
+	case simconnect.PACKET_REQUEST_FACILITIES_LIST, simconnect.PACKET_REQUEST_FACILITIES_LIST_EX1:
+		facilityType := r.dword()
+		requestID := r.dword()
+		if r.err {
+			break
+		}
+
+		c.sendFacilities(facilityType, requestID, c.server.facilityList(facilityType))
+
+	case simconnect.PACKET_SUBSCRIBE_TO_FACILITIES_EX1:
+		facilityType := r.dword()
+		sub := facilitySubscription{inRange: r.dword(), outRange: r.dword()}
+		if r.err {
+			break
+		}
+
+		c.mu.Lock()
+		c.facilitySubscriptionsEX1[facilityType] = sub
+		c.mu.Unlock()
+
+		c.sendFacilities(facilityType, sub.inRange, c.server.facilityList(facilityType))
+
+	case simconnect.PACKET_UNSUBSCRIBE_TO_FACILITIES_EX1:
+		facilityType := r.dword()
+		inRange := r.dword() != 0
+		outRange := r.dword() != 0
+
+		c.mu.Lock()
+		sub, ok := c.facilitySubscriptionsEX1[facilityType]
+		if !ok {
+			c.mu.Unlock()
+			break
+		}
+		if inRange {
+			sub.inRange = simconnect.UNUSED
+		}
+		if outRange {
+			sub.outRange = simconnect.UNUSED
+		}
+		if sub.inRange == simconnect.UNUSED && sub.outRange == simconnect.UNUSED {
+			delete(c.facilitySubscriptionsEX1, facilityType)
+		} else {
+			c.facilitySubscriptionsEX1[facilityType] = sub
+		}
+		c.mu.Unlock()
+
+	case simconnect.PACKET_ADD_TO_FACILITY_DEFINITION:
+		defineID := r.dword()
+		fieldName := r.string(256)
+		if r.err {
+			break
+		}
+
+		c.mu.Lock()
+		c.facilityDefinitions[defineID] = append(c.facilityDefinitions[defineID], fieldName)
+		c.mu.Unlock()
+
+	case simconnect.PACKET_REQUEST_FACILITY_DATA:
+		defineID := r.dword()
+		requestID := r.dword()
+		icao := r.string(256)
+		r.string(256) // region
+		if r.err {
+			break
+		}
+
+		c.requestFacilityData(defineID, requestID, icao)
+
+	case simconnect.PACKET_AI_CREATE_PARKED_ATC_AIRCRAFT:
+		title := r.string(256)
+		tailNumber := r.string(12)
+		r.string(5) // airport
+		requestID := r.dword()
+		if r.err {
+			break
+		}
+
+		c.createAI(requestID, simconnect.SIMOBJECT_TYPE_AIRCRAFT, "", map[string]interface{}{
+			"TITLE":         title,
+			"ATC ID":        tailNumber,
+			"SIM ON GROUND": 1,
+		})
+
+	case simconnect.PACKET_AI_CREATE_ENROUTE_ATC_AIRCRAFT:
+		title := r.string(256)
+		tailNumber := r.string(12)
+		flightNumber := int32(r.dword())
+		flightPlanPath := r.string(260)
+		r.float64() // flight plan position
+		r.dword()   // touch and go
+		requestID := r.dword()
+		if r.err {
+			break
+		}
+
+		c.createAI(requestID, simconnect.SIMOBJECT_TYPE_AIRCRAFT, flightPlanPath, map[string]interface{}{
+			"TITLE":             title,
+			"ATC ID":            tailNumber,
+			"ATC FLIGHT NUMBER": strconv.Itoa(int(flightNumber)),
+		})
+
+	case simconnect.PACKET_AI_CREATE_NON_ATC_AIRCRAFT:
+		title := r.string(256)
+		tailNumber := r.string(12)
+		pos := decodeDatum(r, simconnect.DATATYPE_INITPOSITION).(simconnect.DataInitPosition)
+		requestID := r.dword()
+		if r.err {
+			break
+		}
+
+		vars := initPositionVars(pos)
+		vars["TITLE"] = title
+		vars["ATC ID"] = tailNumber
+		c.createAI(requestID, simconnect.SIMOBJECT_TYPE_AIRCRAFT, "", vars)
+
+	case simconnect.PACKET_AI_CREATE_SIMULATED_OBJECT:
+		title := r.string(256)
+		pos := decodeDatum(r, simconnect.DATATYPE_INITPOSITION).(simconnect.DataInitPosition)
+		requestID := r.dword()
+		if r.err {
+			break
+		}
+
+		vars := initPositionVars(pos)
+		vars["TITLE"] = title
+		c.createAI(requestID, simconnect.SIMOBJECT_TYPE_GROUND, "", vars)
+
+	case simconnect.PACKET_AI_RELEASE_CONTROL:
+		// released objects are flown by the simulator, nothing to fake
+
+	case simconnect.PACKET_AI_REMOVE_OBJECT:
+		objectID := r.dword()
+		if r.err {
+			break
+		}
+
+		c.server.removeAI(objectID)
+
+	case simconnect.PACKET_AI_SET_AIRCRAFT_FLIGHT_PLAN:
+		objectID := r.dword()
+		flightPlanPath := r.string(260)
+		if r.err {
+			break
+		}
+
+		c.server.setFlightPlan(objectID, flightPlanPath)
+
+	case simconnect.PACKET_REQUEST_SYSTEM_STATE:
+		requestID := r.dword()
+		state := r.string(256)
+		if r.err {
+			break
+		}
+
+		c.sendSystemState(requestID, state)
+
+	case simconnect.PACKET_FLIGHT_LOAD:
+		fileName := r.string(260)
+		if r.err {
+			break
+		}
+
+		c.server.SetSystemState(simconnect.SYSTEM_STATE_FLIGHT_LOADED, 0, 0, fileName)
+		c.server.FireFilenameEvent(simconnect.SYSTEM_EVENT_FLIGHT_LOADED, fileName, 0)
+
+	case simconnect.PACKET_FLIGHT_SAVE:
+		f := SavedFlight{
+			FileName:    r.string(260),
+			Title:       r.string(260),
+			Description: r.string(2048),
+		}
+		r.dword() // flags
+		if r.err {
+			break
+		}
+
+		c.server.mu.Lock()
+		c.server.savedFlights = append(c.server.savedFlights, f)
+		c.server.mu.Unlock()
+		c.server.FireFilenameEvent(simconnect.SYSTEM_EVENT_FLIGHT_SAVED, f.FileName, 0)
+
+	case simconnect.PACKET_FLIGHT_PLAN_LOAD:
+		fileName := r.string(260)
+		if r.err {
+			break
+		}
+
+		c.server.SetSystemState(simconnect.SYSTEM_STATE_FLIGHT_PLAN, 0, 0, fileName)
+		c.server.FireFilenameEvent(simconnect.SYSTEM_EVENT_FLIGHT_PLAN_ACTIVATED, fileName, 0)
+
+	case simconnect.PACKET_MAP_CLIENT_DATA_NAME_TO_ID:
+		name := r.string(256)
+		clientDataID := r.dword()
+
+		c.mu.Lock()
+		c.clientDataNames[clientDataID] = name
+		c.mu.Unlock()
+
+	case simconnect.PACKET_CREATE_CLIENT_DATA:
+		clientDataID := r.dword()
+		size := r.dword()
+		r.dword() // flags
+
+		name, ok := c.clientDataName(clientDataID)
+		if !ok || r.err {
+			break
+		}
+
+		c.server.mu.Lock()
+		c.server.createClientData(name, int(size))
+		c.server.mu.Unlock()
+
+	case simconnect.PACKET_ADD_TO_CLIENT_DATA_DEFINITION:
+		defineID := r.dword()
+		offset := r.dword()
+		d := clientDatum{size: clientDatumSize(r.dword())}
+		r.float32() // epsilon
+		d.datumID = r.dword()
+
+		c.mu.Lock()
+		datums := c.clientDefinitions[defineID]
+		d.offset = int(offset)
+		if offset == simconnect.CLIENTDATAOFFSET_AUTO {
+			d.offset = 0
+			if n := len(datums); n > 0 {
+				d.offset = datums[n-1].offset + datums[n-1].size
+			}
+		}
+		c.clientDefinitions[defineID] = append(datums, d)
+		c.mu.Unlock()
+
+	case simconnect.PACKET_CLEAR_CLIENT_DATA_DEFINITION:
+		defineID := r.dword()
+
+		c.mu.Lock()
+		delete(c.clientDefinitions, defineID)
+		c.mu.Unlock()
+
+	case simconnect.PACKET_REQUEST_CLIENT_DATA:
+		req := &clientDataRequest{
+			clientDataID: r.dword(),
+			requestID:    r.dword(),
+			defineID:     r.dword(),
+			period:       simconnect.ClientDataPeriod(r.dword()),
+			flags:        simconnect.ClientDataRequestFlag(r.dword()),
+		}
+		r.dword() // origin
+		req.interval = r.dword()
+		req.limit = r.dword()
+		if r.err {
+			break
+		}
+
+		c.requestClientData(req)
+
+	case simconnect.PACKET_SET_CLIENT_DATA:
+		clientDataID := r.dword()
+		defineID := r.dword()
+		flags := r.dword()
+		r.dword() // reserved
+		r.dword() // size
+
+		c.setClientData(clientDataID, defineID, flags, r)
+	}
+}
+
+// objectsOfType returns the IDs of the objects matching a SIMOBJECT_TYPE_*, a
+// radius of zero only matches the user aircraft.
+func (s *Server) objectsOfType(simobjectType, radius simconnect.DWORD) []simconnect.DWORD {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ids []simconnect.DWORD
+	for id, o := range s.objects {
+		user := o.simobjectType == simconnect.SIMOBJECT_TYPE_USER
+
+		switch {
+		case simobjectType == simconnect.SIMOBJECT_TYPE_USER || radius == 0:
+			if !user {
+				continue
+			}
+		case simobjectType == simconnect.SIMOBJECT_TYPE_ALL:
+		case simobjectType == simconnect.SIMOBJECT_TYPE_AIRCRAFT && user:
+		case o.simobjectType != simobjectType:
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// values returns the current values of the datums of defineID.
+func (c *conn) values(defineID, objectID simconnect.DWORD) ([]datum, []interface{}) {
+	c.mu.Lock()
+	datums := c.definitions[defineID]
+	c.mu.Unlock()
+
+	values := make([]interface{}, len(datums))
+	c.server.mu.Lock()
+	for i, d := range datums {
+		values[i] = c.server.simVar(objectID, d.name)
+	}
+	c.server.mu.Unlock()
+
+	return datums, values
+}
+
+// packData lays out the datums for which send is true, untagged or prefixed
+// with their datum ID when the request is DATA_REQUEST_FLAG_TAGGED.
+func packData(req *dataRequest, datums []datum, values []interface{}, send []bool) ([]byte, simconnect.DWORD) {
+	w := &writer{}
+	count := simconnect.DWORD(0)
+	for i, d := range datums {
+		if !send[i] {
+			continue
+		}
+		if req.flags&simconnect.DATA_REQUEST_FLAG_TAGGED != 0 {
+			w.dword(d.datumID)
+		}
+		encodeDatum(w, d.dataType, values[i])
+		count += 1
+	}
+	return w.buf, count
+}
+
+func (c *conn) sendData(recvID simconnect.DWORD, req *dataRequest, objectID, entryNumber, outOf simconnect.DWORD) {
+	datums, values := c.values(req.defineID, objectID)
+	send := make([]bool, len(datums))
+	for i := range send {
+		send[i] = true
+	}
+
+	data, count := packData(req, datums, values, send)
+	c.sendPacked(recvID, req, objectID, entryNumber, outOf, data, count)
+}
+
+func (c *conn) sendPacked(recvID simconnect.DWORD, req *dataRequest, objectID, entryNumber, outOf simconnect.DWORD, data []byte, count simconnect.DWORD) {
+	c.send(newRecv(recvID).
+		dword(req.requestID).
+		dword(objectID).
+		dword(req.defineID).
+		dword(simconnect.DWORD(req.flags)).
+		dword(entryNumber).
+		dword(outOf).
+		dword(count).
+		bytes(data))
+}
+
+func (c *conn) tick() {
+	defer c.clientDataTick()
+
+	c.mu.Lock()
+	requests := make([]*dataRequest, 0, len(c.requests))
+	for _, req := range c.requests {
+		requests = append(requests, req)
+	}
+	c.mu.Unlock()
+
+	sort.Slice(requests, func(i, j int) bool { return requests[i].requestID < requests[j].requestID })
+
+	for _, req := range requests {
+		req.ticks += 1
+		if req.interval > 0 && (req.ticks-1)%int(req.interval+1) != 0 {
+			continue
+		}
+
+		datums, values := c.values(req.defineID, req.objectID)
+
+		// with DATA_REQUEST_FLAG_CHANGED only changed datums are sent when
+		// tagged, untagged data is sent in full if any datum changed.
+		send := make([]bool, len(datums))
+		changed := false
+		for i, d := range datums {
+			send[i] = req.flags&simconnect.DATA_REQUEST_FLAG_CHANGED == 0 ||
+				len(req.last) != len(values) ||
+				valueChanged(d, req.last[i], values[i])
+			changed = changed || send[i]
+		}
+		if !changed {
+			continue
+		}
+		if req.flags&simconnect.DATA_REQUEST_FLAG_TAGGED == 0 {
+			for i := range send {
+				send[i] = true
+			}
+		}
+
+		// compare against the last value sent, not the last value seen
+		if len(req.last) != len(values) {
+			req.last = make([]interface{}, len(values))
+		}
+		for i := range values {
+			if send[i] {
+				req.last[i] = values[i]
+			}
+		}
+
+		data, count := packData(req, datums, values, send)
+		c.sendPacked(simconnect.RECV_ID_SIMOBJECT_DATA, req, req.objectID, 1, 1, data, count)
+
+		req.sent += 1
+		if req.limit > 0 && req.sent >= int(req.limit) {
+			c.mu.Lock()
+			delete(c.requests, req.requestID)
+			c.mu.Unlock()
+		}
+	}
+}
+
+// valueChanged reports whether a datum changed by more than its epsilon.
+func valueChanged(d datum, last, value interface{}) bool {
+	a, ok1 := last.(float64)
+	b, ok2 := value.(float64)
+	if ok1 && ok2 {
+		return math.Abs(b-a) > float64(d.epsilon)
+	}
+	return !reflect.DeepEqual(last, value)
+}
+
+// inputGroup returns the input group groupID, creating it disabled, c.mu has
+// to be held.
+func (c *conn) inputGroup(groupID simconnect.DWORD) *inputGroup {
+	g, ok := c.inputGroups[groupID]
+	if !ok {
+		g = &inputGroup{state: simconnect.STATE_OFF, events: map[string]inputEvent{}}
+		c.inputGroups[groupID] = g
+	}
+	return g
+}
+
+// input sends the client events mapped to input in enabled input groups.
+func (c *conn) input(input string, down bool) {
+	type event struct{ groupID, eventID, data simconnect.DWORD }
+
+	c.mu.Lock()
+	var events []event
+	for groupID, g := range c.inputGroups {
+		e, ok := g.events[inputKey(input)]
+		if !ok || g.state != simconnect.STATE_ON {
+			continue
+		}
+		if down && e.downEventID != simconnect.UNUSED {
+			events = append(events, event{groupID, e.downEventID, e.downValue})
+		}
+		if !down && e.upEventID != simconnect.UNUSED {
+			events = append(events, event{groupID, e.upEventID, e.upValue})
+		}
+	}
+	c.mu.Unlock()
+
+	sort.Slice(events, func(i, j int) bool { return events[i].groupID < events[j].groupID })
+
+	for _, e := range events {
+		c.send(newRecv(simconnect.RECV_ID_EVENT).
+			dword(e.groupID).
+			dword(e.eventID).
+			dword(e.data))
+	}
+}
+
+// inputKey normalizes an input definition, they are compared ignoring case
+// and spaces.
+func inputKey(input string) string {
+	return strings.ToUpper(strings.Replace(input, " ", "", -1))
+}
+
+func (c *conn) sendException(exception, sendID, index simconnect.DWORD) {
+	c.send(newRecv(simconnect.RECV_ID_EXCEPTION).
+		dword(exception).
+		dword(sendID).
+		dword(index))
+}
+
+func (c *conn) send(w *writer) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.Write(w.finish(c.protocol))
+}
+
+// arrayValue turns the values of one datum written with an array count into
+// the simvar value, waypoint lists are kept as []simconnect.DataWaypoint.
+func arrayValue(dataType simconnect.DWORD, values []interface{}) interface{} {
+	if dataType != simconnect.DATATYPE_WAYPOINT {
+		return values
+	}
+	list := make([]simconnect.DataWaypoint, len(values))
+	for i, v := range values {
+		list[i] = v.(simconnect.DataWaypoint)
+	}
+	return list
+}
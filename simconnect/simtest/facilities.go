@@ -0,0 +1,149 @@
+package simtest
+
+import (
+	"reflect"
+
+	"github.com/supersidor/msfs2020-go/simconnect"
+)
+
+// defaultFacilitiesPerMessage is used when Server.FacilitiesPerMessage is 0.
+const defaultFacilitiesPerMessage = 64
+
+// facilitySubscription holds the request IDs of a SubscribeToFacilities or
+// SubscribeToFacilitiesEX1 subscription, UNUSED once unsubscribed.
+type facilitySubscription struct {
+	inRange  simconnect.DWORD
+	outRange simconnect.DWORD
+}
+
+// SetFacilities replaces the facilities of facilityType, list is a slice of
+// the matching simconnect.DataFacility* struct. subscribed clients are sent
+// the facilities added, EX1 subscriptions the removed ones as well.
+func (s *Server) SetFacilities(facilityType simconnect.DWORD, list interface{}) {
+	v := reflect.ValueOf(list)
+	entries := make([]interface{}, v.Len())
+	for i := range entries {
+		entries[i] = v.Index(i).Interface()
+	}
+
+	s.mu.Lock()
+	old := s.facilities[facilityType]
+	s.facilities[facilityType] = entries
+	s.mu.Unlock()
+
+	added := subtract(entries, old)
+	removed := subtract(old, entries)
+	for _, c := range s.connections() {
+		c.facilitiesChanged(facilityType, added, removed)
+	}
+}
+
+// subtract returns the entries of a missing in b.
+func subtract(a, b []interface{}) []interface{} {
+	var diff []interface{}
+	for _, x := range a {
+		found := false
+		for _, y := range b {
+			if reflect.DeepEqual(x, y) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			diff = append(diff, x)
+		}
+	}
+	return diff
+}
+
+func (s *Server) facilityList(facilityType simconnect.DWORD) []interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.facilities[facilityType]
+}
+
+func (c *conn) facilitiesChanged(facilityType simconnect.DWORD, added, removed []interface{}) {
+	c.mu.Lock()
+	sub, ok := c.facilitySubscriptions[facilityType]
+	ex1, okEX1 := c.facilitySubscriptionsEX1[facilityType]
+	c.mu.Unlock()
+
+	if ok && len(added) > 0 {
+		c.sendFacilities(facilityType, sub.inRange, added)
+	}
+	if okEX1 && ex1.inRange != simconnect.UNUSED && len(added) > 0 {
+		c.sendFacilities(facilityType, ex1.inRange, added)
+	}
+	if okEX1 && ex1.outRange != simconnect.UNUSED && len(removed) > 0 {
+		c.sendFacilities(facilityType, ex1.outRange, removed)
+	}
+}
+
+// sendFacilities sends entries as a facility list split into messages of
+// Server.FacilitiesPerMessage entries, an empty list as one empty message.
+func (c *conn) sendFacilities(facilityType, requestID simconnect.DWORD, entries []interface{}) {
+	var recvID simconnect.DWORD
+	switch facilityType {
+	case simconnect.FACILITY_LIST_TYPE_AIRPORT:
+		recvID = simconnect.RECV_ID_AIRPORT_LIST
+	case simconnect.FACILITY_LIST_TYPE_WAYPOINT:
+		recvID = simconnect.RECV_ID_WAYPOINT_LIST
+	case simconnect.FACILITY_LIST_TYPE_NDB:
+		recvID = simconnect.RECV_ID_NDB_LIST
+	case simconnect.FACILITY_LIST_TYPE_VOR:
+		recvID = simconnect.RECV_ID_VOR_LIST
+	default:
+		return
+	}
+
+	perMessage := c.server.FacilitiesPerMessage
+	if perMessage <= 0 {
+		perMessage = defaultFacilitiesPerMessage
+	}
+	outOf := (len(entries) + perMessage - 1) / perMessage
+	if outOf == 0 {
+		outOf = 1
+	}
+
+	for i := 0; i < outOf; i++ {
+		part := entries[i*perMessage:]
+		if len(part) > perMessage {
+			part = part[:perMessage]
+		}
+
+		w := newRecv(recvID).
+			dword(requestID).
+			dword(simconnect.DWORD(len(part))).
+			dword(simconnect.DWORD(i)).
+			dword(simconnect.DWORD(outOf))
+		for _, e := range part {
+			encodeFacility(w, e)
+		}
+		c.send(w)
+	}
+}
+
+// encodeFacility appends a simconnect.DataFacility* struct.
+func encodeFacility(w *writer, e interface{}) {
+	switch f := e.(type) {
+	case simconnect.DataFacilityAirport:
+		w.string(f.Icao, 9).
+			float64(f.Latitude).
+			float64(f.Longitude).
+			float64(f.Altitude)
+	case simconnect.DataFacilityWaypoint:
+		encodeFacility(w, f.DataFacilityAirport)
+		w.float32(f.MagVar)
+	case simconnect.DataFacilityNDB:
+		encodeFacility(w, f.DataFacilityWaypoint)
+		w.dword(f.Frequency)
+	case simconnect.DataFacilityVOR:
+		encodeFacility(w, f.DataFacilityNDB)
+		w.dword(f.Flags).
+			float32(f.Localizer).
+			float64(f.GlideLat).
+			float64(f.GlideLon).
+			float64(f.GlideAlt).
+			float32(f.GlideSlopeAngle)
+	}
+}
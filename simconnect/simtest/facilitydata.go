@@ -0,0 +1,227 @@
+package simtest
+
+import (
+	"strings"
+
+	"github.com/supersidor/msfs2020-go/simconnect"
+)
+
+// FacilityObject is an object of a facility tree served to
+// RequestFacilityData, e.g. an airport or one of its runways.
+type FacilityObject struct {
+	// Fields holds the values by SDK field name, e.g. "LATITUDE". values are
+	// float64, float32, int32 or string and are sent in the size of the
+	// field, fields missing are sent as zero.
+	Fields map[string]interface{}
+
+	// Children holds the child objects by the name the definition opens them
+	// with, e.g. "RUNWAY" or "PRIMARY_THRESHOLD".
+	Children map[string][]*FacilityObject
+}
+
+// facilityDataTypes maps object names to their FACILITY_DATA_* type.
+var facilityDataTypes = map[string]simconnect.DWORD{
+	"AIRPORT":             simconnect.FACILITY_DATA_AIRPORT,
+	"RUNWAY":              simconnect.FACILITY_DATA_RUNWAY,
+	"START":               simconnect.FACILITY_DATA_START,
+	"FREQUENCY":           simconnect.FACILITY_DATA_FREQUENCY,
+	"HELIPAD":             simconnect.FACILITY_DATA_HELIPAD,
+	"APPROACH":            simconnect.FACILITY_DATA_APPROACH,
+	"APPROACH_TRANSITION": simconnect.FACILITY_DATA_APPROACH_TRANSITION,
+	"APPROACH_LEG":        simconnect.FACILITY_DATA_APPROACH_LEG,
+	"FINAL_APPROACH_LEG":  simconnect.FACILITY_DATA_FINAL_APPROACH_LEG,
+	"MISSED_APPROACH_LEG": simconnect.FACILITY_DATA_MISSED_APPROACH_LEG,
+	"DEPARTURE":           simconnect.FACILITY_DATA_DEPARTURE,
+	"ARRIVAL":             simconnect.FACILITY_DATA_ARRIVAL,
+	"RUNWAY_TRANSITION":   simconnect.FACILITY_DATA_RUNWAY_TRANSITION,
+	"ENROUTE_TRANSITION":  simconnect.FACILITY_DATA_ENROUTE_TRANSITION,
+	"VOR":                 simconnect.FACILITY_DATA_VOR,
+	"NDB":                 simconnect.FACILITY_DATA_NDB,
+	"WAYPOINT":            simconnect.FACILITY_DATA_WAYPOINT,
+}
+
+// facilityDataType returns the FACILITY_DATA_* type of the object name and
+// whether it is sent as a list item. the objects of a runway end are single
+// objects.
+func facilityDataType(name string) (simconnect.DWORD, bool) {
+	if strings.HasPrefix(name, "PRIMARY_") || strings.HasPrefix(name, "SECONDARY_") {
+		switch {
+		case strings.HasSuffix(name, "_APPROACH_LIGHTS"):
+			return simconnect.FACILITY_DATA_APPROACH_LIGHTS, false
+		case strings.HasSuffix(name, "_VASI"):
+			return simconnect.FACILITY_DATA_VASI, false
+		default:
+			return simconnect.FACILITY_DATA_PAVEMENT, false
+		}
+	}
+	return facilityDataTypes[name], true
+}
+
+// facilityFieldSize returns the size in bytes of the field name of the object
+// parent, the fields not listed are 4 bytes.
+func facilityFieldSize(parent, name string) int {
+	switch name {
+	case "LATITUDE", "LONGITUDE", "ALTITUDE",
+		"FIX_LATITUDE", "FIX_LONGITUDE", "FIX_ALTITUDE":
+		return 8
+	case "ICAO", "REGION", "FAF_ICAO", "FAF_REGION", "IAF_ICAO",
+		"FIX_ICAO", "FIX_REGION":
+		return 8
+	case "NAME64":
+		return 64
+	case "NAME":
+		switch parent {
+		case "AIRPORT":
+			return 32
+		case "FREQUENCY":
+			return 64
+		}
+		return 8
+	}
+	return 4
+}
+
+// facilityNode is an object of a facility definition.
+type facilityNode struct {
+	name     string
+	fields   []string
+	children []*facilityNode
+}
+
+// parseFacilityDefinition turns the field names added to a facility
+// definition into its objects, nil if the definition is not balanced.
+func parseFacilityDefinition(entries []string) *facilityNode {
+	var root *facilityNode
+	var stack []*facilityNode
+	for _, entry := range entries {
+		switch {
+		case strings.HasPrefix(entry, "OPEN "):
+			n := &facilityNode{name: strings.TrimPrefix(entry, "OPEN ")}
+			if len(stack) == 0 {
+				if root != nil {
+					return nil
+				}
+				root = n
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+			}
+			stack = append(stack, n)
+		case strings.HasPrefix(entry, "CLOSE "):
+			if len(stack) == 0 || stack[len(stack)-1].name != strings.TrimPrefix(entry, "CLOSE ") {
+				return nil
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return nil
+			}
+			n := stack[len(stack)-1]
+			n.fields = append(n.fields, entry)
+		}
+	}
+	if len(stack) > 0 {
+		return nil
+	}
+	return root
+}
+
+// SetFacilityData sets the facility tree served for icao, nil removes it.
+func (s *Server) SetFacilityData(icao string, root *FacilityObject) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if root == nil {
+		delete(s.facilityData, icao)
+		return
+	}
+	s.facilityData[icao] = root
+}
+
+func (s *Server) facilityObject(icao string) *FacilityObject {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.facilityData[icao]
+}
+
+// requestFacilityData sends the objects of the facility icao the definition
+// defineID asks for, parents before their children, and ends with
+// RECV_ID_FACILITY_DATA_END. unknown facilities only get the end.
+func (c *conn) requestFacilityData(defineID, requestID simconnect.DWORD, icao string) {
+	c.mu.Lock()
+	def := parseFacilityDefinition(c.facilityDefinitions[defineID])
+	c.mu.Unlock()
+
+	root := c.server.facilityObject(icao)
+	if def != nil && root != nil {
+		var uniqueID simconnect.DWORD
+		var send func(n *facilityNode, o *FacilityObject, parentID simconnect.DWORD, list bool, index, size int)
+		send = func(n *facilityNode, o *FacilityObject, parentID simconnect.DWORD, list bool, index, size int) {
+			uniqueID += 1
+			id := uniqueID
+			dataType, _ := facilityDataType(n.name)
+
+			w := newRecv(simconnect.RECV_ID_FACILITY_DATA).
+				dword(requestID).
+				dword(id).
+				dword(parentID).
+				dword(dataType).
+				dword(boolDWORD(list)).
+				dword(simconnect.DWORD(index)).
+				dword(simconnect.DWORD(size))
+			for _, field := range n.fields {
+				encodeFacilityField(w, facilityFieldSize(n.name, field), o.Fields[field])
+			}
+			c.send(w)
+
+			for _, child := range n.children {
+				objects := o.Children[child.name]
+				_, isList := facilityDataType(child.name)
+				for i, co := range objects {
+					send(child, co, id, isList, i, len(objects))
+					if !isList {
+						break
+					}
+				}
+			}
+		}
+		send(def, root, 0, false, 0, 0)
+	}
+
+	c.send(newRecv(simconnect.RECV_ID_FACILITY_DATA_END).dword(requestID))
+}
+
+// encodeFacilityField appends value in size bytes.
+func encodeFacilityField(w *writer, size int, value interface{}) {
+	switch v := value.(type) {
+	case string:
+		w.string(v, size)
+		return
+	case float64:
+		if size == 8 {
+			w.float64(v)
+		} else {
+			w.float32(float32(v))
+		}
+		return
+	case float32:
+		if size == 8 {
+			w.float64(float64(v))
+		} else {
+			w.float32(v)
+		}
+		return
+	case int32:
+		if size == 4 {
+			w.dword(simconnect.DWORD(v))
+			return
+		}
+	}
+	w.bytes(make([]byte, size))
+}
+
+func boolDWORD(b bool) simconnect.DWORD {
+	if b {
+		return 1
+	}
+	return 0
+}
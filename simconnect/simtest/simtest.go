@@ -0,0 +1,333 @@
+// Package simtest provides an in-process fake SimConnect server for tests.
+//
+// the server speaks the SimConnect network protocol, clients connect to it
+// with simconnect.Dial(name, srv.Addr()). it keeps a table of simvar values
+// per object, answers data requests with correctly laid out
+// RECV_ID_SIMOBJECT_DATA(_BYTYPE) packets, applies SetDataOnSimObject writes
+// and can fire scripted system events and exceptions. transmitted client
+// events are recorded, see Server.Events, mapped inputs can be pressed with
+// Server.PressInput and facility lists are served from Server.SetFacilities.
+// AI objects created by clients are added as objects, see FirstAIObjectID.
+package simtest
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/supersidor/msfs2020-go/simconnect"
+)
+
+// UserObjectID is the object ID of the user aircraft, OBJECT_ID_USER requests
+// are answered with it.
+const UserObjectID simconnect.DWORD = 1
+
+// FirstAIObjectID is the object ID of the first AI object created by a
+// client, the next ones count up from it.
+const FirstAIObjectID simconnect.DWORD = 100
+
+type object struct {
+	simobjectType simconnect.DWORD
+	vars          map[string]interface{}
+	flightPlan    string // AI flight plan path
+}
+
+// Server is a fake SimConnect server listening on a local tcp port.
+type Server struct {
+	// ApplicationName is reported to clients in RECV_ID_OPEN.
+	ApplicationName string
+
+	// FacilitiesPerMessage is the most facilities sent in one facility list
+	// message, longer lists are split. defaults to 64.
+	FacilitiesPerMessage int
+
+	ln net.Listener
+	wg sync.WaitGroup
+
+	mu      sync.Mutex
+	objects map[simconnect.DWORD]*object
+	conns   map[*conn]bool
+	fail    map[simconnect.DWORD]simconnect.DWORD
+	events  []Event
+
+	lastAIObjectID simconnect.DWORD
+	systemStates   map[string]systemState
+	savedFlights   []SavedFlight
+
+	clientData   map[string][]byte                  // client data areas by name
+	facilities   map[simconnect.DWORD][]interface{} // by FACILITY_LIST_TYPE_*
+	facilityData map[string]*FacilityObject         // facility trees by ICAO
+}
+
+// Event is a client event transmitted with TransmitClientEvent.
+type Event struct {
+	ObjectID simconnect.DWORD
+	Name     string // sim event the client event is mapped to, empty if unmapped
+	Data     simconnect.DWORD
+}
+
+// NewServer starts a server on 127.0.0.1 with the user aircraft as the only
+// object. it panics if it can't listen, like httptest.NewServer.
+func NewServer() *Server {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(fmt.Sprintf("simtest: failed to listen: %v", err))
+	}
+
+	s := &Server{
+		ApplicationName: "KittyHawk",
+		ln:              ln,
+		objects:         map[simconnect.DWORD]*object{},
+		conns:           map[*conn]bool{},
+		fail:            map[simconnect.DWORD]simconnect.DWORD{},
+		lastAIObjectID:  FirstAIObjectID - 1,
+		systemStates: map[string]systemState{
+			simconnect.SYSTEM_STATE_SIM:             {integer: 1},
+			simconnect.SYSTEM_STATE_AIRCRAFT_LOADED: {str: `SimObjects\Airplanes\Asobo_C172sp_AS1000\aircraft.cfg`},
+		},
+		clientData:   map[string][]byte{},
+		facilities:   map[simconnect.DWORD][]interface{}{},
+		facilityData: map[string]*FacilityObject{},
+	}
+	s.AddObject(UserObjectID, simconnect.SIMOBJECT_TYPE_USER)
+
+	s.wg.Add(1)
+	go s.serve()
+
+	return s
+}
+
+// Addr returns the "host:port" clients should dial.
+func (s *Server) Addr() string {
+	return s.ln.Addr().String()
+}
+
+// Close stops listening and drops every client connection.
+func (s *Server) Close() {
+	s.ln.Close()
+
+	s.mu.Lock()
+	for c := range s.conns {
+		c.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+
+	for {
+		nc, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+
+		c := newConn(s, nc)
+		s.mu.Lock()
+		s.conns[c] = true
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			c.serve()
+
+			s.mu.Lock()
+			delete(s.conns, c)
+			s.mu.Unlock()
+		}()
+	}
+}
+
+// ConnectionCount returns the number of connected clients.
+func (s *Server) ConnectionCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.conns)
+}
+
+// AddObject adds a simobject of the given SIMOBJECT_TYPE_* so it shows up in
+// RequestDataOnSimObjectType results. the user aircraft should use
+// SIMOBJECT_TYPE_USER.
+func (s *Server) AddObject(objectID, simobjectType simconnect.DWORD) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if o, ok := s.objects[objectID]; ok {
+		o.simobjectType = simobjectType
+		return
+	}
+	s.objects[objectID] = &object{simobjectType: simobjectType, vars: map[string]interface{}{}}
+}
+
+// RemoveObject removes a simobject and its simvars.
+func (s *Server) RemoveObject(objectID simconnect.DWORD) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, objectID)
+}
+
+// SetSimVar sets a simvar of an object, adding the object if needed. numbers
+// are kept as float64 and converted to the requested DATATYPE_* when sent,
+// strings and the simconnect.Data* structs are kept as they are. values
+// written with an array count are kept as a []interface{}, waypoint lists as
+// a []simconnect.DataWaypoint.
+func (s *Server) SetSimVar(objectID simconnect.DWORD, name string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.setSimVar(objectID, name, value)
+}
+
+func (s *Server) setSimVar(objectID simconnect.DWORD, name string, value interface{}) {
+	objectID = s.resolveObject(objectID)
+	o, ok := s.objects[objectID]
+	if !ok {
+		o = &object{simobjectType: simconnect.SIMOBJECT_TYPE_AIRCRAFT, vars: map[string]interface{}{}}
+		s.objects[objectID] = o
+	}
+
+	switch v := value.(type) {
+	case string, []interface{}, []simconnect.DataWaypoint,
+		simconnect.DataInitPosition, simconnect.DataMarkerState, simconnect.DataWaypoint,
+		simconnect.DataLatLonAlt, simconnect.DataXYZ:
+		o.vars[simvarKey(name)] = v
+	default:
+		o.vars[simvarKey(name)] = toFloat64(value)
+	}
+}
+
+// SimVar returns the current value of a simvar, nil if it was never set.
+func (s *Server) SimVar(objectID simconnect.DWORD, name string) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.objects[s.resolveObject(objectID)]
+	if !ok {
+		return nil
+	}
+	return o.vars[simvarKey(name)]
+}
+
+func (s *Server) simVar(objectID simconnect.DWORD, name string) interface{} {
+	o, ok := s.objects[objectID]
+	if !ok {
+		return nil
+	}
+	return o.vars[simvarKey(name)]
+}
+
+func (s *Server) resolveObject(objectID simconnect.DWORD) simconnect.DWORD {
+	if objectID == simconnect.OBJECT_ID_USER {
+		return UserObjectID
+	}
+	return objectID
+}
+
+// SendException sends a RECV_ID_EXCEPTION to every client.
+func (s *Server) SendException(exception, sendID, index simconnect.DWORD) {
+	for _, c := range s.connections() {
+		c.sendException(exception, sendID, index)
+	}
+}
+
+// PressInput sends the down events mapped to input (e.g. "Shift+Ctrl+B" or
+// "joystick:0:button:3") in every enabled input group.
+func (s *Server) PressInput(input string) {
+	for _, c := range s.connections() {
+		c.input(input, true)
+	}
+}
+
+// ReleaseInput sends the up events mapped to input.
+func (s *Server) ReleaseInput(input string) {
+	for _, c := range s.connections() {
+		c.input(input, false)
+	}
+}
+
+// Events returns the client events transmitted so far, oldest first.
+func (s *Server) Events() []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Event{}, s.events...)
+}
+
+// FailNext makes the server answer the next packet of packetType
+// (simconnect.PACKET_*) with the given exception instead of handling it.
+func (s *Server) FailNext(packetType, exception simconnect.DWORD) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fail[packetType] = exception
+}
+
+func (s *Server) takeFailure(packetType simconnect.DWORD) (simconnect.DWORD, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exception, ok := s.fail[packetType]
+	if ok {
+		delete(s.fail, packetType)
+	}
+	return exception, ok
+}
+
+// Tick advances every periodic data and client data request by one period, sending data for
+// the ones that are due.
+func (s *Server) Tick() {
+	for _, c := range s.connections() {
+		c.tick()
+	}
+}
+
+// Quit sends RECV_ID_QUIT to every client and drops the connections, like
+// the simulator does when it shuts down.
+func (s *Server) Quit() {
+	for _, c := range s.connections() {
+		c.send(newRecv(simconnect.RECV_ID_QUIT))
+		c.Close()
+	}
+}
+
+func (s *Server) connections() []*conn {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	conns := make([]*conn, 0, len(s.conns))
+	for c := range s.conns {
+		conns = append(conns, c)
+	}
+	return conns
+}
+
+func simvarKey(name string) string {
+	return strings.ToUpper(strings.TrimSpace(name))
+}
+
+func toFloat64(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case float32:
+		return float64(v)
+	case int:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint32:
+		return float64(v)
+	case simconnect.DWORD:
+		return float64(v)
+	case bool:
+		if v {
+			return 1
+		}
+		return 0
+	}
+	return math.NaN()
+}
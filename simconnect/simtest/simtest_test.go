@@ -0,0 +1,313 @@
+package simtest_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/supersidor/msfs2020-go/simconnect"
+	"github.com/supersidor/msfs2020-go/simconnect/simtest"
+)
+
+// Report is laid out like the vfrmap report, with a title, floats, a
+// DWORD and a bool.
+type Report struct {
+	simconnect.RecvSimobjectDataByType
+	Title     [256]byte `name:"TITLE"`
+	Altitude  float64   `name:"INDICATED ALTITUDE" unit:"feet"`
+	Latitude  float64   `name:"PLANE LATITUDE" unit:"degrees"`
+	Longitude float64   `name:"PLANE LONGITUDE" unit:"degrees"`
+	Heading   float64   `name:"PLANE HEADING DEGREES TRUE" unit:"degrees" epsilon:"0.5"`
+	Lights    uint32    `name:"LIGHT ON STATES" unit:"mask"`
+	OnGround  bool      `name:"SIM ON GROUND" unit:"bool"`
+}
+
+func (r *Report) title() string {
+	return strings.TrimRight(string(r.Title[:]), "\x00")
+}
+
+// TeleportRequest is the vfrmap teleport definition.
+type TeleportRequest struct {
+	Position simconnect.DataInitPosition `name:"Initial Position" unit:"NULL"`
+}
+
+// DWORD saves spelling out the package in the tables.
+type DWORD = simconnect.DWORD
+
+const timeout = 2 * time.Second
+
+// start returns a server, a client connected to it and a running dispatcher,
+// all closed when the test ends.
+func start(t *testing.T) (*simtest.Server, *simconnect.NetSimConnect, *simconnect.Dispatcher) {
+	srv := simtest.NewServer()
+	t.Cleanup(srv.Close)
+
+	s, err := simconnect.Dial("simtest", srv.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	d := simconnect.NewDispatcher(s)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	d.Start(ctx)
+
+	return srv, s, d
+}
+
+func setUser(srv *simtest.Server, title string, altitude float64) {
+	srv.SetSimVar(simtest.UserObjectID, "TITLE", title)
+	srv.SetSimVar(simtest.UserObjectID, "INDICATED ALTITUDE", altitude)
+	srv.SetSimVar(simtest.UserObjectID, "PLANE LATITUDE", 47.5)
+	srv.SetSimVar(simtest.UserObjectID, "PLANE LONGITUDE", 8.5)
+	srv.SetSimVar(simtest.UserObjectID, "PLANE HEADING DEGREES TRUE", 270)
+	srv.SetSimVar(simtest.UserObjectID, "LIGHT ON STATES", 5)
+	srv.SetSimVar(simtest.UserObjectID, "SIM ON GROUND", 1)
+}
+
+func TestRegisterDataDefinition(t *testing.T) {
+	_, s, _ := start(t)
+
+	if err := s.RegisterDataDefinition(&Report{}); err != nil {
+		t.Fatal(err)
+	}
+	defineID := s.GetDefineID(&Report{})
+	if again := s.GetDefineID(&Report{}); again != defineID {
+		t.Errorf("define ID of the same type changed from %d to %d", defineID, again)
+	}
+	if other := s.GetDefineID(&TeleportRequest{}); other == defineID {
+		t.Errorf("TeleportRequest shares define ID %d with Report", defineID)
+	}
+
+	type unsupported struct {
+		Values [4]float64 `name:"PLANE LATITUDE" unit:"degrees"`
+	}
+	if err := s.RegisterDataDefinition(&unsupported{}); err == nil {
+		t.Error("registering a [4]float64 field succeeded")
+	}
+}
+
+func TestRequestData(t *testing.T) {
+	srv, _, d := start(t)
+	setUser(srv, "Cessna Skyhawk", 1500)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var r Report
+	if err := d.RequestOnce(ctx, &r, simconnect.OBJECT_ID_USER); err != nil {
+		t.Fatal(err)
+	}
+	if r.ObjectID != simtest.UserObjectID {
+		t.Errorf("object ID %d, want %d", r.ObjectID, simtest.UserObjectID)
+	}
+	if r.title() != "Cessna Skyhawk" || r.Altitude != 1500 || r.Latitude != 47.5 || r.Longitude != 8.5 ||
+		r.Heading != 270 || r.Lights != 5 || !r.OnGround {
+		t.Errorf("got %q %v %v %v %v %v %v", r.title(), r.Altitude, r.Latitude, r.Longitude, r.Heading, r.Lights, r.OnGround)
+	}
+}
+
+func TestRequestDataByType(t *testing.T) {
+	srv, _, d := start(t)
+	setUser(srv, "Cessna Skyhawk", 1500)
+	srv.AddObject(2, simconnect.SIMOBJECT_TYPE_AIRCRAFT)
+	srv.SetSimVar(2, "TITLE", "Airbus A320")
+	srv.SetSimVar(2, "INDICATED ALTITUDE", 33000)
+	srv.AddObject(3, simconnect.SIMOBJECT_TYPE_BOAT)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var reports []Report
+	if err := d.RequestByType(ctx, &reports, 10000, simconnect.SIMOBJECT_TYPE_AIRCRAFT); err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 2 {
+		t.Fatalf("got %d reports, want the user aircraft and object 2", len(reports))
+	}
+	for i, want := range []struct {
+		objectID DWORD
+		title    string
+		altitude float64
+	}{
+		{simtest.UserObjectID, "Cessna Skyhawk", 1500},
+		{2, "Airbus A320", 33000},
+	} {
+		r := reports[i]
+		if r.ObjectID != want.objectID || r.EntryNumber != DWORD(i+1) || r.OutOf != 2 {
+			t.Errorf("report %d: object %d entry %d of %d", i, r.ObjectID, r.EntryNumber, r.OutOf)
+		}
+		if r.title() != want.title || r.Altitude != want.altitude {
+			t.Errorf("report %d: got %q at %v, want %q at %v", i, r.title(), r.Altitude, want.title, want.altitude)
+		}
+	}
+
+	// radius 0 only matches the user aircraft
+	if err := d.RequestByType(ctx, &reports, 0, simconnect.SIMOBJECT_TYPE_AIRCRAFT); err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 1 || reports[0].ObjectID != simtest.UserObjectID {
+		t.Errorf("radius 0 returned %d reports", len(reports))
+	}
+}
+
+func TestSubscribeTagged(t *testing.T) {
+	srv, _, d := start(t)
+	setUser(srv, "Cessna Skyhawk", 1500)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	reports := make(chan Report, 4)
+	opts := &simconnect.SubscribeOptions{
+		Flags: simconnect.DATA_REQUEST_FLAG_CHANGED | simconnect.DATA_REQUEST_FLAG_TAGGED,
+		Limit: 2,
+	}
+	if err := d.Subscribe(ctx, reports, simconnect.OBJECT_ID_USER, simconnect.PERIOD_SECOND, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	r := tickReport(t, srv, reports)
+	if r.title() != "Cessna Skyhawk" || r.Altitude != 1500 {
+		t.Fatalf("first report %q at %v", r.title(), r.Altitude)
+	}
+
+	// below the heading epsilon, only the altitude is sent and the rest kept
+	srv.SetSimVar(simtest.UserObjectID, "INDICATED ALTITUDE", 1600)
+	srv.SetSimVar(simtest.UserObjectID, "PLANE HEADING DEGREES TRUE", 270.2)
+	r = tickReport(t, srv, reports)
+	if r.title() != "Cessna Skyhawk" || r.Altitude != 1600 || r.Heading != 270 {
+		t.Errorf("second report %q at %v heading %v", r.title(), r.Altitude, r.Heading)
+	}
+
+	select {
+	case r, ok := <-reports:
+		if ok {
+			t.Errorf("report past the limit: %+v", r)
+		}
+	case <-ctx.Done():
+		t.Error("channel not closed after the limit")
+	}
+}
+
+// tickReport ticks the server until the next report arrives, the request may
+// not have been read by the server yet.
+func tickReport(t *testing.T, srv *simtest.Server, reports chan Report) Report {
+	deadline := time.After(timeout)
+	for {
+		srv.Tick()
+		select {
+		case r, ok := <-reports:
+			if !ok {
+				t.Fatal("channel closed")
+			}
+			return r
+		case <-time.After(10 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("no report")
+		}
+	}
+}
+
+func TestSetData(t *testing.T) {
+	srv, s, _ := start(t)
+
+	if err := s.RegisterDataDefinition(&TeleportRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	pos := simconnect.DataInitPosition{
+		Latitude:  46.25,
+		Longitude: 7.5,
+		Altitude:  5000,
+		Heading:   90,
+		Airspeed:  120,
+	}
+	if err := s.SetData(simconnect.OBJECT_ID_USER, &TeleportRequest{Position: pos}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the write is applied when the server reads the packet
+	deadline := time.Now().Add(timeout)
+	for {
+		v := srv.SimVar(simtest.UserObjectID, "Initial Position")
+		if v == pos {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Initial Position is %+v, want %+v", v, pos)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSystemEvent(t *testing.T) {
+	srv, _, d := start(t)
+
+	events := make(chan *simconnect.RecvEvent, 1)
+	eventID, err := d.HandleSystemEvent(simconnect.SYSTEM_EVENT_PAUSE, func(msg interface{}) {
+		if e, ok := msg.(*simconnect.RecvEvent); ok {
+			select {
+			case events <- e:
+			default:
+			}
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the subscription is sent before the event is fired, wait for the
+	// server to read it
+	deadline := time.After(timeout)
+	for {
+		srv.FireSystemEvent(simconnect.SYSTEM_EVENT_PAUSE, 1)
+		select {
+		case e := <-events:
+			if e.EventID != eventID || e.Data != 1 {
+				t.Errorf("got event %d with %d, want %d with 1", e.EventID, e.Data, eventID)
+			}
+			return
+		case <-time.After(10 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("no event")
+		}
+	}
+}
+
+func TestException(t *testing.T) {
+	srv, s, d := start(t)
+
+	errs := make(chan *simconnect.ExceptionError, 1)
+	d.Error = func(err *simconnect.ExceptionError) {
+		errs <- err
+	}
+
+	srv.FailNext(simconnect.PACKET_ADD_TO_DATA_DEFINITION, simconnect.EXCEPTION_NAME_UNRECOGNIZED)
+	if err := s.RegisterDataDefinition(&Report{}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-errs:
+		if err.Exception != simconnect.EXCEPTION_NAME_UNRECOGNIZED {
+			t.Errorf("exception %d, want %d", err.Exception, simconnect.EXCEPTION_NAME_UNRECOGNIZED)
+		}
+		if err.Call == nil || err.Call.Method != "AddToDataDefinition" || err.Call.Args[1] != "TITLE" {
+			t.Errorf("exception names call %v, want the TITLE AddToDataDefinition", err.Call)
+		}
+	case <-time.After(timeout):
+		t.Fatal("no exception")
+	}
+
+	srv.SendException(simconnect.EXCEPTION_ERROR, 0, 3)
+	select {
+	case err := <-errs:
+		if err.Exception != simconnect.EXCEPTION_ERROR || err.Index != 3 || err.Call != nil {
+			t.Errorf("got %v, want EXCEPTION_ERROR at index 3 for an unknown call", err)
+		}
+	case <-time.After(timeout):
+		t.Fatal("no exception")
+	}
+}
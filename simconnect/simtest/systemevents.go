@@ -0,0 +1,79 @@
+package simtest
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/supersidor/msfs2020-go/simconnect"
+)
+
+// systemEvent is a system event subscription of a client.
+type systemEvent struct {
+	name  string
+	state simconnect.DWORD // STATE_OFF while turned off with SetSystemEventState
+}
+
+// FireSystemEvent sends a RECV_ID_EVENT to every client subscribed to the
+// system event name (e.g. simconnect.SYSTEM_EVENT_SIM_START).
+func (s *Server) FireSystemEvent(name simconnect.SystemEvent, data simconnect.DWORD) {
+	for _, c := range s.connections() {
+		c.fireSystemEvent(name, simconnect.RECV_ID_EVENT, data, nil)
+	}
+}
+
+// FireFilenameEvent sends a RECV_ID_EVENT_FILENAME for fileName, e.g. of
+// simconnect.SYSTEM_EVENT_FLIGHT_LOADED.
+func (s *Server) FireFilenameEvent(name simconnect.SystemEvent, fileName string, flags simconnect.DWORD) {
+	for _, c := range s.connections() {
+		c.fireSystemEvent(name, simconnect.RECV_ID_EVENT_FILENAME, 0, func(w *writer) {
+			w.string(fileName, 260).dword(flags)
+		})
+	}
+}
+
+// FireFrameEvent sends a RECV_ID_EVENT_FRAME of simconnect.SYSTEM_EVENT_FRAME
+// or SYSTEM_EVENT_PAUSE_FRAME.
+func (s *Server) FireFrameEvent(name simconnect.SystemEvent, frameRate, simSpeed float32) {
+	for _, c := range s.connections() {
+		c.fireSystemEvent(name, simconnect.RECV_ID_EVENT_FRAME, 0, func(w *writer) {
+			w.float32(frameRate).float32(simSpeed)
+		})
+	}
+}
+
+// FireObjectEvent sends a RECV_ID_EVENT_OBJECT_ADDREMOVE of
+// simconnect.SYSTEM_EVENT_OBJECT_ADDED or SYSTEM_EVENT_OBJECT_REMOVED. AI
+// objects created and removed by clients fire them as well.
+func (s *Server) FireObjectEvent(name simconnect.SystemEvent, objectID, simobjectType simconnect.DWORD) {
+	for _, c := range s.connections() {
+		c.fireSystemEvent(name, simconnect.RECV_ID_EVENT_OBJECT_ADDREMOVE, objectID, func(w *writer) {
+			w.dword(simobjectType)
+		})
+	}
+}
+
+// fireSystemEvent sends a recvID message to every subscription of name that
+// is on, payload appends what follows the RECV_ID_EVENT fields.
+func (c *conn) fireSystemEvent(name simconnect.SystemEvent, recvID, data simconnect.DWORD, payload func(w *writer)) {
+	c.mu.Lock()
+	var eventIDs []simconnect.DWORD
+	for eventID, e := range c.systemEvents {
+		if strings.EqualFold(e.name, string(name)) && e.state != simconnect.STATE_OFF {
+			eventIDs = append(eventIDs, eventID)
+		}
+	}
+	c.mu.Unlock()
+
+	sort.Slice(eventIDs, func(i, j int) bool { return eventIDs[i] < eventIDs[j] })
+
+	for _, eventID := range eventIDs {
+		w := newRecv(recvID).
+			dword(unknownGroup).
+			dword(eventID).
+			dword(data)
+		if payload != nil {
+			payload(w)
+		}
+		c.send(w)
+	}
+}
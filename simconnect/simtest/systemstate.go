@@ -0,0 +1,45 @@
+package simtest
+
+import "github.com/supersidor/msfs2020-go/simconnect"
+
+// systemState is the answer to RequestSystemState of one state.
+type systemState struct {
+	integer simconnect.DWORD
+	float   float32
+	str     string
+}
+
+// SavedFlight is a flight saved with FlightSave.
+type SavedFlight struct {
+	FileName    string
+	Title       string
+	Description string
+}
+
+// SetSystemState sets the answer to RequestSystemState of state (e.g.
+// simconnect.SYSTEM_STATE_SIM). FlightLoad and FlightPlanLoad set
+// FlightLoaded and FlightPlan, states never set are answered with zeros.
+func (s *Server) SetSystemState(state string, integer simconnect.DWORD, float float32, str string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.systemStates[state] = systemState{integer: integer, float: float, str: str}
+}
+
+// SavedFlights returns the flights saved so far, oldest first.
+func (s *Server) SavedFlights() []SavedFlight {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]SavedFlight{}, s.savedFlights...)
+}
+
+func (c *conn) sendSystemState(requestID simconnect.DWORD, state string) {
+	c.server.mu.Lock()
+	st := c.server.systemStates[state]
+	c.server.mu.Unlock()
+
+	c.send(newRecv(simconnect.RECV_ID_SYSTEM_STATE).
+		dword(requestID).
+		dword(st.integer).
+		float32(st.float).
+		string(st.str, 260))
+}
@@ -0,0 +1,256 @@
+package simtest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+
+	"github.com/supersidor/msfs2020-go/simconnect"
+)
+
+// writer builds a server to client message, laid out like the SIMCONNECT_RECV
+// structures. the size is filled in when the message is sent.
+type writer struct {
+	buf []byte
+}
+
+func newRecv(id simconnect.DWORD) *writer {
+	w := &writer{buf: make([]byte, 0, 64)}
+	return w.dword(0).dword(0).dword(id)
+}
+
+func (w *writer) dword(v simconnect.DWORD) *writer {
+	w.buf = append(w.buf, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(w.buf[len(w.buf)-4:], uint32(v))
+	return w
+}
+
+func (w *writer) uint64(v uint64) *writer {
+	w.buf = append(w.buf, 0, 0, 0, 0, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint64(w.buf[len(w.buf)-8:], v)
+	return w
+}
+
+func (w *writer) float32(v float32) *writer {
+	return w.dword(simconnect.DWORD(math.Float32bits(v)))
+}
+
+func (w *writer) float64(v float64) *writer {
+	return w.uint64(math.Float64bits(v))
+}
+
+func (w *writer) string(s string, size int) *writer {
+	field := make([]byte, size)
+	copy(field[:size-1], s)
+	w.buf = append(w.buf, field...)
+	return w
+}
+
+func (w *writer) bytes(b []byte) *writer {
+	w.buf = append(w.buf, b...)
+	return w
+}
+
+func (w *writer) finish(protocol simconnect.DWORD) []byte {
+	binary.LittleEndian.PutUint32(w.buf[0:], uint32(len(w.buf)))
+	binary.LittleEndian.PutUint32(w.buf[4:], uint32(protocol))
+	return w.buf
+}
+
+// reader reads the body of a client to server packet.
+type reader struct {
+	buf []byte
+	err bool
+}
+
+func (r *reader) next(n int) []byte {
+	if len(r.buf) < n {
+		r.err = true
+		r.buf = nil
+		return make([]byte, n)
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+func (r *reader) dword() simconnect.DWORD {
+	return simconnect.DWORD(binary.LittleEndian.Uint32(r.next(4)))
+}
+
+func (r *reader) float32() float32 {
+	return math.Float32frombits(binary.LittleEndian.Uint32(r.next(4)))
+}
+
+func (r *reader) float64() float64 {
+	return math.Float64frombits(binary.LittleEndian.Uint64(r.next(8)))
+}
+
+func (r *reader) string(size int) string {
+	return cString(r.next(size))
+}
+
+func (r *reader) rest() []byte {
+	b := r.buf
+	r.buf = nil
+	return b
+}
+
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
+
+// datumSize returns the wire size of a fixed size DATATYPE_*, 0 if unknown.
+func datumSize(dataType simconnect.DWORD) int {
+	switch dataType {
+	case simconnect.DATATYPE_INT32, simconnect.DATATYPE_FLOAT32:
+		return 4
+	case simconnect.DATATYPE_INT64, simconnect.DATATYPE_FLOAT64:
+		return 8
+	case simconnect.DATATYPE_STRING8:
+		return 8
+	case simconnect.DATATYPE_STRING32:
+		return 32
+	case simconnect.DATATYPE_STRING64:
+		return 64
+	case simconnect.DATATYPE_STRING128:
+		return 128
+	case simconnect.DATATYPE_STRING256:
+		return 256
+	case simconnect.DATATYPE_STRING260:
+		return 260
+	case simconnect.DATATYPE_INITPOSITION:
+		return 56
+	case simconnect.DATATYPE_MARKERSTATE:
+		return 68
+	case simconnect.DATATYPE_WAYPOINT:
+		return 44
+	case simconnect.DATATYPE_LATLONALT, simconnect.DATATYPE_XYZ:
+		return 24
+	}
+	return 0
+}
+
+// encodeDatum appends value converted to dataType.
+func encodeDatum(w *writer, dataType simconnect.DWORD, value interface{}) {
+	var f float64
+	var s string
+	switch v := value.(type) {
+	case float64:
+		f = v
+	case string:
+		s = v
+	case []simconnect.DataWaypoint:
+		for _, wp := range v {
+			encodeDatum(w, dataType, wp)
+		}
+		return
+	case []interface{}:
+		for _, item := range v {
+			encodeDatum(w, dataType, item)
+		}
+		return
+	}
+
+	switch dataType {
+	case simconnect.DATATYPE_INT32:
+		w.dword(simconnect.DWORD(int32(f)))
+	case simconnect.DATATYPE_INT64:
+		w.uint64(uint64(int64(f)))
+	case simconnect.DATATYPE_FLOAT32:
+		w.dword(simconnect.DWORD(math.Float32bits(float32(f))))
+	case simconnect.DATATYPE_FLOAT64:
+		w.float64(f)
+	case simconnect.DATATYPE_STRINGV:
+		w.bytes(append([]byte(s), 0))
+	case simconnect.DATATYPE_INITPOSITION:
+		v, _ := value.(simconnect.DataInitPosition)
+		w.float64(v.Latitude).float64(v.Longitude).float64(v.Altitude).
+			float64(v.Pitch).float64(v.Bank).float64(v.Heading).
+			dword(v.OnGround).dword(v.Airspeed)
+	case simconnect.DATATYPE_MARKERSTATE:
+		v, _ := value.(simconnect.DataMarkerState)
+		w.string(v.MarkerName, 64).dword(v.MarkerState)
+	case simconnect.DATATYPE_WAYPOINT:
+		v, _ := value.(simconnect.DataWaypoint)
+		w.float64(v.Latitude).float64(v.Longitude).float64(v.Altitude).
+			dword(v.Flags).float64(v.KtsSpeed).float64(v.PercentThrottle)
+	case simconnect.DATATYPE_LATLONALT:
+		v, _ := value.(simconnect.DataLatLonAlt)
+		w.float64(v.Latitude).float64(v.Longitude).float64(v.Altitude)
+	case simconnect.DATATYPE_XYZ:
+		v, _ := value.(simconnect.DataXYZ)
+		w.float64(v.X).float64(v.Y).float64(v.Z)
+	default:
+		if size := datumSize(dataType); size > 0 {
+			w.string(s, size)
+		}
+	}
+}
+
+// decodeDatum reads one value of dataType, numbers are returned as float64
+// and structured types as their simconnect.Data* struct.
+func decodeDatum(r *reader, dataType simconnect.DWORD) interface{} {
+	switch dataType {
+	case simconnect.DATATYPE_INT32:
+		return float64(int32(r.dword()))
+	case simconnect.DATATYPE_INT64:
+		return float64(int64(binary.LittleEndian.Uint64(r.next(8))))
+	case simconnect.DATATYPE_FLOAT32:
+		return float64(r.float32())
+	case simconnect.DATATYPE_FLOAT64:
+		return r.float64()
+	case simconnect.DATATYPE_STRINGV:
+		i := bytes.IndexByte(r.buf, 0)
+		if i < 0 {
+			break
+		}
+		return cString(r.next(i + 1))
+	case simconnect.DATATYPE_INITPOSITION:
+		return simconnect.DataInitPosition{
+			Latitude:  r.float64(),
+			Longitude: r.float64(),
+			Altitude:  r.float64(),
+			Pitch:     r.float64(),
+			Bank:      r.float64(),
+			Heading:   r.float64(),
+			OnGround:  r.dword(),
+			Airspeed:  r.dword(),
+		}
+	case simconnect.DATATYPE_MARKERSTATE:
+		return simconnect.DataMarkerState{
+			MarkerName:  r.string(64),
+			MarkerState: r.dword(),
+		}
+	case simconnect.DATATYPE_WAYPOINT:
+		return simconnect.DataWaypoint{
+			Latitude:        r.float64(),
+			Longitude:       r.float64(),
+			Altitude:        r.float64(),
+			Flags:           r.dword(),
+			KtsSpeed:        r.float64(),
+			PercentThrottle: r.float64(),
+		}
+	case simconnect.DATATYPE_LATLONALT:
+		return simconnect.DataLatLonAlt{
+			Latitude:  r.float64(),
+			Longitude: r.float64(),
+			Altitude:  r.float64(),
+		}
+	case simconnect.DATATYPE_XYZ:
+		return simconnect.DataXYZ{
+			X: r.float64(),
+			Y: r.float64(),
+			Z: r.float64(),
+		}
+	default:
+		if size := datumSize(dataType); size > 0 {
+			return r.string(size)
+		}
+	}
+	r.err = true
+	return nil
+}
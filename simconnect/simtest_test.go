@@ -0,0 +1,83 @@
+package simconnect_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/supersidor/msfs2020-go/simconnect"
+	"github.com/supersidor/msfs2020-go/simconnect/simtest"
+)
+
+// the tests of this package run against a simtest.Server.
+
+const timeout = 2 * time.Second
+
+// start returns a server, a client connected to it and a running dispatcher,
+// all closed when the test ends.
+func start(t *testing.T) (*simtest.Server, *simconnect.NetSimConnect, *simconnect.Dispatcher) {
+	srv := simtest.NewServer()
+	t.Cleanup(srv.Close)
+
+	s, err := simconnect.Dial("simconnect", srv.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	d := simconnect.NewDispatcher(s)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	d.Start(ctx)
+
+	return srv, s, d
+}
+
+// withTimeout returns a context cancelled when the test ends or the timeout
+// passed.
+func withTimeout(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+// eventually fails the test if cond doesn't hold within the timeout. the
+// server handles packets on its own goroutine, writes show up later.
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	srv := simtest.NewServer()
+	defer srv.Close()
+	srv.ApplicationName = "Test Simulator"
+
+	s, err := simconnect.Dial("simconnect", srv.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	buf, err := s.NextDispatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := s.Decode(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	open, ok := msg.(*simconnect.RecvOpen)
+	if !ok {
+		t.Fatalf("first message is %T, want *RecvOpen", msg)
+	}
+	if open.ApplicationName != "Test Simulator" || open.SimConnectVersionMajor != 11 {
+		t.Errorf("opened %q SimConnect %d", open.ApplicationName, open.SimConnectVersionMajor)
+	}
+}
@@ -0,0 +1,117 @@
+package simconnect
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Overflow decides what a subscription does with a value arriving while its
+// channel is full.
+type Overflow int
+
+const (
+	OverflowDrop   Overflow = iota // drop the new value, the consumer gets the older ones first
+	OverflowLatest                 // drop the oldest buffered value, the consumer gets the newest ones
+)
+
+// SubscribeOptions are the optional parameters of Subscribe.
+type SubscribeOptions struct {
+	Flags    DataRequestFlag // DATA_REQUEST_FLAG_*
+	Origin   DWORD           // periods to skip before the first value
+	Interval DWORD           // periods to skip between two values
+	Limit    DWORD           // values to send before the channel is closed, 0 is unlimited
+
+	Overflow Overflow
+}
+
+// Subscribe requests the simulation variables of objectID every period
+// (PERIOD_*) and sends copies of them to ch, a buffered chan T or chan<- T of
+// a data definition struct T (a chan T for OverflowLatest):
+//
+//	reports := make(chan Report, 1)
+//	err := d.Subscribe(ctx, reports, simconnect.OBJECT_ID_USER, simconnect.PERIOD_SECOND, nil)
+//
+// with DATA_REQUEST_FLAG_TAGGED every value holds the latest state of all
+// fields, not only the received ones. every call is a request of its own.
+// once ctx is done or Limit values were sent the request is stopped with
+// PERIOD_NEVER and ch closed, so ch must not be shared with another sender.
+// a nil opts uses the defaults, the dispatcher has to be running.
+func (d *Dispatcher) Subscribe(ctx context.Context, ch interface{}, objectID DWORD, period Period, opts *SubscribeOptions) error {
+	out := reflect.ValueOf(ch)
+	t := reflect.TypeOf(ch)
+	if t == nil || t.Kind() != reflect.Chan || t.ChanDir()&reflect.SendDir == 0 || t.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%T is not a send channel of a struct", ch)
+	}
+	if out.IsNil() || out.Cap() < 1 {
+		return fmt.Errorf("%T is nil or not buffered", ch)
+	}
+	if opts == nil {
+		opts = &SubscribeOptions{}
+	}
+	if opts.Overflow == OverflowLatest && t.ChanDir() != reflect.BothDir {
+		return fmt.Errorf("OverflowLatest needs a chan %s to drop values from, not %T", t.Elem(), ch)
+	}
+
+	c := d.client
+	state := reflect.New(t.Elem())
+	def := state.Interface()
+	if err := c.RegisterDataDefinition(def); err != nil {
+		return err
+	}
+	defineID := c.GetDefineID(def)
+
+	var mu sync.Mutex
+	closed := false
+	done := make(chan struct{})
+	var sent DWORD
+
+	requestID := c.GetRequestID()
+	d.HandleRequest(requestID, func(msg interface{}) {
+		m, ok := msg.(*SimobjectData)
+		if !ok || m.Update(def) != nil {
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if closed {
+			return
+		}
+
+		v := state.Elem()
+		if !out.TrySend(v) && opts.Overflow == OverflowLatest {
+			out.TryRecv()
+			out.TrySend(v)
+		}
+
+		sent++
+		if opts.Limit > 0 && sent == opts.Limit {
+			closed = true
+			close(done)
+		}
+	})
+
+	err := c.RequestDataOnSimObject(requestID, defineID, objectID, period, opts.Flags, opts.Origin, opts.Interval, opts.Limit)
+	if err != nil {
+		d.HandleRequest(requestID, nil)
+		return err
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		c.RequestDataOnSimObject(requestID, defineID, objectID, PERIOD_NEVER, 0, 0, 0, 0)
+		d.HandleRequest(requestID, nil)
+
+		mu.Lock()
+		defer mu.Unlock()
+		closed = true
+		out.Close()
+	}()
+
+	return nil
+}
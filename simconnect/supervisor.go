@@ -0,0 +1,850 @@
+package simconnect
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/supersidor/msfs2020-go/logging"
+)
+
+// State is the connection state of a Supervisor.
+type State int
+
+const (
+	StateWaiting   State = iota // not connected, waiting for the simulator
+	StateConnected              // connected and every registration replayed
+	StateClosed                 // Close was called
+)
+
+func (s State) String() string {
+	switch s {
+	case StateWaiting:
+		return "waiting for simulator"
+	case StateConnected:
+		return "connected"
+	case StateClosed:
+		return "closed"
+	}
+	return fmt.Sprintf("State(%d)", int(s))
+}
+
+// ErrNotConnected is returned by Supervisor calls that need a connection while
+// it is waiting for the simulator.
+var ErrNotConnected = errors.New("not connected to the simulator")
+
+var errQuit = errors.New("simulator quit")
+
+// Supervisor is a Client that keeps a connection to the simulator open. it
+// retries with backoff until the simulator is available and reconnects after
+// RECV_ID_QUIT or a failing NextDispatch.
+//
+// data definitions, system event and facility subscriptions, event mappings,
+// notification groups, menu items and periodic data requests are recorded and
+// replayed on every new connection, so IDs handed out by the supervisor stay
+// valid across reconnects. registrations made while waiting are sent once the
+// connection is up, every other call fails with ErrNotConnected.
+//
+// messages are only watched when read through NextDispatch or
+// GetNextDispatch, e.g. by a Dispatcher. it keeps running through reconnects
+// and stops once the supervisor is closed. a connection returning
+// ErrReplayEnded closes the supervisor, the error is passed on.
+type Supervisor struct {
+	registry
+
+	// Open opens a new connection, NewSupervisor sets it to Open(name, address).
+	Open func() (Client, error)
+
+	// MinBackoff is the delay after the first failed connection attempt, it is
+	// doubled after every further failure up to MaxBackoff. defaults to 1s and
+	// 30s.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// StateChange is called from the supervisor goroutine whenever the state
+	// changes and after every failed connection attempt. err is the reason the
+	// connection was lost or could not be opened.
+	StateChange func(state State, err error)
+
+	// Log gets the connection state changes, failed connection attempts at
+	// LevelDebug. nil logs nothing.
+	Log *logging.Logger
+
+	mu      sync.Mutex
+	client  Client
+	state   State
+	lost    chan error
+	quit    bool // RECV_ID_QUIT was returned, drop the connection on the next read
+	entries []replayEntry
+	limits  map[string]DWORD   // messages left of recorded requests with a limit, by key
+	objects map[DWORD][]string // objects opened by the facility definitions, by define ID
+
+	stop chan struct{}
+	once sync.Once
+	done chan struct{}
+}
+
+// replayEntry is a call recorded for replaying on a new connection. entries
+// with the same key replace each other.
+type replayEntry struct {
+	key  string
+	call func(c Client) error
+}
+
+var _ Client = (*Supervisor)(nil)
+
+// NewSupervisor returns a supervisor connecting with Open(name, address), see
+// Open. it does not connect before Start is called.
+func NewSupervisor(name, address string) *Supervisor {
+	return &Supervisor{
+		registry: newRegistry(),
+		Open: func() (Client, error) {
+			return Open(name, address)
+		},
+		limits:  map[string]DWORD{},
+		objects: map[DWORD][]string{},
+		stop:    make(chan struct{}),
+	}
+}
+
+// Start runs the supervisor in its own goroutine until ctx is cancelled or
+// Close is called.
+func (s *Supervisor) Start(ctx context.Context) {
+	s.done = make(chan struct{})
+	go func() {
+		defer close(s.done)
+		s.run(ctx)
+	}()
+}
+
+func (s *Supervisor) run(ctx context.Context) {
+	defer s.disconnect(StateClosed, nil)
+
+	minBackoff := s.MinBackoff
+	if minBackoff == 0 {
+		minBackoff = time.Second
+	}
+	maxBackoff := s.MaxBackoff
+	if maxBackoff == 0 {
+		maxBackoff = 30 * time.Second
+	}
+
+	backoff := minBackoff
+	for {
+		lost, err := s.connect()
+		if err != nil {
+			s.Log.Debug("connecting failed", logging.F("error", err), logging.F("retry_in", backoff))
+			s.changed(StateWaiting, err)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.stop:
+				return
+			case <-time.After(backoff):
+			}
+
+			backoff *= 2
+			if backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+			continue
+		}
+		backoff = minBackoff
+		s.Log.Info("connected")
+		s.changed(StateConnected, nil)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stop:
+			return
+		case err := <-lost:
+			s.Log.Warn("connection lost", logging.F("error", err))
+			s.changed(StateWaiting, err)
+		}
+	}
+}
+
+// connect opens a connection and replays every recorded call on it. the
+// returned channel receives the reason the connection was lost.
+func (s *Supervisor) connect() (chan error, error) {
+	c, err := s.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.entries {
+		if err := e.call(c); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+
+	s.client = c
+	s.state = StateConnected
+	s.lost = make(chan error, 1)
+	return s.lost, nil
+}
+
+// fail drops the current connection, s.mu has to be held.
+func (s *Supervisor) fail(err error) {
+	if s.client == nil {
+		return
+	}
+	s.client.Close()
+	s.client = nil
+	s.quit = false
+	s.state = StateWaiting
+	s.lost <- err
+}
+
+// end stops the supervisor like Close, without waiting for it.
+func (s *Supervisor) end() {
+	s.once.Do(func() {
+		close(s.stop)
+	})
+}
+
+func (s *Supervisor) disconnect(state State, err error) {
+	s.mu.Lock()
+	if s.client != nil {
+		s.client.Close()
+		s.client = nil
+	}
+	s.quit = false
+	s.state = state
+	s.mu.Unlock()
+
+	s.changed(state, err)
+}
+
+func (s *Supervisor) changed(state State, err error) {
+	if s.StateChange != nil {
+		s.StateChange(state, err)
+	}
+}
+
+// State returns the current connection state.
+func (s *Supervisor) State() State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+// Close stops the supervisor and closes the current connection.
+func (s *Supervisor) Close() error {
+	s.once.Do(func() {
+		close(s.stop)
+	})
+	if s.done != nil {
+		<-s.done
+	} else {
+		s.disconnect(StateClosed, nil)
+	}
+	return nil
+}
+
+// record remembers call for replaying and sends it right away when connected.
+// while waiting it only records and returns nil. a call with the key of a
+// recorded one replaces it and is replayed last.
+func (s *Supervisor) record(key string, call func(c Client) error) error {
+	return s.recordLimited(key, 0, func(c Client, limit DWORD) error {
+		return call(c)
+	})
+}
+
+// recordLimited is record for requests sending limit messages at most, 0 is
+// unlimited. a new connection only asks for the messages that did not arrive
+// yet and the request is forgotten once all of them did, see count.
+func (s *Supervisor) recordLimited(key string, limit DWORD, call func(c Client, limit DWORD) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.forget(key)
+	if limit > 0 {
+		s.limits[key] = limit
+	}
+	s.entries = append(s.entries, replayEntry{key: key, call: func(c Client) error {
+		return call(c, s.limits[key])
+	}})
+
+	if s.client == nil {
+		return nil
+	}
+	return call(s.client, limit)
+}
+
+// recordDatum is record for the datums of a definition, a datum with the key
+// of a recorded one replaces it in place so the datums keep their order.
+func (s *Supervisor) recordDatum(key string, call func(c Client) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	replaced := false
+	for i := range s.entries {
+		if s.entries[i].key == key {
+			s.entries[i].call = call
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		s.entries = append(s.entries, replayEntry{key: key, call: call})
+	}
+
+	if s.client == nil {
+		return nil
+	}
+	return call(s.client)
+}
+
+// count counts a message answering the request recorded under key, s.mu has
+// to be held.
+func (s *Supervisor) count(key string) {
+	left, ok := s.limits[key]
+	if !ok {
+		return
+	}
+	if left <= 1 {
+		s.forget(key)
+		return
+	}
+	s.limits[key] = left - 1
+}
+
+// forget drops the recorded call for key, s.mu has to be held.
+func (s *Supervisor) forget(key string) {
+	if key == "" {
+		return
+	}
+	delete(s.limits, key)
+	for i, e := range s.entries {
+		if e.key == key {
+			s.entries = append(s.entries[:i], s.entries[i+1:]...)
+			return
+		}
+	}
+}
+
+// forgetPrefix drops every recorded call with a key starting with prefix,
+// s.mu has to be held.
+func (s *Supervisor) forgetPrefix(prefix string) {
+	entries := s.entries[:0]
+	for _, e := range s.entries {
+		if !strings.HasPrefix(e.key, prefix) {
+			entries = append(entries, e)
+		}
+	}
+	s.entries = entries
+}
+
+// do sends call if connected, without recording it.
+func (s *Supervisor) do(call func(c Client) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.client == nil {
+		return ErrNotConnected
+	}
+	return call(s.client)
+}
+
+// SentCall returns the call of the current connection that sent the packet
+// sendID, send IDs start over on every new connection.
+func (s *Supervisor) SentCall(sendID DWORD) (SentCall, bool) {
+	var call SentCall
+	var ok bool
+	s.do(func(c Client) error {
+		call, ok = c.SentCall(sendID)
+		return nil
+	})
+	return call, ok
+}
+
+func (s *Supervisor) RegisterDataDefinition(a interface{}) error {
+	return s.registerDataDefinition(s, a)
+}
+
+func (s *Supervisor) SetData(objectID DWORD, v interface{}) error {
+	return s.setData(s, objectID, v)
+}
+
+func (s *Supervisor) SendEvent(event KeyEvent, data ...DWORD) error {
+	return s.sendEvent(s, event, data...)
+}
+
+func (s *Supervisor) RegisterClientDataDefinition(a interface{}) error {
+	return s.registerClientDataDefinition(s, a)
+}
+
+func (s *Supervisor) WriteClientData(clientDataID DWORD, v interface{}) error {
+	return s.writeClientData(s, clientDataID, v)
+}
+
+func (s *Supervisor) RegisterFacilityDefinition(a interface{}) error {
+	return s.registerFacilityDefinition(s, a)
+}
+
+func (s *Supervisor) AddToDataDefinition(defineID DWORD, name, unit string, dataType DWORD, epsilon float32, datumID DWORD) error {
+	return s.recordDatum(fmt.Sprintf("definition %d %s %s", defineID, name, unit), func(c Client) error {
+		return c.AddToDataDefinition(defineID, name, unit, dataType, epsilon, datumID)
+	})
+}
+
+// ClearDataDefinition forgets the AddToDataDefinition calls of defineID, a
+// new connection starts without them.
+func (s *Supervisor) ClearDataDefinition(defineID DWORD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.forgetPrefix(fmt.Sprintf("definition %d ", defineID))
+	if s.client == nil {
+		return nil
+	}
+	return s.client.ClearDataDefinition(defineID)
+}
+
+func (s *Supervisor) SubscribeToSystemEvent(eventID DWORD, eventName string) error {
+	return s.record(fmt.Sprintf("system event %d", eventID), func(c Client) error {
+		return c.SubscribeToSystemEvent(eventID, eventName)
+	})
+}
+
+// UnsubscribeFromSystemEvent forgets the subscription and its state.
+func (s *Supervisor) UnsubscribeFromSystemEvent(eventID DWORD) error {
+	s.mu.Lock()
+	s.forget(fmt.Sprintf("system event %d", eventID))
+	s.forget(fmt.Sprintf("system event %d state", eventID))
+	s.mu.Unlock()
+
+	return s.do(func(c Client) error {
+		return c.UnsubscribeFromSystemEvent(eventID)
+	})
+}
+
+func (s *Supervisor) SetSystemEventState(eventID, state DWORD) error {
+	return s.record(fmt.Sprintf("system event %d state", eventID), func(c Client) error {
+		return c.SetSystemEventState(eventID, state)
+	})
+}
+
+func (s *Supervisor) RequestDataOnSimObjectType(requestID, defineID, radius, simobjectType DWORD) error {
+	return s.do(func(c Client) error {
+		return c.RequestDataOnSimObjectType(requestID, defineID, radius, simobjectType)
+	})
+}
+
+// RequestDataOnSimObject records periodic requests, a request with the same
+// requestID and PERIOD_NEVER stops replaying it. a request with a limit is
+// replayed with the messages left.
+func (s *Supervisor) RequestDataOnSimObject(requestID, defineID, objectID DWORD, period Period, flags DataRequestFlag, origin, interval, limit DWORD) error {
+	call := func(c Client, limit DWORD) error {
+		return c.RequestDataOnSimObject(requestID, defineID, objectID, period, flags, origin, interval, limit)
+	}
+
+	key := fmt.Sprintf("request %d", requestID)
+	switch period {
+	case PERIOD_NEVER, PERIOD_ONCE:
+		s.mu.Lock()
+		s.forget(key)
+		s.mu.Unlock()
+		return s.do(func(c Client) error {
+			return call(c, limit)
+		})
+	}
+	return s.recordLimited(key, limit, call)
+}
+
+func (s *Supervisor) SetDataOnSimObject(defineID, simobjectType, flags, arrayCount, size DWORD, buf unsafe.Pointer) error {
+	return s.do(func(c Client) error {
+		return c.SetDataOnSimObject(defineID, simobjectType, flags, arrayCount, size, buf)
+	})
+}
+
+func (s *Supervisor) SubscribeToFacilities(facilityType, requestID DWORD) error {
+	return s.record(fmt.Sprintf("facilities %d", facilityType), func(c Client) error {
+		return c.SubscribeToFacilities(facilityType, requestID)
+	})
+}
+
+func (s *Supervisor) UnsubscribeToFacilities(facilityType DWORD) error {
+	s.mu.Lock()
+	s.forget(fmt.Sprintf("facilities %d", facilityType))
+	s.mu.Unlock()
+
+	return s.do(func(c Client) error {
+		return c.UnsubscribeToFacilities(facilityType)
+	})
+}
+
+func (s *Supervisor) RequestFacilitiesList(facilityType, requestID DWORD) error {
+	return s.do(func(c Client) error {
+		return c.RequestFacilitiesList(facilityType, requestID)
+	})
+}
+
+func (s *Supervisor) SubscribeToFacilitiesEX1(facilityType, newElemInRangeRequestID, oldElemOutRangeRequestID DWORD) error {
+	s.mu.Lock()
+	s.forget(fmt.Sprintf("facilities ex1 %d unsubscribe", facilityType))
+	s.mu.Unlock()
+
+	return s.record(fmt.Sprintf("facilities ex1 %d", facilityType), func(c Client) error {
+		return c.SubscribeToFacilitiesEX1(facilityType, newElemInRangeRequestID, oldElemOutRangeRequestID)
+	})
+}
+
+// UnsubscribeToFacilitiesEX1 forgets the subscription if both halves are
+// unsubscribed, otherwise the unsubscribe is replayed after it.
+func (s *Supervisor) UnsubscribeToFacilitiesEX1(facilityType DWORD, unsubscribeNewInRange, unsubscribeOldOutRange bool) error {
+	call := func(c Client) error {
+		return c.UnsubscribeToFacilitiesEX1(facilityType, unsubscribeNewInRange, unsubscribeOldOutRange)
+	}
+
+	if !unsubscribeNewInRange || !unsubscribeOldOutRange {
+		return s.record(fmt.Sprintf("facilities ex1 %d unsubscribe", facilityType), call)
+	}
+
+	s.mu.Lock()
+	s.forget(fmt.Sprintf("facilities ex1 %d", facilityType))
+	s.forget(fmt.Sprintf("facilities ex1 %d unsubscribe", facilityType))
+	s.mu.Unlock()
+
+	return s.do(call)
+}
+
+func (s *Supervisor) RequestFacilitiesListEX1(facilityType, requestID DWORD) error {
+	return s.do(func(c Client) error {
+		return c.RequestFacilitiesListEX1(facilityType, requestID)
+	})
+}
+
+func (s *Supervisor) MapClientEventToSimEvent(eventID DWORD, eventName string) error {
+	return s.record(fmt.Sprintf("event %d", eventID), func(c Client) error {
+		return c.MapClientEventToSimEvent(eventID, eventName)
+	})
+}
+
+func (s *Supervisor) TransmitClientEvent(objectID, eventID, data, groupID, flags DWORD) error {
+	return s.do(func(c Client) error {
+		return c.TransmitClientEvent(objectID, eventID, data, groupID, flags)
+	})
+}
+
+func (s *Supervisor) MenuAddItem(menuItem string, menuEventID, Data DWORD) error {
+	return s.record(fmt.Sprintf("menu %d", menuEventID), func(c Client) error {
+		return c.MenuAddItem(menuItem, menuEventID, Data)
+	})
+}
+
+func (s *Supervisor) MenuDeleteItem(menuItem string, menuEventID, Data DWORD) error {
+	s.mu.Lock()
+	s.forget(fmt.Sprintf("menu %d", menuEventID))
+	s.mu.Unlock()
+
+	return s.do(func(c Client) error {
+		return c.MenuDeleteItem(menuItem, menuEventID, Data)
+	})
+}
+
+func (s *Supervisor) AddClientEventToNotificationGroup(groupID, eventID DWORD) error {
+	return s.record(fmt.Sprintf("group %d event %d", groupID, eventID), func(c Client) error {
+		return c.AddClientEventToNotificationGroup(groupID, eventID)
+	})
+}
+
+func (s *Supervisor) SetNotificationGroupPriority(groupID, priority DWORD) error {
+	return s.record(fmt.Sprintf("group %d priority", groupID), func(c Client) error {
+		return c.SetNotificationGroupPriority(groupID, priority)
+	})
+}
+
+func (s *Supervisor) MapInputEventToClientEvent(groupID DWORD, inputDefinition string, downEventID, downValue, upEventID, upValue DWORD) error {
+	return s.record(fmt.Sprintf("input %d %s", groupID, inputDefinition), func(c Client) error {
+		return c.MapInputEventToClientEvent(groupID, inputDefinition, downEventID, downValue, upEventID, upValue)
+	})
+}
+
+func (s *Supervisor) RemoveInputEvent(groupID DWORD, inputDefinition string) error {
+	s.mu.Lock()
+	s.forget(fmt.Sprintf("input %d %s", groupID, inputDefinition))
+	s.mu.Unlock()
+
+	return s.do(func(c Client) error {
+		return c.RemoveInputEvent(groupID, inputDefinition)
+	})
+}
+
+func (s *Supervisor) ClearInputGroup(groupID DWORD) error {
+	s.mu.Lock()
+	s.forgetPrefix(fmt.Sprintf("input %d ", groupID))
+	s.mu.Unlock()
+
+	return s.do(func(c Client) error {
+		return c.ClearInputGroup(groupID)
+	})
+}
+
+func (s *Supervisor) SetInputGroupPriority(groupID, priority DWORD) error {
+	return s.record(fmt.Sprintf("input group %d priority", groupID), func(c Client) error {
+		return c.SetInputGroupPriority(groupID, priority)
+	})
+}
+
+func (s *Supervisor) SetInputGroupState(groupID, state DWORD) error {
+	return s.record(fmt.Sprintf("input group %d state", groupID), func(c Client) error {
+		return c.SetInputGroupState(groupID, state)
+	})
+}
+
+func (s *Supervisor) MapClientDataNameToID(clientDataName string, clientDataID DWORD) error {
+	return s.record(fmt.Sprintf("client data %d name", clientDataID), func(c Client) error {
+		return c.MapClientDataNameToID(clientDataName, clientDataID)
+	})
+}
+
+func (s *Supervisor) CreateClientData(clientDataID, size, flags DWORD) error {
+	return s.record(fmt.Sprintf("client data %d create", clientDataID), func(c Client) error {
+		return c.CreateClientData(clientDataID, size, flags)
+	})
+}
+
+func (s *Supervisor) AddToClientDataDefinition(defineID, offset, sizeOrType DWORD, epsilon float32, datumID DWORD) error {
+	return s.recordDatum(fmt.Sprintf("client data definition %d %d", defineID, offset), func(c Client) error {
+		return c.AddToClientDataDefinition(defineID, offset, sizeOrType, epsilon, datumID)
+	})
+}
+
+// ClearClientDataDefinition forgets the AddToClientDataDefinition calls of
+// defineID, a new connection starts without them.
+func (s *Supervisor) ClearClientDataDefinition(defineID DWORD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.forgetPrefix(fmt.Sprintf("client data definition %d ", defineID))
+	if s.client == nil {
+		return nil
+	}
+	return s.client.ClearClientDataDefinition(defineID)
+}
+
+func (s *Supervisor) RequestClientData(clientDataID, requestID, defineID DWORD, period ClientDataPeriod, flags ClientDataRequestFlag, origin, interval, limit DWORD) error {
+	call := func(c Client, limit DWORD) error {
+		return c.RequestClientData(clientDataID, requestID, defineID, period, flags, origin, interval, limit)
+	}
+
+	key := fmt.Sprintf("client data request %d", requestID)
+	switch period {
+	case CLIENT_DATA_PERIOD_NEVER, CLIENT_DATA_PERIOD_ONCE:
+		s.mu.Lock()
+		s.forget(key)
+		s.mu.Unlock()
+		return s.do(func(c Client) error {
+			return call(c, limit)
+		})
+	}
+	return s.recordLimited(key, limit, call)
+}
+
+func (s *Supervisor) SetClientData(clientDataID, defineID, flags, size DWORD, buf unsafe.Pointer) error {
+	return s.do(func(c Client) error {
+		return c.SetClientData(clientDataID, defineID, flags, size, buf)
+	})
+}
+
+// AddToFacilityDefinition records fields by the objects they are in, a field
+// added again to the same object replaces the recorded one.
+func (s *Supervisor) AddToFacilityDefinition(defineID DWORD, fieldName string) error {
+	s.mu.Lock()
+	objects := s.objects[defineID]
+	key := fmt.Sprintf("facility definition %d %s %s", defineID, strings.Join(objects, "/"), fieldName)
+	switch {
+	case strings.HasPrefix(fieldName, "OPEN "):
+		s.objects[defineID] = append(objects, strings.TrimPrefix(fieldName, "OPEN "))
+	case strings.HasPrefix(fieldName, "CLOSE ") && len(objects) > 0:
+		s.objects[defineID] = objects[:len(objects)-1]
+	}
+	s.mu.Unlock()
+
+	return s.recordDatum(key, func(c Client) error {
+		return c.AddToFacilityDefinition(defineID, fieldName)
+	})
+}
+
+func (s *Supervisor) RequestFacilityData(defineID, requestID DWORD, icao, region string) error {
+	return s.do(func(c Client) error {
+		return c.RequestFacilityData(defineID, requestID, icao, region)
+	})
+}
+
+// AICreateParkedATCAircraft and the other AI calls are not replayed, AI
+// objects go away with the simulator and get new object IDs when created
+// again.
+func (s *Supervisor) AICreateParkedATCAircraft(containerTitle, tailNumber, airportID string, requestID DWORD) error {
+	return s.do(func(c Client) error {
+		return c.AICreateParkedATCAircraft(containerTitle, tailNumber, airportID, requestID)
+	})
+}
+
+func (s *Supervisor) AICreateEnrouteATCAircraft(containerTitle, tailNumber string, flightNumber int32, flightPlanPath string, flightPlanPosition float64, touchAndGo bool, requestID DWORD) error {
+	return s.do(func(c Client) error {
+		return c.AICreateEnrouteATCAircraft(containerTitle, tailNumber, flightNumber, flightPlanPath, flightPlanPosition, touchAndGo, requestID)
+	})
+}
+
+func (s *Supervisor) AICreateNonATCAircraft(containerTitle, tailNumber string, initPos DataInitPosition, requestID DWORD) error {
+	return s.do(func(c Client) error {
+		return c.AICreateNonATCAircraft(containerTitle, tailNumber, initPos, requestID)
+	})
+}
+
+func (s *Supervisor) AICreateSimulatedObject(containerTitle string, initPos DataInitPosition, requestID DWORD) error {
+	return s.do(func(c Client) error {
+		return c.AICreateSimulatedObject(containerTitle, initPos, requestID)
+	})
+}
+
+func (s *Supervisor) AIReleaseControl(objectID, requestID DWORD) error {
+	return s.do(func(c Client) error {
+		return c.AIReleaseControl(objectID, requestID)
+	})
+}
+
+func (s *Supervisor) AIRemoveObject(objectID, requestID DWORD) error {
+	return s.do(func(c Client) error {
+		return c.AIRemoveObject(objectID, requestID)
+	})
+}
+
+func (s *Supervisor) AISetAircraftFlightPlan(objectID DWORD, flightPlanPath string, requestID DWORD) error {
+	return s.do(func(c Client) error {
+		return c.AISetAircraftFlightPlan(objectID, flightPlanPath, requestID)
+	})
+}
+
+func (s *Supervisor) RequestSystemState(requestID DWORD, state string) error {
+	return s.do(func(c Client) error {
+		return c.RequestSystemState(requestID, state)
+	})
+}
+
+func (s *Supervisor) FlightLoad(fileName string) error {
+	return s.do(func(c Client) error {
+		return c.FlightLoad(fileName)
+	})
+}
+
+func (s *Supervisor) FlightSave(fileName, title, description string, flags DWORD) error {
+	return s.do(func(c Client) error {
+		return c.FlightSave(fileName, title, description, flags)
+	})
+}
+
+func (s *Supervisor) FlightPlanLoad(fileName string) error {
+	return s.do(func(c Client) error {
+		return c.FlightPlanLoad(fileName)
+	})
+}
+
+func (s *Supervisor) ShowText(textType DWORD, duration float64, eventID DWORD, text string) error {
+	return s.do(func(c Client) error {
+		return c.ShowText(textType, duration, eventID, text)
+	})
+}
+
+// GetNextDispatch returns E_FAIL while waiting for the simulator, same as
+// when no message is pending.
+func (s *Supervisor) GetNextDispatch() (unsafe.Pointer, int32, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fail := E_FAIL
+	if err := s.checkDispatch(); err != nil {
+		return nil, -1, err
+	}
+	if s.client == nil {
+		return nil, int32(fail), nil
+	}
+
+	ppData, r1, err := s.client.GetNextDispatch()
+	if err == ErrReplayEnded {
+		s.end()
+		return nil, -1, err
+	}
+	if err != nil {
+		s.fail(err)
+		return nil, int32(fail), nil
+	}
+	if ppData != nil {
+		size := (*Recv)(ppData).Size
+		s.watch((*[1 << 30]byte)(ppData)[:size:size])
+	}
+	return ppData, r1, nil
+}
+
+// NextDispatch returns nil while waiting for the simulator, same as when no
+// message is pending. RECV_ID_QUIT is still returned before reconnecting.
+func (s *Supervisor) NextDispatch() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkDispatch(); err != nil {
+		return nil, err
+	}
+	if s.client == nil {
+		return nil, nil
+	}
+
+	buf, err := s.client.NextDispatch()
+	if err == ErrReplayEnded {
+		s.end()
+		return nil, err
+	}
+	if err != nil {
+		s.fail(err)
+		return nil, nil
+	}
+	s.watch(buf)
+	return buf, nil
+}
+
+// watch looks at a message handed out by NextDispatch or GetNextDispatch for
+// RECV_ID_QUIT and the data of requests with a limit, s.mu has to be held.
+func (s *Supervisor) watch(buf []byte) {
+	if len(buf) < 12 {
+		return
+	}
+	switch DWORD(binary.LittleEndian.Uint32(buf[8:])) {
+	case RECV_ID_QUIT:
+		s.quit = true
+	case RECV_ID_SIMOBJECT_DATA:
+		if len(s.limits) > 0 && len(buf) >= 16 {
+			s.count(fmt.Sprintf("request %d", binary.LittleEndian.Uint32(buf[12:])))
+		}
+	case RECV_ID_CLIENT_DATA:
+		if len(s.limits) > 0 && len(buf) >= 16 {
+			s.count(fmt.Sprintf("client data request %d", binary.LittleEndian.Uint32(buf[12:])))
+		}
+	}
+}
+
+// checkDispatch drops the connection once the RECV_ID_QUIT message was
+// handed out, s.mu has to be held. the message may still point into the
+// connection's memory until the next read.
+func (s *Supervisor) checkDispatch() error {
+	if s.state == StateClosed {
+		return fmt.Errorf("SimConnect_GetNextDispatch error: supervisor closed")
+	}
+	if s.quit {
+		s.fail(errQuit)
+	}
+	return nil
+}
@@ -0,0 +1,336 @@
+package simconnect_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/supersidor/msfs2020-go/simconnect"
+	"github.com/supersidor/msfs2020-go/simconnect/simtest"
+)
+
+// simulator is the server a supervisor connects to, set replaces it like a
+// simulator restart.
+type simulator struct {
+	mu  sync.Mutex
+	srv *simtest.Server
+}
+
+func (s *simulator) set(srv *simtest.Server) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.srv = srv
+}
+
+func (s *simulator) open() (simconnect.Client, error) {
+	s.mu.Lock()
+	srv := s.srv
+	s.mu.Unlock()
+	return simconnect.Dial("supervisor", srv.Addr())
+}
+
+type stateChange struct {
+	state simconnect.State
+	err   error
+	at    time.Time
+}
+
+// stateLog keeps the StateChange calls of a supervisor.
+type stateLog struct {
+	mu      sync.Mutex
+	changes []stateChange
+}
+
+func (l *stateLog) add(state simconnect.State, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.changes = append(l.changes, stateChange{state, err, time.Now()})
+}
+
+func (l *stateLog) get() []stateChange {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]stateChange{}, l.changes...)
+}
+
+// connects returns how often the supervisor connected.
+func (l *stateLog) connects() int {
+	n := 0
+	for _, c := range l.get() {
+		if c.state == simconnect.StateConnected {
+			n++
+		}
+	}
+	return n
+}
+
+// supervise starts a supervisor on sim and a dispatcher reading from it, both
+// stopped when the test ends. registrations made before calling start are
+// replayed on the first connection.
+func supervise(t *testing.T, sim *simulator) (*simconnect.Supervisor, *stateLog, func() *simconnect.Dispatcher) {
+	sup := simconnect.NewSupervisor("supervisor", "")
+	sup.Open = sim.open
+	sup.MinBackoff = 20 * time.Millisecond
+	sup.MaxBackoff = 80 * time.Millisecond
+
+	states := &stateLog{}
+	sup.StateChange = states.add
+
+	start := func() *simconnect.Dispatcher {
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(func() {
+			cancel()
+			sup.Close()
+		})
+		sup.Start(ctx)
+
+		d := simconnect.NewDispatcher(sup)
+		d.Start(ctx)
+		return d
+	}
+	return sup, states, start
+}
+
+type altitude struct {
+	simconnect.RecvSimobjectDataByType
+	Altitude float64 `name:"PLANE ALTITUDE" unit:"feet"`
+}
+
+func TestSupervisorReconnect(t *testing.T) {
+	sim := &simulator{srv: simtest.NewServer()}
+	defer func() { sim.srv.Close() }()
+
+	sup, states, start := supervise(t, sim)
+	d := start()
+	ctx := withTimeout(t)
+
+	// recorded while waiting for the first connection
+	reports := make(chan altitude, 8)
+	opts := &simconnect.SubscribeOptions{Flags: simconnect.DATA_REQUEST_FLAG_CHANGED}
+	if err := d.Subscribe(ctx, reports, simconnect.OBJECT_ID_USER, simconnect.PERIOD_SECOND, opts); err != nil {
+		t.Fatal(err)
+	}
+	paused := make(chan simconnect.DWORD, 8)
+	_, err := d.HandleSystemEvent(simconnect.SYSTEM_EVENT_PAUSE, func(msg interface{}) {
+		if e, ok := msg.(*simconnect.RecvEvent); ok {
+			select {
+			case paused <- e.Data:
+			default:
+			}
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// check makes sure the subscription, the system event and the event
+	// mapping work on srv
+	check := func(srv *simtest.Server, alt float64) {
+		t.Helper()
+		eventually(t, "connected", func() bool { return sup.State() == simconnect.StateConnected })
+
+		srv.SetSimVar(simtest.UserObjectID, "PLANE ALTITUDE", alt)
+		got := false
+		for !got {
+			srv.Tick()
+			select {
+			case r := <-reports:
+				if r.Altitude != alt {
+					t.Fatalf("altitude %v, want %v", r.Altitude, alt)
+				}
+				got = true
+			case <-time.After(10 * time.Millisecond):
+			case <-ctx.Done():
+				t.Fatal("no report")
+			}
+		}
+
+		srv.FireSystemEvent(simconnect.SYSTEM_EVENT_PAUSE, 1)
+		select {
+		case <-paused:
+		case <-ctx.Done():
+			t.Fatal("no pause event")
+		}
+
+		if err := sup.SendEvent(simconnect.KEY_PAUSE_TOGGLE()); err != nil {
+			t.Fatal(err)
+		}
+		eventually(t, "PAUSE_TOGGLE", func() bool {
+			events := srv.Events()
+			return len(events) == 1 && events[0].Name == "PAUSE_TOGGLE"
+		})
+	}
+	check(sim.srv, 1000)
+
+	// the simulator goes away, the supervisor retries with backoff until it
+	// is back
+	sim.srv.Close()
+	eventually(t, "the connection to drop", func() bool { return sup.State() == simconnect.StateWaiting })
+	if err := sup.SendEvent(simconnect.KEY_PAUSE_TOGGLE()); err != simconnect.ErrNotConnected {
+		t.Errorf("SendEvent while waiting returned %v, want ErrNotConnected", err)
+	}
+	time.Sleep(400 * time.Millisecond)
+
+	var failed []time.Time
+	for _, c := range states.get() {
+		if c.state == simconnect.StateWaiting && c.err != nil {
+			failed = append(failed, c.at)
+		}
+	}
+	if len(failed) < 4 {
+		t.Fatalf("%d failed attempts in 400ms", len(failed))
+	}
+	var longest time.Duration
+	for i := 2; i < len(failed); i++ {
+		gap := failed[i].Sub(failed[i-1])
+		if gap < sup.MinBackoff {
+			t.Errorf("retried after %v, less than MinBackoff", gap)
+		}
+		if gap > longest {
+			longest = gap
+		}
+	}
+	if longest < sup.MaxBackoff*3/4 {
+		t.Errorf("the longest backoff was %v, want it to grow to MaxBackoff", longest)
+	}
+
+	sim.set(simtest.NewServer())
+	check(sim.srv, 2000)
+	if n := states.connects(); n != 2 {
+		t.Errorf("connected %d times, want 2", n)
+	}
+}
+
+func TestSupervisorQuit(t *testing.T) {
+	sim := &simulator{srv: simtest.NewServer()}
+	defer sim.srv.Close()
+
+	sup, states, start := supervise(t, sim)
+	d := start()
+
+	quit := make(chan struct{}, 1)
+	d.Handle(simconnect.RECV_ID_QUIT, func(msg interface{}) {
+		quit <- struct{}{}
+	})
+	eventually(t, "connected", func() bool { return sup.State() == simconnect.StateConnected })
+
+	sim.srv.Quit()
+	select {
+	case <-quit:
+	case <-time.After(timeout):
+		t.Fatal("RECV_ID_QUIT not passed on")
+	}
+	eventually(t, "a new connection", func() bool {
+		return states.connects() == 2 && sim.srv.ConnectionCount() == 1
+	})
+
+	// the supervisor stops on ctx or Close, not on RECV_ID_QUIT
+	sup.Close()
+	if state := sup.State(); state != simconnect.StateClosed {
+		t.Errorf("state %v after Close", state)
+	}
+	eventually(t, "the connection to close", func() bool { return sim.srv.ConnectionCount() == 0 })
+}
+
+func TestSupervisorLimit(t *testing.T) {
+	sim := &simulator{srv: simtest.NewServer()}
+	defer sim.srv.Close()
+	srv := sim.srv
+
+	sup, states, start := supervise(t, sim)
+	d := start()
+	eventually(t, "connected", func() bool { return sup.State() == simconnect.StateConnected })
+
+	if err := sup.RegisterDataDefinition(&altitude{}); err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	received := 0
+	count := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return received
+	}
+
+	requestID := sup.GetRequestID()
+	d.HandleRequest(requestID, func(msg interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		received++
+	})
+	err := sup.RequestDataOnSimObject(requestID, sup.GetDefineID(&altitude{}), simconnect.OBJECT_ID_USER, simconnect.PERIOD_SECOND, 0, 0, 0, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tickUntil := func(what string, n int) {
+		t.Helper()
+		eventually(t, what, func() bool {
+			srv.Tick()
+			return count() >= n
+		})
+	}
+	tickUntil("2 messages", 2)
+
+	// the new connection only asks for the messages left
+	srv.Quit()
+	eventually(t, "a new connection", func() bool { return states.connects() == 2 })
+	tickUntil("5 messages", 5)
+
+	for i := 0; i < 5; i++ {
+		srv.Tick()
+	}
+	time.Sleep(100 * time.Millisecond)
+	if n := count(); n != 5 {
+		t.Errorf("received %d messages of a request limited to 5", n)
+	}
+}
+
+type runway struct {
+	Latitude float64 `facility:"LATITUDE"`
+}
+
+type airport struct {
+	simconnect.RecvFacilityData `facility:"AIRPORT"`
+	Latitude                    float64  `facility:"LATITUDE"`
+	Runways                     []runway `facility:"RUNWAY"`
+}
+
+func TestSupervisorFacilityDefinition(t *testing.T) {
+	sim := &simulator{srv: simtest.NewServer()}
+	defer sim.srv.Close()
+	sim.srv.SetFacilityData("KSEA", &simtest.FacilityObject{
+		Fields: map[string]interface{}{"LATITUDE": 47.45},
+		Children: map[string][]*simtest.FacilityObject{
+			"RUNWAY": {{Fields: map[string]interface{}{"LATITUDE": 47.46}}},
+		},
+	})
+
+	sup, _, start := supervise(t, sim)
+
+	// fields added again replace the recorded ones, the LATITUDE of the
+	// runway is not the one of the airport
+	defineID := sup.GetDefineID(&airport{})
+	for i := 0; i < 2; i++ {
+		for _, field := range []string{"OPEN AIRPORT", "LATITUDE", "OPEN RUNWAY", "LATITUDE", "CLOSE RUNWAY", "CLOSE AIRPORT"} {
+			if err := sup.AddToFacilityDefinition(defineID, field); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := sup.RegisterFacilityDefinition(&airport{}); err != nil {
+		t.Fatal(err)
+	}
+
+	d := start()
+	eventually(t, "connected", func() bool { return sup.State() == simconnect.StateConnected })
+
+	var a airport
+	if err := d.RequestFacilityData(withTimeout(t), &a, "KSEA", ""); err != nil {
+		t.Fatal(err)
+	}
+	if a.Latitude != 47.45 || len(a.Runways) != 1 || a.Runways[0].Latitude != 47.46 {
+		t.Errorf("got %+v", a)
+	}
+}
@@ -0,0 +1,73 @@
+package simconnect
+
+// SystemEvent is the name of a system event, as documented in
+// MSFS-SDK/Documentation "SimConnect_SubscribeToSystemEvent".
+// HandleSystemEvent takes a SystemEvent, deliberate conversions like
+// SystemEvent("MyEvent") still compile.
+//
+// the comment of each event names the message it arrives as and the meaning
+// of its Data, events without one arrive as *RecvEvent with no data.
+type SystemEvent string
+
+// timers
+const (
+	SYSTEM_EVENT_1SEC        SystemEvent = "1sec"
+	SYSTEM_EVENT_4SEC        SystemEvent = "4sec"
+	SYSTEM_EVENT_6HZ         SystemEvent = "6Hz"
+	SYSTEM_EVENT_FRAME       SystemEvent = "Frame"      // *RecvEventFrame
+	SYSTEM_EVENT_PAUSE_FRAME SystemEvent = "PauseFrame" // *RecvEventFrame, while paused
+)
+
+// simulation state
+const (
+	SYSTEM_EVENT_PAUSE            SystemEvent = "Pause"     // Data 1 paused, 0 unpaused
+	SYSTEM_EVENT_PAUSE_EX1        SystemEvent = "Pause_EX1" // Data PAUSE_STATE_* flags
+	SYSTEM_EVENT_PAUSED           SystemEvent = "Paused"
+	SYSTEM_EVENT_UNPAUSED         SystemEvent = "Unpaused"
+	SYSTEM_EVENT_SIM              SystemEvent = "Sim" // Data 1 running, 0 stopped
+	SYSTEM_EVENT_SIM_START        SystemEvent = "SimStart"
+	SYSTEM_EVENT_SIM_STOP         SystemEvent = "SimStop"
+	SYSTEM_EVENT_CRASHED          SystemEvent = "Crashed"
+	SYSTEM_EVENT_CRASH_RESET      SystemEvent = "CrashReset"
+	SYSTEM_EVENT_POSITION_CHANGED SystemEvent = "PositionChanged"
+	SYSTEM_EVENT_SOUND            SystemEvent = "Sound" // Data SOUND_SYSTEM_EVENT_DATA_MASTER while sound is on
+	SYSTEM_EVENT_VIEW             SystemEvent = "View"  // Data VIEW_SYSTEM_EVENT_DATA_*
+)
+
+// files
+const (
+	SYSTEM_EVENT_AIRCRAFT_LOADED         SystemEvent = "AircraftLoaded"      // *RecvEventFilename, the aircraft.cfg
+	SYSTEM_EVENT_FLIGHT_LOADED           SystemEvent = "FlightLoaded"        // *RecvEventFilename, the .FLT file
+	SYSTEM_EVENT_FLIGHT_SAVED            SystemEvent = "FlightSaved"         // *RecvEventFilename, the .FLT file
+	SYSTEM_EVENT_FLIGHT_PLAN_ACTIVATED   SystemEvent = "FlightPlanActivated" // *RecvEventFilename, the .PLN file
+	SYSTEM_EVENT_FLIGHT_PLAN_DEACTIVATED SystemEvent = "FlightPlanDeactivated"
+)
+
+// objects
+const (
+	SYSTEM_EVENT_OBJECT_ADDED   SystemEvent = "ObjectAdded"   // *RecvEventObjectAddRemove, Data the object ID
+	SYSTEM_EVENT_OBJECT_REMOVED SystemEvent = "ObjectRemoved" // *RecvEventObjectAddRemove, Data the object ID
+)
+
+// missions and weather
+const (
+	SYSTEM_EVENT_CUSTOM_MISSION_ACTION_EXECUTED SystemEvent = "CustomMissionActionExecuted"
+	SYSTEM_EVENT_WEATHER_MODE_CHANGED           SystemEvent = "WeatherModeChanged"
+)
+
+// Data of SYSTEM_EVENT_PAUSE_EX1, or-ed together.
+const (
+	PAUSE_STATE_NO_PAUSE     DWORD = 0
+	PAUSE_STATE_FULL_PAUSE   DWORD = 1
+	PAUSE_STATE_ACTIVE_PAUSE DWORD = 2
+	PAUSE_STATE_SIM_PAUSE    DWORD = 4
+)
+
+// Data of SYSTEM_EVENT_SOUND and SYSTEM_EVENT_VIEW.
+const (
+	SOUND_SYSTEM_EVENT_DATA_MASTER DWORD = 1
+
+	VIEW_SYSTEM_EVENT_DATA_COCKPIT_2D      DWORD = 0x00000001
+	VIEW_SYSTEM_EVENT_DATA_COCKPIT_VIRTUAL DWORD = 0x00000002
+	VIEW_SYSTEM_EVENT_DATA_ORTHOGONAL      DWORD = 0x00000004
+)
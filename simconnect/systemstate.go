@@ -0,0 +1,41 @@
+package simconnect
+
+import "context"
+
+// system states for RequestSystemState, the comment names the field of
+// RecvSystemState holding the answer.
+const (
+	SYSTEM_STATE_AIRCRAFT_LOADED = "AircraftLoaded" // String, path of the aircraft.cfg
+	SYSTEM_STATE_DIALOG_MODE     = "DialogMode"     // Integer, 1 while a dialog is open
+	SYSTEM_STATE_FLIGHT_LOADED   = "FlightLoaded"   // String, path of the .FLT file
+	SYSTEM_STATE_FLIGHT_PLAN     = "FlightPlan"     // String, path of the .PLN file
+	SYSTEM_STATE_SIM             = "Sim"            // Integer, 1 while the user is in control
+)
+
+// RequestSystemState requests the system state, one of SYSTEM_STATE_*, and
+// waits for the answer. the dispatcher has to be running.
+func (d *Dispatcher) RequestSystemState(ctx context.Context, state string) (*RecvSystemState, error) {
+	requestID := d.client.GetRequestID()
+
+	result := make(chan *RecvSystemState, 1)
+	d.HandleRequest(requestID, func(msg interface{}) {
+		if m, ok := msg.(*RecvSystemState); ok {
+			select {
+			case result <- m:
+			default:
+			}
+		}
+	})
+	defer d.HandleRequest(requestID, nil)
+
+	if err := d.client.RequestSystemState(requestID, state); err != nil {
+		return nil, err
+	}
+
+	select {
+	case m := <-result:
+		return m, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,84 @@
+package simconnect_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/supersidor/msfs2020-go/simconnect"
+	"github.com/supersidor/msfs2020-go/simconnect/simtest"
+)
+
+func TestRequestSystemState(t *testing.T) {
+	srv, _, d := start(t)
+	srv.SetSystemState(simconnect.SYSTEM_STATE_SIM, 1, 0, "")
+	srv.SetSystemState(simconnect.SYSTEM_STATE_AIRCRAFT_LOADED, 0, 0, `SimObjects\Airplanes\Asobo_C172sp_AS1000\aircraft.cfg`)
+
+	st, err := d.RequestSystemState(withTimeout(t), simconnect.SYSTEM_STATE_SIM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Integer != 1 {
+		t.Errorf("Sim is %d, want 1", st.Integer)
+	}
+
+	st, err = d.RequestSystemState(withTimeout(t), simconnect.SYSTEM_STATE_AIRCRAFT_LOADED)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.String != `SimObjects\Airplanes\Asobo_C172sp_AS1000\aircraft.cfg` {
+		t.Errorf("AircraftLoaded is %q", st.String)
+	}
+}
+
+func TestFlightLoadSave(t *testing.T) {
+	srv, s, d := start(t)
+
+	loaded := make(chan string, 1)
+	_, err := d.HandleSystemEvent(simconnect.SYSTEM_EVENT_FLIGHT_LOADED, func(msg interface{}) {
+		if e, ok := msg.(*simconnect.RecvEventFilename); ok {
+			loaded <- e.FileName
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the server handles the calls in order, the states are set by the time
+	// they are requested
+	if err := s.FlightLoad(`flights\LSZH.FLT`); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.FlightPlanLoad(`flights\LSZHLSGG`); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case name := <-loaded:
+		if name != `flights\LSZH.FLT` {
+			t.Errorf("FlightLoaded event for %q", name)
+		}
+	case <-time.After(timeout):
+		t.Fatal("no FlightLoaded event")
+	}
+
+	for state, want := range map[string]string{
+		simconnect.SYSTEM_STATE_FLIGHT_LOADED: `flights\LSZH.FLT`,
+		simconnect.SYSTEM_STATE_FLIGHT_PLAN:   `flights\LSZHLSGG`,
+	} {
+		st, err := d.RequestSystemState(withTimeout(t), state)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if st.String != want {
+			t.Errorf("%s is %q, want %q", state, st.String, want)
+		}
+	}
+
+	if err := s.FlightSave(`flights\saved`, "Saved", "over the Alps", 0); err != nil {
+		t.Fatal(err)
+	}
+	want := simtest.SavedFlight{FileName: `flights\saved`, Title: "Saved", Description: "over the Alps"}
+	eventually(t, "the flight to be saved", func() bool {
+		saved := srv.SavedFlights()
+		return len(saved) == 1 && saved[0] == want
+	})
+}
@@ -0,0 +1,11 @@
+// keyevent.go must not compile, see TestKeyEventCompile.
+package main
+
+import "github.com/supersidor/msfs2020-go/simconnect"
+
+func main() {
+	var c simconnect.Client
+	c.SendEvent(simconnect.KeyEvent("PAUSE_TOGGLE"))
+	c.SendEvent("PAUSE_TOGGLE")
+	simconnect.KEY_PAUSE_TOGGLE = simconnect.CustomKeyEvent("PAUSE_ON")
+}
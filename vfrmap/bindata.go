@@ -68,7 +68,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _indexHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd4\x3b\x59\x73\xdb\xb6\xba\xef\xfd\x15\x28\xce\x74\x22\x4d\x29\x88\xab\xb6\x48\x3a\x4d\xb3\xb4\xbd\xd7\x69\x32\x89\xdb\xce\xbd\x4d\x47\x03\x91\x30\xc5\x63\x10\xe0\x10\x90\x65\xc7\xe3\xff\x7e\x07\x20\x29\x01\x24\xbd\x24\xe7\xe1\xf4\xf6\xa1\x16\x81\x6f\xc7\xb7\xe1\x23\xb3\xfc\xf6\xd5\xbb\x97\xe7\xff\xf3\xfe\x35\xd8\xc9\x9c\xae\xbf\x59\x56\x7f\x00\x58\xee\x08\x4e\xd4\x0f\x00\x96\x32\x93\x94\xac\x73\x71\x21\x7c\xd7\x77\x47\x29\x1f\x5f\x5d\x94\x39\x2e\x96\xe3\x6a\xa7\x82\xa2\x19\xbb\x04\x25\xa1\x2b\x98\xc5\x9c\x41\x20\x6f\x0a\xb2\x82\x59\x8e\x53\x32\x2e\x58\x0a\xc1\xae\x24\x17\x2b\x98\x60\x89\x17\xc7\xd5\xe7\x5b\x2c\xc8\x24\x74\xb2\xdf\x7f\x7c\xf7\xe1\xe0\xfe\xf7\x4f\x29\x5f\xc1\x0e\x41\x21\x6f\x28\x11\x3b\x42\x64\x43\x85\x12\x7c\x41\x89\xfc\x97\x18\xd7\xbf\x50\x2c\x04\x04\xe3\x1a\x35\x27\x12\x03\x86\x73\xb2\x82\xb8\x28\x28\x19\xe5\x7c\x9b\x51\x32\x3a\x90\xed\x08\x17\xc5\x28\xc6\x05\xde\x52\x02\x41\xcc\x99\x24\x4c\xae\xe0\x0d\x11\xf0\x89\xc8\x42\x62\xb9\x17\xa3\x2d\x2e\x47\x5a\x30\x83\xca\x96\xe2\xf8\xf2\xa9\x74\xb4\xf1\x0c\xe4\xdf\xdf\x7c\x78\x8b\x8b\x06\x5b\xc4\x65\x56\x48\x20\xca\xb8\x4f\xdb\x7f\x09\xb8\x5e\x8e\x2b\x98\x27\x21\x94\x5c\x62\x49\x92\xb7\xb8\xbc\x24\x65\x2f\xba\x52\xa5\x3e\x34\x49\xae\xe5\x58\x19\xb4\xda\x03\xda\x39\x1c\xb0\xe5\xc9\x0d\xb8\xad\x97\x00\xd8\x91\x2c\xdd\xc9\x05\xf0\x5c\xf7\xbb\xe7\xc7\xd5\x1c\x97\x69\xc6\x16\xc0\x3d\x2d\x15\x38\x49\x32\x96\x1a\x6b\x77\xdf\xd4\x3f\x5a\x24\x93\x4c\x14\x14\xdf\x2c\xc0\x05\x25\xd7\x27\x02\xea\x69\x94\x64\x25\x89\x65\xc6\xd9\x02\xc4\x9c\xee\x73\xd6\x21\xf6\x8f\x1c\x17\x06\xb1\x2d\x8e\x2f\xd3\x92\xef\x59\x32\x8a\x39\xe5\xe5\x02\xa4\x25\xbe\x69\x51\x4d\x4b\x7e\x58\x00\xaf\x4b\x6b\x97\x25\x64\xb4\xdb\x27\x06\xc1\x82\x8b\xac\x12\x00\x6f\x05\xa7\x7b\x49\x4e\xc4\x24\x2f\x2c\x9d\x29\xb9\x90\xd6\xc2\xe7\x51\xc6\x12\x72\xbd\x00\x5e\xd8\x67\x19\x14\x91\xfc\xb4\x5e\xcb\xab\xdd\xc9\x10\x98\x33\x39\x12\xd9\x67\xb2\x00\x9e\x09\xad\xd7\x0f\xf5\x69\x6c\x39\x4d\x1e\x23\x14\xef\x4b\xa1\x96\x0b\x9e\x31\x49\xca\x93\xf2\x2d\xdd\x17\x3b\x7e\x45\x4a\xc3\x02\x35\xb9\xc3\x2e\x3b\xe9\x6e\x98\xcc\xb6\x56\xa3\x9b\x25\xab\x72\xad\x11\xa6\x59\xaa\x8e\x91\x98\xdc\xfb\xa9\xdb\x5a\xdb\x56\xea\x1e\xb0\xa5\xa7\x2d\x98\x28\x30\x43\x17\x19\xa1\x3d\x32\x8e\xca\xca\x76\xd6\x29\xdc\x75\xb0\xaf\x30\xdd\x13\x03\xdb\x90\xcc\x47\xee\xa3\x98\x1b\x91\x63\x4a\xfb\xf1\x3d\x13\xff\x24\x57\xed\x45\xc8\xeb\x21\x2e\x09\x25\x05\x2f\xe5\xa8\xe0\xc5\xbe\x00\x45\x8f\xe9\x5d\xe4\x9b\x54\x7b\x42\xf3\xfe\xe3\xb8\x9f\x0f\xc5\x5b\x42\x1f\x32\xa2\xdf\x96\x76\x39\xd6\xd9\x65\xfd\x8d\x99\xa9\x9a\xdc\x02\xf7\x82\x00\x21\xcb\x2c\x96\xf0\x79\x73\x64\x94\x48\xb0\xdb\x1f\x1d\x59\x3d\xe6\xb8\xb0\x1f\x55\x26\xeb\xae\x9c\xd7\xe2\x76\x77\x7e\x89\x39\x33\x57\xb5\x3e\xe6\xc2\x41\x58\xdb\x14\x33\xb2\xe9\x00\x35\xe6\xe8\xee\x5c\x70\x4a\xf9\x61\xa3\xf1\xc0\x0a\x5c\x60\x2a\x88\xb9\x4f\xb1\x90\x9b\x52\x23\x83\x15\xb8\xbd\x33\xf7\xc4\x55\xfa\x5e\xe1\x29\x19\x3f\xca\x32\x63\x29\x58\x81\x67\xcb\x7f\x5e\xe7\x14\x5c\x91\x52\x64\x9c\xad\xa0\x87\x5c\x08\x08\x8b\xb9\x32\xf7\x0a\xfe\x76\xfe\x66\x34\x83\x40\x48\xcc\x12\x4c\x39\x23\x2b\xc8\x38\xfc\xe7\x7a\x29\xae\x52\x70\x9d\x53\x26\x56\x70\x27\x65\xb1\x18\x8f\x0f\x87\x03\x3a\x04\x88\x97\xe9\xd8\x77\x5d\x77\x2c\xae\x54\x3d\xd6\x19\x63\x05\xfd\x70\x8e\x66\x21\x04\x87\x2c\x91\x3b\xf5\x38\x43\x7e\x04\x6d\xb6\x6b\x5d\xcd\x54\xed\x06\x59\xb2\x82\xcd\xc3\x1c\x8e\xd7\xcb\x02\xcb\x9d\x5e\x55\x3f\xa2\xa9\x1f\x42\x90\xac\xe0\x5b\xe0\x87\x53\x14\x79\xa1\x1b\x3a\x5e\xe4\xa3\xd0\xf5\x27\x13\xe0\x05\x73\xe4\x46\xd3\x99\xe7\x4c\x3d\x34\x73\xdd\x79\x38\x01\x31\x70\xd1\xcc\xf5\x27\x33\x67\xe4\xf9\x28\x8c\xbc\x59\x18\x01\x0f\x05\x7e\x38\x0d\x9c\x51\xe8\x22\x3f\x9a\x28\x5c\x17\xcd\xa2\x50\x41\x85\x11\x0a\xbd\x69\x34\x9f\x83\x51\x80\xe6\xa1\x1b\x84\xce\x28\x0c\x90\x3f\x99\x84\x13\x1f\x8c\x02\x0f\xf9\x81\xeb\xcd\x9c\x91\x1f\xa2\x49\xe0\x7a\xde\x3c\xd0\xab\xe1\x2c\x08\x22\x67\x14\xa1\xc0\x77\x83\xc9\x14\x8c\x5c\xe4\x4e\xe6\x81\x13\x21\x7f\xe6\x05\x13\x0f\x8c\x3c\xe4\x7a\x91\xeb\x3b\x81\x8f\xa2\xf9\x2c\x98\xcd\xd4\x92\xe7\x86\x53\xcf\x89\x5c\x34\x0b\x26\x13\xdf\x07\x67\xca\xc5\x67\xa1\x3f\xf5\xa6\x8e\x17\x85\x28\x98\x46\x13\x1f\xb8\x8e\x37\x73\x91\x37\x8f\xa6\x11\xa0\xc0\xf3\x5c\x14\xb9\x6e\x34\x73\x46\x91\x8b\xc2\x99\x1f\xcc\x41\x80\xe6\xf3\x20\xf0\x9d\x99\x8b\xfc\xb9\x37\x51\x32\xf9\xc8\x0d\xfd\x68\x32\x75\x7c\x1f\xcd\x83\x99\x37\x51\x32\xf9\x6e\x30\x0b\x23\xc7\x9b\xa0\xd9\x7c\x32\x0f\x40\xe8\x23\xc5\x6b\xea\x3b\x23\xcf\x43\xd1\x3c\xd2\xb6\x70\xdd\x99\xe3\x22\x2f\x98\x47\x0a\x60\xea\x05\x9e\xe7\x78\x2e\x9a\x7b\xb3\xe9\x5c\x51\x89\xdc\xb9\x3f\x77\x46\x8a\xca\xcc\xf7\x2a\x66\x61\x14\x4c\x43\x67\xe4\xfb\x28\x98\xcf\xdd\x00\xf8\x68\xe2\x79\x81\xef\x8c\x66\x2e\x0a\x22\xdf\x8d\x80\xe7\x79\x28\x88\xe6\xf3\xc8\x09\x67\x28\x72\x27\x9e\xa7\x68\x4d\x83\x70\xae\xf0\x22\x34\x9d\xfa\xf3\x08\x7c\x86\xe0\x22\xa3\x74\x54\xee\x29\x59\x41\x72\x45\x18\x4f\x92\x6a\x6d\x05\x37\x9b\x97\xef\xce\xde\x7d\xd8\x6c\x94\x6f\x28\x4f\x5b\x3f\xfb\xa6\x1d\x56\xca\xcd\x7f\x54\xb9\x1a\xac\xc0\x19\x52\xfd\xe2\xe0\x94\x4f\xd4\xe3\x6f\x25\x5d\x54\xae\x4e\x7e\xfb\xf0\xcb\xc0\xec\x1a\xc5\x55\xfa\xfd\x75\x4e\x1d\x08\xbe\xef\x09\x9b\x21\x2a\x49\x41\x71\x4c\x06\xf0\x1f\xd0\x81\xdf\xf9\x01\x34\x96\x4e\xb2\x39\xa0\x6e\xd6\x86\x8e\xc5\xf8\xa3\x4e\xc7\x7f\x4e\x42\x07\x4c\xc2\xbf\x9a\xbd\xbb\x61\x27\x33\x28\x96\x7f\xa8\x4a\xf5\x9f\x54\x41\x97\xca\x7f\x4b\x85\x9f\x4a\x42\xd8\x7f\x52\x85\x54\x09\xf0\x74\x15\xea\x9f\x09\x8f\xf7\x39\x61\x12\x71\x76\x49\x6e\xf6\x85\xca\xb7\x7b\xa6\xdb\xc3\x81\xf2\x47\x39\x34\x0a\x14\xc8\x2e\x40\xb5\x8a\x2e\xc9\x0d\x58\xad\x56\x00\xbe\x16\x31\x2e\x08\x1c\x1a\x50\xaa\x89\x4b\x53\x4a\x36\x55\x22\x1f\x0c\x4f\x65\xf2\x58\x0d\x8f\x3d\x45\xc3\x0d\xf0\x82\xb0\x4d\xc6\x36\x29\xe7\x0a\x37\xc7\x85\x18\x98\xcc\xaf\x70\x09\xf6\x25\x05\x2b\xa0\x93\xb1\xa8\xb3\x71\x05\x8e\x62\x9e\x8f\x15\xca\xf8\x07\x65\x48\xa3\x42\x20\x8a\x65\x26\xf7\x09\x01\xdf\x03\xe8\x74\x36\x39\x4b\xed\xdd\x1c\x17\x28\x25\xf2\x7f\x39\xcf\x07\x43\xb5\xfa\x19\x1e\x45\x38\x64\x2c\xe1\x07\xa4\x24\x1d\xec\x4b\xea\x3c\xdb\x6c\x29\x66\x97\xcf\x86\x9d\x4e\xe9\xa8\xd5\xbe\x48\xb0\x24\x6f\x71\x31\xc8\x45\x6a\xd9\x52\x2b\x54\x70\xa1\x7d\x86\x62\x79\xc6\x52\x05\x73\x94\xd7\x01\xfa\xa9\x11\xd0\x34\x62\x5d\x87\x91\x20\xf2\xac\x42\x2c\xb8\xb8\x07\xe0\x83\xba\xaf\x64\x9c\xbd\x60\x29\x25\x9a\x81\xba\x90\x2a\xd7\x3a\xfa\x80\xfa\xcf\x28\xd2\xa8\xe0\x02\x65\x8c\xa9\x16\xe0\x5a\x55\xd8\x5f\xf7\xf9\x96\x94\x8a\x87\x12\x6e\x88\x24\x7f\x93\x5d\x93\x64\x30\x19\x1e\xad\x66\x82\x28\xaf\x3d\x81\x98\x52\x8d\xc7\x26\x9b\x54\x99\x5a\xf3\xf9\xf9\xfc\xed\x99\x3a\xd7\x25\xae\x6e\xa5\x9f\x1e\x3f\xe1\x5a\x98\xa3\x00\x35\xe7\x07\x8e\xf1\x13\x5c\x57\x94\xd4\x9e\x58\x8e\xf1\x1a\x5a\x16\x50\xbe\x6d\x76\x1e\xf6\x61\x01\x4d\xb1\xc0\xec\x9c\x77\x8c\x7d\xd7\xea\xf4\xfa\x7d\xe0\xe7\xdf\x5e\xb5\x7d\x60\xb7\x4f\x10\xa6\xd5\x71\x5b\x16\x57\xc7\xd4\x6c\x3c\xb7\xc0\xeb\xc3\xeb\x40\xd7\xeb\x36\x30\xce\x4a\x51\x10\x92\x74\x69\xd7\x1b\x36\xf8\x15\x29\x65\x16\x63\xba\xe9\x47\xb2\xb7\xfb\x39\x6d\x64\xb9\xb7\x55\x81\x03\x7d\x1c\x06\x53\x0d\xa3\x8e\x64\x08\x6d\x22\x17\x14\x17\xa2\xc3\x56\xaf\xda\x80\xb2\xcc\xf2\x0e\x9c\x5a\xb4\xc1\xca\x7d\x92\x90\x72\xd3\x0b\x6d\xec\x75\x82\xf7\xa0\x62\x92\x91\x03\xf8\x83\x6c\x3f\xf2\xf8\x92\xc8\x01\x3c\x28\x6f\x54\x9a\xd4\x39\x80\xf2\x58\x87\x15\xda\x71\x21\x19\xce\xb5\x42\x8b\x3e\x00\xdd\xa6\x7e\x0f\xe0\xf8\x20\xe0\xd1\x6d\x0e\x02\x71\xa6\xf2\x88\x99\x6f\x4d\xd7\x18\x8f\x63\xce\x04\xa7\x04\x51\x9e\x2a\xf6\x3a\x3f\x9e\x08\xdc\x59\x94\x62\xca\x05\x79\x3a\x29\x0d\x7e\x1f\xad\x9c\x08\x81\x53\x8b\x1a\x69\xe7\xe1\x5c\xa8\xb6\xfa\xbf\x3e\xbe\xfb\x15\x15\xb8\x14\x64\x40\x90\x2a\x69\x46\x54\x74\x99\x2a\x00\xa8\x93\x9a\x01\x66\xb7\xf2\xb9\x48\x8d\x90\xb4\xc3\xc6\xd8\x50\x91\x9a\xe3\x02\x7c\xbb\x5a\x81\x3d\x4b\xc8\x45\xc6\x48\x62\x87\xab\x9d\x76\x4f\xfc\xee\x4e\x2a\xb7\xa3\x34\x63\x99\x54\x08\x6d\x5d\x5b\x29\x5a\x75\xe0\x53\x7f\xea\x8c\x3c\x34\x8d\xfc\xc0\x94\x4b\x41\x73\x91\xd7\xce\x73\x86\xce\x33\x4a\xce\xf0\x0d\x29\x07\xcd\xf5\xe1\x56\xdc\x21\x99\x51\xa2\x4b\x88\x90\x25\x21\x52\xa5\x15\x75\x9d\xb8\xfd\x7c\x37\xbe\xbd\xbe\x1b\xdf\xde\xdc\xa1\x82\xa5\xd0\xb1\xf4\xc9\xf1\xb5\x4a\x65\x0b\xe0\xcd\x1c\x73\x39\x63\xd5\xb2\x6f\xae\x5e\xf0\x32\xc7\x72\x01\x8c\x99\xa1\xb9\x2d\xf6\xdb\x84\xe7\x38\x63\x62\x01\xfe\x84\x58\x77\x70\xea\x7f\x31\xfc\xeb\x64\x27\xc3\x66\x5a\xad\x82\x30\x9c\x15\x9b\x18\xc7\x3b\x92\x6c\xb6\x58\x10\x75\x02\x4f\xd0\x54\x25\x5b\x54\xa3\x23\x46\xe4\x38\x25\xfc\x40\xb6\x9a\xd0\x58\x90\xf2\x2a\x8b\xc9\x58\xe6\x62\xec\x21\x17\xb9\xe3\x86\x51\xcd\xe1\x87\xd7\xef\x3f\xfe\xf4\x5d\xf0\x62\xee\xba\x73\x2f\xf8\xa1\x60\x4f\x37\x54\xd8\x6b\x28\x6b\x55\xe6\x62\x01\x54\x36\x72\xac\x22\x95\x10\x49\x62\xf9\x81\xc8\x8c\xe1\xee\xbe\x69\x3e\xe8\xf9\xf0\x0b\x2c\x2f\x4b\xcc\x44\x81\x4b\xc2\x64\x45\xf7\x5e\x7b\x0b\x89\x73\xc2\x36\xba\xb3\xde\x1c\xea\xee\xf8\x51\x5b\x57\x58\xba\x52\x4a\xce\x48\xf9\x37\x77\xaa\x5a\x49\x49\xca\x12\x67\xec\x4b\x15\xac\xb0\xfe\x7f\xa8\x78\xc0\x92\x94\x5f\xa8\xa0\xc6\xd1\xe3\xb8\xbf\xb9\x8e\x31\x2e\x25\xdf\x24\xb8\xbc\xbc\x4f\x43\x55\x3b\x35\x54\xb2\x1d\xd5\x81\x2d\x46\x4a\xe7\x94\xf2\x2d\xa6\x48\x08\x8a\x2e\xb0\x90\xf4\x46\x67\x08\x45\x6a\x83\x29\xfd\x1b\xeb\x6d\x8c\x02\x4f\x59\x50\x55\x0f\x98\xe3\xa2\x25\x27\x55\x86\x50\x74\x75\x71\xf8\xcb\x64\x5b\x4d\x0a\x17\xaa\xc6\x98\xcb\x9f\x2b\xb5\x5c\x73\x0d\x4b\x59\x66\xdb\xbd\x2a\x55\x2f\x39\x93\x25\xa7\x8b\x6a\x32\x66\xc9\x67\x1d\x4c\x85\xa2\x8b\x57\x5c\xa1\x20\x83\xca\xe0\xf6\x34\x86\x87\x5b\x2e\x25\xcf\x29\xb9\x90\xd0\x54\xb3\x02\x47\x38\x49\x5e\x18\x88\xf7\xf4\xe9\xdd\x9a\x16\xf3\xe2\x46\x0f\x33\x3f\x41\x20\x71\x99\x12\xb9\xfa\x04\xab\x3b\xd3\x27\x08\xf4\x28\x73\xf5\x09\x7e\x82\xeb\x77\x05\x51\x17\x5e\x42\x54\x05\xd6\xad\xf9\xbf\x23\x44\x5d\x69\x1e\x63\xaa\x40\x5f\xfc\xf2\xfe\xab\xd8\x2d\xc6\xfa\x2e\x62\xc4\xeb\x63\xdc\x3e\x6a\xc8\xaf\xd6\x4d\x87\x8f\xce\x0b\x8f\x31\x7a\xa9\x20\xdb\x7c\x7a\x18\x9e\x73\xd5\x44\xb5\x7d\x46\x85\xe7\x5b\x5c\xa8\x96\xc7\xbe\xfe\x40\xeb\x8c\xe0\x42\x39\xb3\x63\x43\x54\x2a\x82\xf3\x2a\x33\xc3\x45\x2b\xc1\xdf\x03\xad\x0a\xd5\x09\xd6\xa8\x78\xfd\xf0\x7f\xa8\xb4\x78\x82\xd7\x59\xb2\x05\xa9\x2d\x00\x5e\xa9\x84\x34\xf8\x55\xb9\x1f\x78\xcb\x13\x32\x84\x0b\x23\x55\x9d\x50\x9a\xee\xd7\xee\x77\xae\x48\x49\xf1\x4d\xbf\x25\x7e\xc5\x57\x59\xaa\x9b\x7b\x4c\xc1\x2b\xd5\xd6\x2e\xee\x69\x90\xba\x5c\x00\x30\xa2\xb1\xca\x0c\x83\xc6\xe6\x8e\xc9\x76\xd8\x7f\x46\xd5\xc5\x5e\xc7\x74\xf5\x53\xdd\x46\xed\x7c\x93\xc5\x2a\xa4\xed\xf1\xa0\x69\xa1\xd2\x9c\x08\x2c\x80\xdb\xb7\xf7\xae\xcc\xf4\x1b\x0e\x58\x65\x28\x23\x57\x9a\xe9\xa1\x9e\x32\x98\x82\xb6\xb6\xb6\x19\x4b\xde\xab\xdb\xfe\xe0\x0c\xe9\x5b\xff\xe0\x16\xef\x25\x7f\x8f\x59\x9d\xbd\xee\x86\xad\x29\x86\x7a\x7c\x59\xbd\xc9\x1d\x98\xd3\x02\x95\x9c\x87\x6d\x6f\xad\xb8\xbc\xb7\x3b\x74\xd7\x71\x3b\x82\x34\x2f\x33\x4c\xc3\x1d\x91\x1d\x70\xdb\xd5\xaa\xc1\x78\x40\xbb\x23\xc8\x13\xb5\x6c\xd4\xb2\xdf\x7c\x1c\x35\x6b\x88\x0b\x22\x37\x47\x90\xb6\xac\xca\x02\x66\xf1\x41\x9c\x0d\x9e\x25\x25\x4e\x85\xc4\xa5\x7c\xe6\xdc\x73\x69\x03\xed\x97\x2a\x56\xf7\xf9\xc0\xc0\xee\xce\x76\xbe\x8a\x5f\x4c\xb3\xf8\xf2\x01\x5e\x7d\x1a\x10\x75\x3c\x94\xa5\x8f\xd1\x56\xc1\xa0\xe3\x22\xde\x61\x96\x92\x07\xb8\xe8\x61\x24\xd2\x77\xef\xd5\xea\xfe\xa8\xef\xce\x71\x9a\xd1\x98\x8a\x8e\x81\x3d\x83\xb6\xe7\x3a\x80\x50\x41\xda\x7c\xac\xbc\xf5\x54\xe2\x7a\x3a\xdc\x47\xfc\x69\xe8\x3a\x86\x7b\x67\x4e\x66\x40\x76\xa7\x8f\x7d\x07\x51\x1f\x83\xc1\xb9\xe5\xcd\xa7\x78\xec\x9c\x58\xcb\x73\xd3\x42\xd4\xef\x72\x57\xa0\x82\x55\x87\x7c\x1c\xfe\xcd\x86\xc6\xcc\xb5\xda\x65\xa9\xb1\xfb\xdc\xba\xd1\x9b\x63\xd9\x66\xf6\x65\x9b\xb7\xc5\xfd\x38\x39\x3b\x89\xd0\xa5\xd0\x73\xf5\xef\x58\xe9\x48\x77\x47\x4a\xd2\x1a\x9d\x9c\x26\x00\x2d\x2b\xa5\x47\x2b\xb5\xda\x61\x63\x36\x22\xf4\x0c\x3f\xbb\xb8\x19\x18\x5a\xb4\x8a\x89\xbc\x29\x08\x5c\x00\xd8\x08\x01\x5b\xc5\x8c\x62\x09\x17\x40\x4f\x59\xde\x50\x8e\x3b\xd9\xe3\x78\x06\x48\x14\x34\x93\x03\xe8\xc0\xe1\x9f\xee\x5f\xc3\x36\x19\x96\x7e\x39\x19\xaf\x43\xa6\x31\xeb\x83\xb4\xec\x83\x51\x4e\xe0\xa2\xc8\xe9\x75\xde\xa1\xf9\xd1\xc2\x69\x5e\x64\x0f\x6d\x0e\x02\x09\xc2\x12\x6b\xb5\xe7\x14\xed\x14\x66\xd8\xf9\xe1\xc9\xae\x59\x64\x2a\x28\x7b\x7e\x59\xad\x55\x60\xc6\xc0\xb2\x27\x7e\x1f\xa3\x94\x70\xf6\xac\x79\xbb\xdd\xa5\x77\xfc\xd5\xca\xd4\xdf\x9a\xcf\xf7\xab\xbf\xcb\x12\xb2\xd9\xed\x13\x4b\x73\xed\x90\xbb\x7d\x02\x56\xa7\x17\x3d\x29\x91\xaf\x29\x51\x3f\x7f\xbc\xf9\x25\x19\xc0\xdd\x3e\x31\x3b\x45\x3d\x39\xd5\xed\x24\xaa\x3f\x22\x52\xa2\x33\x6e\x09\xfb\x28\xcd\xfa\xbb\x97\x2f\x24\x7c\xd7\x79\x2b\x85\x93\xe4\xf5\x15\x61\xf2\x2c\x13\x92\x30\x75\x9f\x7c\xf5\xee\x6d\x5d\x47\xcf\x38\x4e\x48\x02\x9d\x07\x5e\x58\x19\x27\xd2\x6a\xe3\x54\xd1\x5d\xdc\xaf\x80\x46\xac\x3e\xd4\x80\x56\x0c\x14\x5c\x3c\x0d\x6d\x54\x70\x61\xa3\xa6\x39\x2e\x9e\x88\xab\x40\x6d\xe4\xca\x09\x9e\x88\x5e\x01\x9b\x04\xee\xee\xcb\xe1\x5f\x66\x16\xfb\x13\x16\x5b\x42\xb1\xdf\xe6\x99\x7c\x32\xf2\xa8\x82\x6f\x99\xa8\x78\xc8\xba\x2d\x02\x69\xd1\x32\x70\x93\x77\x9e\x4e\xe2\x98\xce\xfa\x4d\x55\x79\xf9\xed\x97\xb1\x68\x40\x36\x3a\xfb\xd9\x12\xd6\x6f\x69\x1e\xc0\xae\x21\xfa\x90\x9b\xf7\x27\x0f\xf1\x6e\x5e\xb1\x3c\x80\xae\x5f\xbf\x3c\x85\x86\x82\xeb\x23\x64\xbf\x07\x7a\x80\x92\x0d\xd8\x47\x4a\xbf\xdb\x79\x80\x82\xde\xef\x43\x94\x65\x96\x3f\x74\xcc\x65\x96\xf7\xa1\x19\x6f\x7d\x1e\xc0\x36\xa0\xba\x44\xee\x8c\x9e\xf5\xde\xae\xf9\xf8\x2e\xe1\x98\xd9\xea\x5f\xe6\xb7\xa2\xcb\x71\xf3\x69\xf0\x72\xcb\x93\x9b\xfa\xf3\xd1\x24\xbb\xd2\xdf\x00\xa9\xec\xd9\x7c\xd9\xb5\x14\x05\x66\x20\xa6\x58\x88\x15\xd4\x9f\xde\xc1\xf5\x8b\xa3\x37\x54\xbb\x0a\xa7\x75\xfc\x0d\x46\xf5\xb4\x76\x97\x63\x05\xb9\xee\x81\x37\x8e\xda\x42\xaa\xbe\xb4\x3b\xa1\x82\xfa\xef\x43\x72\x1d\x23\xc4\xe0\x63\x87\xc4\x7d\x72\x3d\x4a\xfb\xe7\x26\x7c\x4e\xa4\xed\x78\xf9\x6a\xca\xbf\xa3\x8f\x6d\x63\xf6\x7a\xef\x57\x33\x78\x53\x79\xfa\x89\xbc\xe9\xda\x5f\x4d\xf5\x5c\x3b\xf2\x89\xa8\xe1\xb2\x5f\x4d\xf3\x03\x6a\x53\xed\xc6\xc3\x13\x88\x2f\xc7\x49\x76\xd5\x7c\x12\x7d\x3c\xad\xa6\x2f\x00\x9c\xe9\x4b\x64\xb5\x54\xf5\x2d\xcf\xe1\x5a\x3d\xa8\xac\xdb\x10\xb2\x43\x42\x15\xc5\x75\x4d\xf7\xb4\x53\x8d\xbe\x60\xf3\xc5\xb3\x6a\x28\x9e\x9f\x22\xa7\xc1\x35\x0b\xfa\xfa\x18\xa7\xcb\x62\xbd\xdc\x05\x6d\x00\x5d\xba\xd7\xcb\xf1\x2e\x58\x2f\xc7\x85\x0d\xbd\xdd\x4b\xc9\x59\x07\x43\x17\xec\x93\x52\xbd\xdf\xb2\x3c\xaf\x46\x8e\x20\x63\xc0\xfa\x10\xa1\x22\xf9\x64\x56\x75\x71\x3f\x31\x6b\x67\x21\x25\x7a\x97\xa6\x69\x37\xc3\x2e\xad\x8a\x6e\x4b\x50\x7d\xa0\x7a\xc1\xcb\x36\x9c\xae\xbd\xeb\x9f\xde\x7f\x5c\x2c\xc7\x1a\x68\xbd\xcc\x58\xb1\x97\xc6\x67\xef\xb0\x87\x7c\x85\xd6\x51\xf4\x7e\x36\x98\x4a\x23\xa3\x7c\x19\xaf\x63\x6d\xbf\xd7\xb2\x15\x81\xea\xa1\x97\x44\xdd\xa2\x18\xa6\xb6\x6f\x8a\xcf\xe1\xba\x59\x79\xcc\xe4\xc7\x70\x58\x8e\xab\x64\xbf\x1c\x57\xff\x42\xe4\xff\x02\x00\x00\xff\xff\x23\x86\xf0\x53\x39\x32\x00\x00")
+var _indexHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd4\x3b\x6b\x8f\xdb\xb6\xb2\xdf\xf3\x2b\x58\x1e\x14\xb1\x51\x99\xd6\xc3\xf2\x6b\x6d\xf7\x9d\xb6\xf7\x6e\x9a\x20\x49\x5b\xdc\xdb\x14\x06\x6d\x71\x65\x9d\xa5\x49\x41\xa4\xd7\xd9\xec\xd9\xff\x7e\x31\xd4\xc3\x94\x25\x7b\x9d\x9c\x0f\xa7\x17\x58\xb4\x16\x39\xef\x19\xce\x0c\x1f\x99\x7d\xf1\xc3\xab\xef\xdf\xfd\xcf\xeb\x1f\xd1\x46\x6f\xf9\xe2\xd9\x2c\xff\x1f\x42\xb3\x0d\xa3\x11\xfc\x40\x68\xa6\x13\xcd\xd9\x62\xab\x6e\x94\xef\xfa\x6e\x2f\x96\xfd\xbb\x9b\x6c\x4b\xd3\x59\x3f\x9f\xc9\xa1\x78\x22\x6e\x51\xc6\xf8\x1c\x27\x6b\x29\x30\xd2\xf7\x29\x9b\xe3\x64\x4b\x63\xd6\x4f\x45\x8c\xd1\x26\x63\x37\x73\x1c\x51\x4d\xa7\xd5\xe8\xd5\x8a\x2a\x36\x1c\x38\xc9\xef\xdf\xbd\x7a\xb3\x77\xff\xfb\xa7\x58\xce\x71\x83\xa0\xd2\xf7\x9c\xa9\x0d\x63\xba\xa4\xc2\x19\xbd\xe1\x4c\xff\x53\xf5\x8b\x5f\x64\xad\x14\x46\xfd\x02\x75\xcb\x34\x45\x82\x6e\xd9\x1c\xd3\x34\xe5\xac\xb7\x95\xab\x84\xb3\xde\x9e\xad\x7a\x34\x4d\x7b\x6b\x9a\xd2\x15\x67\x18\xad\xa5\xd0\x4c\xe8\x39\xbe\x67\x0a\x5f\x88\xac\x34\xd5\x3b\xd5\x5b\xd1\xac\x67\x04\xb3\xa8\xac\x38\x5d\xdf\x5e\x4a\xc7\x18\xcf\x42\xfe\xfd\xc5\x9b\x97\x34\x2d\xb1\xd5\x3a\x4b\x52\x8d\x54\xb6\x6e\xd3\xf6\x9f\x0a\x2f\x66\xfd\x1c\xe6\x22\x84\x4c\x6a\xaa\x59\xf4\x92\x66\xb7\x2c\x6b\x45\x07\x55\x0a\xa7\x69\xf6\x41\xf7\xc1\xa0\x39\x69\x64\x82\xc3\x41\x2b\x19\xdd\xa3\x87\x62\x08\xa1\x0d\x4b\xe2\x8d\x9e\x22\xcf\x75\xbf\xbc\xaa\x46\xb7\x34\x8b\x13\x31\x45\xee\x61\x28\xa5\x51\x94\x88\xd8\x1a\x7b\x7c\x56\xfc\x38\x22\x19\x25\x2a\xe5\xf4\x7e\x8a\x6e\x38\xfb\x70\x20\x00\x5f\xbd\x28\xc9\xd8\x5a\x27\x52\x4c\xd1\x5a\xf2\xdd\x56\x34\x88\xfd\x63\x4b\x53\x8b\xd8\x8a\xae\x6f\xe3\x4c\xee\x44\xd4\x5b\x4b\x2e\xb3\x29\x8a\x33\x7a\x7f\x44\x35\xce\xe4\x7e\x8a\xbc\x26\xad\x4d\x12\xb1\xde\x66\x17\x59\x04\x53\xa9\x92\x5c\x00\xba\x52\x92\xef\x34\x3b\x10\xd3\x32\xad\xe9\xcc\xd9\x8d\xae\x0d\x7c\xec\x25\x22\x62\x1f\xa6\xc8\x1b\xb4\x59\x86\x84\x6c\x7b\x18\x2f\xe4\x35\xe1\x74\x18\xbd\x91\x42\xf7\x54\xf2\x91\x4d\x91\x67\x43\x9b\xf1\x7d\xe1\x8d\x95\xe4\xd1\x53\x84\xd6\xbb\x4c\x81\x3d\x52\x99\x08\xcd\xb2\x72\xe2\xf1\x58\xf7\xe9\x46\xde\xb1\x0c\x3d\x1c\x93\xdb\x6f\x12\xcd\x5a\x4c\x56\xb7\x56\xa9\x5b\x4d\x56\x08\xad\x1e\xe5\x49\x0c\x6e\x64\x36\xf7\x76\xea\x75\xad\xeb\x56\x6a\x3a\xb8\xa6\x67\x5d\x30\x95\x52\x41\x6e\x12\xc6\x5b\x64\xec\x65\xb9\xed\x6a\x5e\x78\x6c\x60\xdf\x51\xbe\x63\xe8\xa1\x4d\x32\x9f\xb8\x4f\x62\x2e\xd5\x96\x72\xde\x8e\xef\xd9\xf8\x07\xb9\x8a\x28\x22\x5e\x0b\xf1\x3c\x0d\xb5\x2d\x1e\x21\x05\x6b\xd0\x6a\xc4\xd8\x05\x9e\x38\x0a\x9b\x33\x71\xd6\x74\x85\xcc\xa8\x88\x59\xd3\x17\x9a\x71\x96\xca\x4c\xf7\x52\x99\xee\x52\x64\x2f\xd8\x52\x52\x97\xf8\x6c\x7b\x36\xa3\x9c\x96\xfd\xf1\x24\x1f\x4e\x57\x8c\x37\xb9\x1d\x7c\x6f\x31\xcd\xa9\xcc\xfa\x26\xbf\x2f\x9e\xd9\x09\xb6\x4c\x89\x78\xa7\x18\x52\x3a\x4b\xd6\x1a\x5f\x95\xda\x71\xa6\xd1\x66\x57\xd9\x05\x3e\xb7\x34\xad\x7f\x42\x02\x6e\x8e\xbc\x2b\xc4\x6d\xce\xfc\xb2\x96\x55\xaa\x83\x51\xa3\x8f\x3d\xb0\x57\xb5\x69\x4e\x05\x5b\x36\x80\x4a\x73\x34\x67\x6e\x24\xe7\x72\xbf\x34\x78\x68\x8e\x6e\x28\x57\x95\xdb\x60\x9e\x53\xa5\x97\x99\x11\x0e\xcd\xd1\xc3\xa3\x3d\xa7\xee\xe2\xd7\x80\x07\x32\xbe\xd5\x59\x22\x62\x34\x47\xcf\x67\x5f\x7f\xd8\x72\x74\xc7\x32\x95\x48\x31\xc7\x1e\x71\x31\x62\x62\x2d\x21\x0c\xe7\xf8\xb7\x77\x2f\x7a\x63\x8c\x94\xa6\x22\xa2\x5c\x0a\x36\xc7\x42\xe2\xaf\x17\x33\x75\x17\xa3\x0f\x5b\x2e\xd4\x1c\x6f\xb4\x4e\xa7\xfd\xfe\x7e\xbf\x27\xfb\x80\xc8\x2c\xee\xfb\xae\xeb\xf6\xd5\x1d\xb4\x11\x26\x00\xe7\xd8\x1f\x4c\xc8\x78\x80\xd1\x3e\x89\xf4\x06\x3e\xc7\xc4\x0f\x71\x9d\xed\xc2\x14\x61\x68\x39\x50\x12\xcd\x71\xf9\x31\xc1\xfd\xc5\x2c\xa5\x7a\x63\x46\xe1\x47\x38\xf2\x07\x18\x45\x73\xfc\x12\xf9\x83\x11\x09\xbd\x81\x3b\x70\xbc\xd0\x27\x03\xd7\x1f\x0e\x91\x17\x4c\x88\x1b\x8e\xc6\x9e\x33\xf2\xc8\xd8\x75\x27\x83\x21\x5a\x23\x97\x8c\x5d\x7f\x38\x76\x7a\x9e\x4f\x06\xa1\x37\x1e\x84\xc8\x23\x81\x3f\x18\x05\x4e\x6f\xe0\x12\x3f\x1c\x02\xae\x4b\xc6\xe1\x00\xa0\x06\x21\x19\x78\xa3\x70\x32\x41\xbd\x80\x4c\x06\x6e\x30\x70\x7a\x83\x80\xf8\xc3\xe1\x60\xe8\xa3\x5e\xe0\x11\x3f\x70\xbd\xb1\xd3\xf3\x07\x64\x18\xb8\x9e\x37\x09\xcc\xe8\x60\x1c\x04\xa1\xd3\x0b\x49\xe0\xbb\xc1\x70\x84\x7a\x2e\x71\x87\x93\xc0\x09\x89\x3f\xf6\x82\xa1\x87\x7a\x1e\x71\xbd\xd0\xf5\x9d\xc0\x27\xe1\x64\x1c\x8c\xc7\x30\xe4\xb9\x83\x91\xe7\x84\x2e\x19\x07\xc3\xa1\xef\xa3\x6b\xe4\x12\x7f\x3c\xf0\x47\xde\xc8\xf1\xc2\x01\x09\x46\xe1\xd0\x47\xae\xe3\x8d\x5d\xe2\x4d\xc2\x51\x88\x38\xf2\x3c\x97\x84\xae\x1b\x8e\x9d\x5e\xe8\x92\xc1\xd8\x0f\x26\x28\x20\x93\x49\x10\xf8\xce\xd8\x25\xfe\xc4\x1b\x82\x4c\x3e\x71\x07\x7e\x38\x1c\x39\xbe\x4f\x26\xc1\xd8\x1b\x82\x4c\xbe\x1b\x8c\x07\xa1\xe3\x0d\xc9\x78\x32\x9c\x04\x68\xe0\x13\xe0\x35\xf2\x9d\x9e\xe7\x91\x70\x12\x1a\x5b\xb8\xee\xd8\x71\x89\x17\x4c\x42\x00\x18\x79\x81\xe7\x39\x9e\x4b\x26\xde\x78\x34\x01\x2a\xa1\x3b\xf1\x27\x4e\x0f\xa8\x8c\x7d\x2f\x67\x36\x08\x83\xd1\xc0\xe9\xf9\x3e\x09\x26\x13\x37\x40\x3e\x19\x7a\x5e\xe0\x3b\xbd\xb1\x4b\x82\xd0\x77\x43\xe4\x79\x1e\x09\xc2\xc9\x24\x74\x06\x63\x12\xba\x43\xcf\x03\x5a\xa3\x60\x30\x01\xbc\x90\x8c\x46\xfe\x24\x44\x1f\x31\xba\x49\x38\xef\x65\x3b\xce\xe6\x98\xdd\x31\x21\xa3\x28\x1f\x9b\xe3\xe5\xf2\xfb\x57\xd7\xaf\xde\x2c\x97\x10\x1b\x10\x69\x8b\xe7\xf6\xaa\x4e\xcb\x30\xff\x0e\x72\x22\x9a\xa3\x6b\x02\x6d\x6e\xe7\x90\x4f\xe0\xf3\xb7\x8c\x4f\xf3\x50\x67\xbf\xbd\xf9\xa5\x63\x37\xbb\xea\x2e\xfe\xea\xc3\x96\x3b\x18\x7d\xd5\xb2\x6c\xba\x24\x63\x29\xa7\x6b\xd6\xc1\xff\xc0\x0e\xfe\xd2\x0f\xb0\x35\x74\x90\xcd\x41\xd8\x24\x65\xdc\x75\x6a\x8c\xdf\x9a\xfa\xf8\xe7\x70\xe0\xa0\xe1\xe0\xaf\x72\xee\xb1\x7b\xd5\xa6\xc2\x1f\x50\x60\xff\x93\x2a\x98\xfe\xe1\xdf\x52\xe1\xa7\x8c\x31\xf1\x9f\x54\x21\x06\x01\x2e\x57\xa1\xf8\x19\xc9\xf5\x6e\xcb\x84\x26\x52\xdc\xb2\xfb\x5d\x0a\xf9\x76\x27\x4c\x57\xdb\x81\x78\xd4\x5d\xab\x40\xa1\xe4\x06\xe5\xa3\xe4\x96\xdd\xa3\xf9\x7c\x8e\xf0\x8f\x6a\x4d\x53\x86\xbb\x16\x14\xf4\x9e\x71\xcc\xd9\x32\x4f\xe4\x9d\xca\x60\x56\x35\xac\xca\x6f\xc9\x0d\xc9\x94\x89\x65\x22\x96\xb1\x94\x80\xbb\xa5\xa9\xea\xd8\xcc\xef\x68\x86\x76\x19\x47\x73\x64\x92\xb1\x2a\xb2\x71\x0e\x4e\xd6\x72\xdb\x07\x94\xfe\x37\x10\xce\x56\x85\x20\x9c\xea\x44\xef\x22\x86\xbe\x42\xd8\x69\x4c\x4a\x11\xd7\x67\xb7\x34\x25\x31\xd3\xff\x2b\xe5\xb6\xd3\x05\x9c\x8f\xb8\x12\x61\x9f\x88\x48\xee\x09\x48\xda\xd9\x65\xdc\x79\xbe\x5c\x71\x2a\x6e\x9f\x77\xaf\x4e\x6a\xb5\x4b\x23\xaa\xd9\x4b\x9a\x76\xb6\x2a\xae\xd9\xd2\x28\x94\x4a\x65\x62\x86\x53\x7d\x2d\x62\x80\xa9\xe4\x75\x90\xf9\x2a\x05\xb4\x8d\x58\xd4\x61\xa2\x98\xbe\xce\x11\x53\xa9\x4e\x00\xbc\x81\x6d\x56\x22\xc5\xb7\x22\xe6\xcc\x30\x80\x7d\x34\x84\x56\x15\x03\xf0\x67\x15\x69\x92\x4a\x45\x12\x21\xa0\x05\xf8\xa0\xd1\x1c\xfd\xba\xdb\xae\x58\xd6\x81\x61\x4e\x75\x97\x68\xf9\x22\xf9\xc0\xa2\xce\xb0\x5b\x59\xcd\x06\x11\xb1\x0d\x62\x4b\xd5\xef\xdb\x6c\x62\x30\xb5\xe1\xf3\xf3\xbb\x97\xd7\xe0\xd7\x19\xcd\x37\xd3\xef\x9f\xf6\x70\x21\x4c\x25\x40\xc1\xf9\x8c\x1b\xdf\xe3\x45\x1e\x2b\x30\xa7\x66\x7d\xba\x38\x74\x49\x65\x6c\xdb\x9d\x47\xdd\x59\xd0\xf4\xa5\x24\xa5\xe2\x9d\x6c\x18\xfb\x10\xd3\xc8\xc4\x74\x7b\x0c\xfc\xfc\xdb\x0f\xc7\x31\xb0\xd9\x45\x84\xf2\x3c\x3c\x6b\x16\x07\x37\x95\x13\x07\x4e\x00\x5e\x38\xaf\x01\x5d\x8c\xd7\x81\x69\x92\xa9\x94\xb1\xa8\x49\xbb\x98\xa8\x83\xdf\xb1\x4c\x27\x6b\xca\x97\xed\x48\xf5\xe9\x76\x4e\x4b\x9d\xed\xea\xaa\xe0\x0e\xb8\xcb\x66\x6a\x60\xc0\x25\x5d\x5c\x27\x72\xc3\x69\x5a\x8f\x3c\x40\x33\xa3\x75\x40\x9d\x25\xdb\x06\x1c\x0c\xd6\xc1\xb2\x5d\x14\xb1\x6c\xd9\x0a\x6d\xcd\x35\x16\xef\x1e\xd6\xa4\x60\x7b\xf4\x07\x5b\xbd\x95\xeb\x5b\xa6\x3b\x78\x0f\xf9\x06\x34\x29\x72\x00\x97\x6b\xb3\xac\xc8\x46\x2a\x0d\xe7\x3a\xa0\xd0\xb4\x0d\x00\xf2\x0c\x4c\xf6\xf7\x0a\x57\x61\xb3\x57\x44\x0a\xc8\x23\x76\xbe\xb5\x43\xa3\xdf\x5f\x4b\xa1\x24\x67\x84\xcb\x18\xd8\x9b\xfc\x78\x20\x50\x35\xbd\x86\xd2\x9a\x4b\xc5\x2e\x27\x65\xc0\x5b\x68\x1d\x45\xec\x5b\xb3\x9f\x3b\x0e\x5a\x48\x5b\xc5\x4e\x6f\x7e\x28\x1f\x31\xd3\x3f\x72\x06\x3f\xbf\xbb\xff\x25\xea\xe0\x1c\xe2\xc0\x23\x5f\x5f\xe0\xcf\xb5\x14\x82\xad\x35\x8b\x6c\xa2\xa8\x20\x49\xcc\xfe\x86\x14\x1b\x47\xc8\x09\xb0\x75\xb4\xe2\xe4\x11\x31\xae\x58\x1b\xe6\xb1\x87\x61\x18\xd2\x7a\x07\x3e\x58\x96\xc9\x0c\x7d\x8d\x30\xaa\xc2\x31\x1f\x32\x61\x88\xa6\x08\xdb\xa2\x9e\x96\x66\xc5\xe5\xfa\xd6\x16\xa7\x19\x3b\x44\x8a\x2d\x53\x8a\xc6\x35\x87\xd4\xd2\x09\x98\x70\xab\x60\x67\xf2\x5f\x6f\x5f\xfd\x4a\x52\x9a\x29\xd6\x61\x04\xba\x82\xee\xd5\x19\xbf\x01\x00\x36\x75\xa1\xc5\xae\x70\x50\x86\xa0\x22\x97\xa6\xaf\x19\xa9\xe1\xd1\x03\x01\x84\x32\xa6\x77\x99\x68\x6a\xd5\x42\x7c\x25\xe5\x2d\x54\x9f\x23\xf2\x06\x8e\xa6\xe8\x8b\xf9\x1c\xed\x44\xc4\x6e\x12\x71\xec\x60\x84\x68\x14\x7d\x57\xa0\x37\x44\x78\xbc\x48\x1c\xab\x74\xe7\xeb\xd8\xca\xe0\xf5\x2c\x6b\x4d\x5c\x20\x5b\xbd\x4a\x37\x39\x3f\x5e\x3d\x3b\x5e\x22\xc7\xca\x58\xf4\x3e\xbb\xb0\x03\x62\x69\x60\xd3\x16\xc0\x8f\xbc\xac\x3a\xe8\xc1\x9c\xce\x4e\x2d\x17\x1c\x3a\x51\x54\xa1\x11\x1a\x45\xef\x64\x67\x4b\xd3\xb6\xc9\x55\x22\xa2\xd7\xb0\xdd\xee\x5c\x13\x53\x84\x3b\x0f\x74\xa7\xe5\x6b\x2a\xa6\xf9\x36\xfb\xb1\x0b\x4d\xc5\xf7\xf9\x01\x70\xa7\x22\x80\x0e\x5c\x67\xab\x6c\x51\x2e\xa1\x52\xa3\xe3\x8e\x00\x55\x00\xa5\x92\x47\x10\x36\x91\xb2\xce\x41\x8e\x44\x37\xfa\xd0\x6d\x75\xbb\x57\xc7\xab\xab\xb2\x7e\x22\x12\x0d\xee\x7a\xc2\xec\xb0\x5d\x1e\xf9\x23\xa7\xe7\x91\x51\xe8\x07\x76\x54\x00\xb4\x54\xdb\x22\xd3\x5f\x93\x77\x09\x67\xd7\xf4\x9e\x65\x9d\x72\xaf\xff\xa0\x1e\x89\x4e\x38\x33\xfd\x9e\xd2\x19\x63\x1a\x7a\x00\x99\xc5\xfd\x87\x8f\x8f\xfd\x87\x0f\x8f\xfd\x87\xfb\x47\x02\x37\x09\x8e\x25\x06\x74\x0a\x1f\xa0\xef\x98\x22\x6f\x7c\xe8\xc6\x11\xda\x26\x22\x1f\xf6\xed\xd1\x1b\x99\x6d\xa9\x9e\x22\xeb\x5e\xc2\x9e\x56\xbb\x55\x24\xb7\x34\x11\x6a\x8a\xfe\xc4\x14\x6c\xbb\x82\xff\xac\xf1\x5f\x15\x94\x1d\x07\x46\xad\x94\x09\x9a\xa4\xcb\x35\x5d\x6f\x58\xb4\x5c\x51\xc5\x20\xfe\x2f\xd0\x14\x3a\x23\xa3\x2e\x4d\x52\x22\x98\xee\xc7\x4c\xee\xd9\xca\x10\xea\x2b\x96\xdd\x25\x6b\xd6\xd7\x5b\xd5\xf7\x88\x4b\xdc\x7e\x01\x59\x72\xf8\xe6\xc7\xd7\x6f\x7f\xfa\x32\xf8\x76\xe2\xba\x13\x2f\xf8\x26\x15\x97\x1b\x6a\xd0\x6a\xa8\xda\xa8\xde\xaa\x29\x82\xd6\xc1\x1e\xec\xf7\x23\xa6\xd9\x5a\xbf\x61\x3a\x11\xb4\x39\x6f\x9b\x0f\x7b\x3e\xfe\x04\xcb\xeb\x8c\x0a\x95\xd2\x8c\x09\x9d\xd3\x3d\x69\x6f\xa5\xe9\x96\x09\xd8\x0b\xac\x6f\x97\xfb\x62\x2b\xfb\xa4\xad\x73\x2c\xb3\x71\xd1\x52\xb0\xec\x6f\x1e\x54\x85\x92\x9a\x65\x19\x4d\xc4\xa7\x2a\x98\x63\xfd\xff\x50\x71\x4f\x35\xcb\x3e\x51\x41\x83\x63\xce\xa8\xff\xe6\x3a\xae\x69\xa6\xe5\x32\xa2\xd9\xed\x29\x0d\xa1\xd1\x35\x50\xd1\xaa\x57\x2c\x6c\xd5\x03\xa7\xc6\x5c\xae\x28\x27\x4a\x71\x72\x43\x95\xe6\xf7\x26\x43\x00\xa9\x25\xe5\xfc\x6f\xac\x77\xf5\xc3\xce\x82\x50\x3d\xf0\x96\xa6\x47\x72\x72\x30\x04\xd0\x35\xc5\xa1\x3a\x31\x81\xbf\xfc\x58\x1f\xee\xa8\x94\x3d\xfc\xd1\xc8\xef\xb9\xf6\x18\xd5\x3a\x4b\x56\x3b\x28\x55\x50\x4b\x33\xc9\x8b\xfa\x5a\xc1\x58\x47\x30\x79\xd2\xce\x51\x4c\xd5\x87\xfb\xd7\x4c\x72\x62\x51\xe9\x3c\x1c\xae\xfa\xf0\x4a\x6a\x2d\xb7\x70\x13\x83\x6d\x35\x73\x70\x68\x00\xbe\xb5\x10\x4f\x6c\xaa\x9b\x35\x6d\x2d\xd3\x7b\x73\xf3\xf0\x1e\x23\x4d\xb3\x98\xe9\xf9\x7b\x0c\x49\x4d\xdc\xbe\x87\xc3\xf1\x7b\xce\xe6\xef\xf1\x7b\xbc\x78\x95\x32\x38\x9d\x62\x0c\x2a\xb0\xd9\x47\xff\x3b\x42\x14\x95\xe6\x29\xa6\x00\xfa\xed\x2f\xaf\x3f\x8b\xdd\xb4\x6f\x8e\x86\xac\xf5\xfa\x14\xb7\xb7\x06\xf2\xb3\x75\x33\xcb\xc7\xe4\x85\xa7\x18\x7d\x0f\x90\xc7\x7c\x5a\x18\x56\x2d\x5d\x35\x07\x31\x03\xcb\xf3\x25\x4d\xa1\xe5\xb1\x63\x18\x21\x5c\xf3\x11\x9e\x42\x30\xdb\xf1\x89\x10\xce\x55\x44\xef\xf2\xcc\x8c\xa7\x47\x09\xfe\x04\x34\x14\x2a\x3c\x6d\xa9\x78\xed\xf0\x7f\x50\x6d\xc3\x9b\x2c\x79\x04\x69\x2c\x80\x7e\x80\x84\xd4\xf9\x15\xc2\x0f\xbd\x94\x11\xeb\xe2\xa9\x95\xaa\x0e\x28\xe5\x56\xb5\x1a\x00\x33\xc0\x95\x30\xa7\xf7\xed\x96\xf8\x95\xde\x25\xb1\xd9\x89\x53\x8e\x7e\x80\x0d\xd4\xf4\x44\x83\xd4\xe4\x82\x90\xb5\x1a\xf3\xcc\xd0\x29\x6d\xee\xd8\x6c\xbb\xed\x3e\xca\xdb\xf7\x66\x27\x5f\x01\xe4\x87\xb4\xd3\xc3\x29\xb2\x39\xcb\x3f\x08\x82\x50\x66\x1f\xdf\x4d\x91\xdb\x36\xf7\x2a\x4b\xcc\x03\x07\x9c\x67\x28\xec\xb4\x66\xc1\xe2\x48\xd0\x16\xf4\x68\xea\xc2\xdd\x81\x75\xe4\x68\x6f\x16\xec\xa3\x3d\x48\xce\xdd\xe3\x68\xcd\xb9\xbc\xae\x77\xe8\xae\xe3\x36\x04\x29\x6f\x1e\x6d\xc3\x55\xc8\x0e\x7a\x78\x3c\x89\x51\x73\xc3\x09\x90\x4f\xdc\x03\xd5\xaf\x29\x2b\xcd\x4a\xe2\x8a\xe9\x65\x05\x72\x2c\x2b\x58\xc0\x92\x34\x25\x52\x74\x9e\x47\x19\x8d\x95\xa6\x99\x7e\xee\x54\xfb\x99\xce\xd1\x69\xe3\xd1\x0d\x68\xad\xfb\x3c\x73\xba\x5e\x2b\x2a\x25\xbf\x35\x4f\xd6\xb7\x67\x78\xb5\x69\xc0\xc0\x3d\x5c\xc4\x4f\xd1\x86\xc5\x60\xd6\xc5\x7a\x43\x45\xcc\xce\x70\x81\x4d\x38\x23\xe6\xa0\x6c\x3e\x3f\xbd\xea\xeb\x48\xa5\xef\x20\xcc\x60\x75\x74\xaa\x75\x62\x2e\x8c\x2c\xe9\xaa\x83\xa1\x23\x3e\xb5\xbc\x75\x29\x71\x73\x95\xd3\x46\xfc\x32\x74\xb3\x86\xeb\xe8\x2d\x0b\xb2\xb9\xa7\x6d\x73\x44\xe1\x06\xf4\x70\x2a\x9a\x0f\xeb\xb1\xe1\xb1\x8a\x56\x71\xdc\x9e\xaa\xe2\xbd\xc8\x1c\xe5\xb0\xe0\xe4\x6a\x63\x3e\x3e\x1c\xe6\x97\xb3\x22\xb6\x66\x0f\x74\xc1\xc4\xf6\x1d\x4a\xb9\x81\xaf\x9b\xf7\x88\x7b\x09\x64\x89\xd0\xa4\x70\xf5\xec\xd8\x62\x4d\x2b\x55\x74\x37\x2c\x63\x47\xe7\x9c\x87\x13\x80\x23\x2b\xc5\x95\x95\xba\x57\xa7\x4e\xe1\xe0\xe5\x84\x88\x93\x9b\x7b\xfb\xd8\xc3\xd6\x08\x21\x0c\xc7\x60\x78\x8a\x70\x29\x44\xad\x2d\x45\x08\x73\xaa\xf1\x14\x99\xf3\xbc\x17\x5c\xd2\x46\xf6\xa8\x7c\x40\x54\xca\x13\xdd\xc1\x0e\xee\xfe\xe9\xfe\x65\x5d\xda\xc1\x1f\xe6\x22\xfe\x74\x32\x5e\x83\x4c\x69\xd6\xb3\xb4\x4a\xa0\x9c\x20\x04\x81\x4b\xc2\x23\x42\xc5\xe5\x02\x9e\x96\xf7\x3c\xb6\xf3\x8a\xc9\x2e\xfa\xd7\xbf\xea\xe5\x09\x24\x28\x8e\xfc\xdb\x31\xcb\xd9\x26\xea\x61\xc1\x58\xfe\xb2\x4f\x43\xeb\xc7\x74\x7b\x45\x14\x13\x51\x6d\xb4\x25\x72\xea\x69\xd3\xf2\xed\xf9\xab\x1f\xbb\xb0\xe5\x50\xb5\xdb\x04\x9c\x8f\xe5\x35\xfc\xfc\x49\xf5\x53\x94\x22\x29\x9e\x6b\x74\x8a\xde\xb3\x13\xd5\xe1\x0b\xfb\xfb\xb4\xfa\xf0\x84\x6e\xb9\xd9\x45\x35\xcd\xcd\x22\x80\xc7\x68\x67\x8e\xf2\x37\xbb\xc8\xee\x4e\xe1\x39\xd1\x93\xc7\xf4\x4f\xd2\x2c\xde\xf3\x7d\x22\xe1\x4a\xab\x8a\x30\x8d\xa2\x1f\xe1\x42\xfa\x3a\x51\x9a\x09\xd8\xc3\xfe\xf0\xea\x65\x51\xbb\xaf\x25\x8d\x58\x84\x9d\x33\x37\xda\x96\x47\x8e\x5a\x47\x28\xf4\xd3\xd3\x0a\x18\xc4\xfc\xc5\x98\x7d\xe7\x8e\x60\x77\x78\x19\x5a\x2f\x95\xaa\x8e\x0a\x17\xa2\x17\xe2\x02\x68\x1d\x39\x0f\x82\x0b\xd1\x73\x60\xdc\x6d\x6d\x79\xeb\x09\xe2\xd3\xcc\x52\xe2\xb6\x59\x46\xed\x56\xdb\x44\x5f\x8c\xdc\xcb\xe1\xeb\x34\xe2\x54\x5d\x4e\x20\x4e\x8f\x0c\x5c\xe6\xba\xcb\x49\x94\x18\x27\x4c\x95\x47\xf9\xc3\xa7\xb1\x28\x41\x96\x26\xe3\xd6\x25\x2c\x92\xe9\x19\xec\x02\xa2\x0d\xb9\xcc\xa7\xe7\x78\x17\x20\xe7\xd0\xcd\xfd\xec\x25\x34\x00\xae\x8d\x50\xfd\xa2\xf8\x0c\xa5\x3a\x60\x1b\x29\x73\xf9\x7b\x86\x82\x99\x6f\x43\x84\x4b\xdf\x33\x78\x30\xdd\x86\x66\x5d\x0b\x9f\xc1\xb6\xa0\x9a\x44\x0e\x77\x45\x67\x3a\xf5\xea\xfe\xe2\xea\x59\xbd\x31\xb4\xdf\xc0\xcf\xfa\xe5\x3f\x79\x98\xc1\xab\xf4\xe2\x59\x7c\x94\xdc\x99\x47\x82\x90\x3d\xcb\xa7\x9f\x33\x78\x4b\x8c\xd6\x9c\x2a\x35\xc7\xe6\x49\x31\x5e\x7c\x5b\xb8\x69\x5a\xcc\x02\x4e\xe5\xba\x5c\xea\x12\x23\xff\x5a\xb8\xb3\x3e\x40\x2e\x5a\xe0\x2d\x57\xd7\x90\xf2\x17\xc4\x07\x54\x54\xfc\xff\x9c\x5c\xd5\x0a\xb1\xf8\xd4\x97\xc4\x29\xb9\x9e\xa4\xfd\x73\xb9\x7c\x0e\xa4\xeb\xeb\xe5\xb3\x29\xff\x4e\xde\x1e\x1b\xb3\x35\x7a\x3f\x9b\xc1\x0b\x88\x64\x9b\xbc\x1d\xda\x9f\x4d\xf5\x9d\x09\xe4\x03\x51\x2b\x64\x3f\x9b\xe6\x1b\x72\x4c\xb5\xb9\x1e\x2e\x20\x3e\xeb\x47\xc9\x5d\xf1\xb3\xa2\x54\xbe\xf3\xc7\x48\x0a\xb3\x71\x9d\xe3\x43\xdf\x72\x85\x17\xf0\x01\x7d\x42\x8d\x50\xb9\x22\x8a\x8b\xef\x45\x41\xb9\x3e\x09\x05\xb3\x39\x63\xfa\x98\x39\x2e\xfa\x8d\x29\x34\x1b\x57\x87\x55\x55\xe2\x5a\xa5\xb3\x9a\x44\x68\x96\x2e\x66\x9b\xe0\x18\xc0\x94\xf5\xc5\xac\xbf\x09\x16\xb3\x7e\x5a\x87\x5e\xed\xb4\x96\xa2\x81\x61\x8a\xf9\x41\xe1\xd6\x87\x70\x57\xf9\x11\x28\x4a\x04\xaa\xbd\x62\xca\x49\x5e\xcc\xaa\x28\xfc\x07\x66\xc7\x19\x0a\x2f\xda\x68\xda\x76\xb3\xec\x52\x2f\x96\x47\xa6\xc9\x5f\xb7\xdf\xc8\xec\x18\xce\xd4\xe5\xc5\x4f\xaf\xdf\x4e\x67\x7d\x03\xb4\x98\x25\x22\xdd\x69\xeb\x9f\xfa\xe0\x16\xf2\x39\x5a\x43\xd1\xd3\x6c\x28\xd7\x56\xb6\xf9\x34\x5e\x65\x8d\xc6\x27\x2d\x9b\x0b\x9b\x9b\xaa\x95\x44\xd1\xbe\x58\xa6\x2e\xe6\x8b\x9d\xeb\x15\x5e\x94\x18\x4f\x99\xbc\xf8\x0d\x3f\xf2\x42\x30\xeb\x6f\xf4\x96\x2f\x9e\xfd\xdf\x00\x20\x8a\x1e\xe5\x2d\x37\x00\x00")
 
 func indexHtmlBytes() ([]byte, error) {
 	return bindataRead(
@@ -83,7 +83,7 @@ func indexHtml() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "index.html", size: 12857, mode: os.FileMode(436), modTime: time.Unix(1, 0)}
+	info := bindataFileInfo{name: "index.html", size: 14125, mode: os.FileMode(436), modTime: time.Unix(1, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
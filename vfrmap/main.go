@@ -5,6 +5,7 @@ package main
 // build: GOOS=windows GOARCH=amd64 go build -o vfrmap.exe github.com/lian/msfs2020-go/vfrmap
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -12,11 +13,13 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
-	"unsafe"
 
+	"github.com/supersidor/msfs2020-go/logging"
 	"github.com/supersidor/msfs2020-go/simconnect"
+	"github.com/supersidor/msfs2020-go/simconnect/facility"
 	"github.com/supersidor/msfs2020-go/vfrmap/html/leafletjs"
 	"github.com/supersidor/msfs2020-go/vfrmap/websockets"
 )
@@ -24,22 +27,24 @@ import (
 type Report struct {
 	simconnect.RecvSimobjectDataByType
 	Title         [256]byte `name:"TITLE"`
-	Altitude      float64   `name:"INDICATED ALTITUDE" unit:"feet"` // PLANE ALTITUDE or PLANE ALT ABOVE GROUND
-	Latitude      float64   //`name:"PLANE LATITUDE" unit:"degrees"`
-	Longitude     float64   //`name:"PLANE LONGITUDE" unit:"degrees"`
-	Heading       float64   `name:"PLANE HEADING DEGREES TRUE" unit:"degrees"`
-	Airspeed      float64   `name:"AIRSPEED INDICATED" unit:"knot"`
-	AirspeedTrue  float64   `name:"AIRSPEED TRUE" unit:"knot"`
-	VerticalSpeed float64   `name:"VERTICAL SPEED" unit:"ft/min"`
-	Flaps         float64   `name:"TRAILING EDGE FLAPS LEFT ANGLE" unit:"degrees"`
-	Trim          float64   `name:"ELEVATOR TRIM PCT" unit:"percent"`
-	RudderTrim    float64   `name:"RUDDER TRIM PCT" unit:"percent"`
+	Altitude      float64   `name:"INDICATED ALTITUDE" unit:"feet" epsilon:"0.5"` // PLANE ALTITUDE or PLANE ALT ABOVE GROUND
+	Latitude      float64   `name:"PLANE LATITUDE" unit:"degrees"`
+	Longitude     float64   `name:"PLANE LONGITUDE" unit:"degrees"`
+	Heading       float64   `name:"PLANE HEADING DEGREES TRUE" unit:"degrees" epsilon:"0.5"`
+	Airspeed      float64   `name:"AIRSPEED INDICATED" unit:"knot" epsilon:"0.5"`
+	AirspeedTrue  float64   `name:"AIRSPEED TRUE" unit:"knot" epsilon:"0.5"`
+	VerticalSpeed float64   `name:"VERTICAL SPEED" unit:"ft/min" epsilon:"0.5"`
+	Flaps         float64   `name:"TRAILING EDGE FLAPS LEFT ANGLE" unit:"degrees" epsilon:"0.5"`
+	Trim          float64   `name:"ELEVATOR TRIM PCT" unit:"percent" epsilon:"0.05"`
+	RudderTrim    float64   `name:"RUDDER TRIM PCT" unit:"percent" epsilon:"0.05"`
 }
 
-func (r *Report) RequestData(s *simconnect.SimConnect) {
-	defineID := s.GetDefineID(r)
-	requestID := defineID
-	s.RequestDataOnSimObjectType(requestID, defineID, 0, simconnect.SIMOBJECT_TYPE_USER)
+// reportOptions subscribe to the fields that changed, about every 6th frame.
+// a slow websocket only gets the latest report.
+var reportOptions = &simconnect.SubscribeOptions{
+	Flags:    simconnect.DATA_REQUEST_FLAG_CHANGED | simconnect.DATA_REQUEST_FLAG_TAGGED,
+	Interval: 5,
+	Overflow: simconnect.OverflowLatest,
 }
 
 type TrafficReport struct {
@@ -52,9 +57,8 @@ type TrafficReport struct {
 	Heading         float64  `name:"PLANE HEADING DEGREES TRUE" unit:"degrees"`
 }
 
-func (r *TrafficReport) RequestData(s *simconnect.SimConnect) {
+func (r *TrafficReport) RequestData(s simconnect.Client, requestID simconnect.DWORD) {
 	defineID := s.GetDefineID(r)
-	requestID := defineID
 	s.RequestDataOnSimObjectType(requestID, defineID, 0, simconnect.SIMOBJECT_TYPE_AIRCRAFT)
 }
 
@@ -70,56 +74,88 @@ func (r *TrafficReport) Inspect() string {
 }
 
 type TeleportRequest struct {
-	simconnect.RecvSimobjectDataByType
-	Latitude  float64 `name:"PLANE LATITUDE" unit:"degrees"`
-	Longitude float64 `name:"PLANE LONGITUDE" unit:"degrees"`
-	Altitude  float64 `name:"PLANE ALTITUDE" unit:"feet"`
+	Position simconnect.DataInitPosition `name:"Initial Position" unit:"NULL"`
 }
 
-func (r *TeleportRequest) SetData(s *simconnect.SimConnect) {
-	defineID := s.GetDefineID(r)
-
-	buf := [3]float64{
-		r.Latitude,
-		r.Longitude,
-		r.Altitude,
-	}
-
-	size := simconnect.DWORD(3 * 8) // 2 * 8 bytes
-	s.SetDataOnSimObject(defineID, simconnect.OBJECT_ID_USER, 0, 0, size, unsafe.Pointer(&buf[0]))
+func (r *TeleportRequest) SetData(s simconnect.Client) error {
+	return s.SetData(simconnect.OBJECT_ID_USER, r)
 }
 
 var buildVersion string
 var buildTime string
 var disableTeleport bool
 
-var verbose bool
+var logLevels string
+var logFile string
 var httpListen string
+var simconnectAddress string
+var bookmarkKey string
+var autosave time.Duration
+var recordFile string
+var replayFile string
+
+// log is the vfrmap subsystem, the simconnect, websockets and report (every
+// plane report at debug) subsystems log through their own.
+var log *logging.Logger
 
 func main() {
-	flag.BoolVar(&verbose, "verbose", false, "verbose output")
+	flag.StringVar(&logLevels, "log", "info", "log levels, a default and subsystem=level pairs, e.g. info,report=debug,websockets=warn")
+	flag.StringVar(&logFile, "log-file", "", "also write the log to this file as json lines")
 	flag.StringVar(&httpListen, "listen", "0.0.0.0:9000", "http listen")
 	flag.BoolVar(&disableTeleport, "disable-teleport", false, "disable teleport")
+	flag.StringVar(&simconnectAddress, "simconnect", "", "simconnect server address (host:port), uses SimConnect.dll when empty")
+	flag.StringVar(&bookmarkKey, "bookmark-key", "Shift+Ctrl+B", "simulator key that drops a bookmark on the map, empty disables it")
+	flag.DurationVar(&autosave, "autosave", 0, "save the flight to vfrmap-autosave.FLT next to vfrmap this often, e.g. 5m, 0 disables it")
+	flag.StringVar(&recordFile, "record", "", "record the simconnect messages to this file")
+	flag.StringVar(&replayFile, "replay", "", "play back a file written with -record instead of connecting to the simulator")
 	flag.Parse()
 
-	fmt.Printf("\nmsfs2020-go/vfrmap\n  readme: https://github.com/lian/msfs2020-go/blob/master/vfrmap/README.md\n  issues: https://github.com/lian/msfs2020-go/issues\n  version: %s (%s)\n\n", buildVersion, buildTime)
+	levels, err := logging.ParseLevels(logLevels)
+	if err != nil {
+		panic(err)
+	}
+	var sink logging.Sink = logging.NewTextSink(os.Stdout)
+	var logOutput *os.File
+	if logFile != "" {
+		if logOutput, err = os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644); err != nil {
+			panic(err)
+		}
+		sink = logging.Multi(sink, logging.NewJSONSink(logOutput))
+	}
+	logger := logging.New(sink, levels)
+	log = logger.Named("vfrmap")
+
+	log.Info("msfs2020-go/vfrmap",
+		logging.F("version", buildVersion),
+		logging.F("build_time", buildTime),
+		logging.F("readme", "https://github.com/lian/msfs2020-go/blob/master/vfrmap/README.md"),
+		logging.F("issues", "https://github.com/lian/msfs2020-go/issues"),
+	)
 
 	exitSignal := make(chan os.Signal, 1)
 	signal.Notify(exitSignal, os.Interrupt, syscall.SIGTERM)
 	exePath, _ := os.Executable()
 
 	ws := websockets.New()
+	ws.Log = logger.Named("websockets")
 
-	s, err := simconnect.New("msfs2020-go/vfrmap")
-	if err != nil {
-		panic(err)
+	sup := simconnect.NewSupervisor("msfs2020-go/vfrmap", simconnectAddress)
+	sup.Log = logger.Named("simconnect")
+	if replayFile != "" {
+		sup.Open = func() (simconnect.Client, error) {
+			return simconnect.OpenReplay(replayFile)
+		}
 	}
-	fmt.Println("connected to flight simulator!")
 
-	report := &Report{}
-	err = s.RegisterDataDefinition(report)
-	if err != nil {
-		panic(err)
+	var s simconnect.Client = sup
+	var recording *os.File
+	if recordFile != "" {
+		if recording, err = os.Create(recordFile); err != nil {
+			panic(err)
+		}
+		if s, err = simconnect.NewRecorder(sup, recording); err != nil {
+			panic(err)
+		}
 	}
 
 	trafficReport := &TrafficReport{}
@@ -134,13 +170,93 @@ func main() {
 		panic(err)
 	}
 
-	eventSimStartID := s.GetEventID()
-	//s.SubscribeToSystemEvent(eventSimStartID, "SimStart")
+	d := simconnect.NewDispatcher(s)
+	d.Log = sup.Log
+
+	d.Error = func(err *simconnect.ExceptionError) {
+		d.Log.Warn("exception", logging.F("exception", simconnect.ExceptionName(err.Exception)), logging.F("send_id", err.SendID), logging.F("error", err))
+	}
+
+	d.Handle(simconnect.RECV_ID_OPEN, func(msg interface{}) {
+		recvOpen := msg.(*simconnect.RecvOpen)
+		d.Log.Info("flight simulator info",
+			logging.F("codename", recvOpen.ApplicationName),
+			logging.F("version", fmt.Sprintf("%d.%d (%d.%d)",
+				recvOpen.ApplicationVersionMajor,
+				recvOpen.ApplicationVersionMinor,
+				recvOpen.ApplicationBuildMajor,
+				recvOpen.ApplicationBuildMinor,
+			)),
+			logging.F("simconnect", fmt.Sprintf("%d.%d (%d.%d)",
+				recvOpen.SimConnectVersionMajor,
+				recvOpen.SimConnectVersionMinor,
+				recvOpen.SimConnectBuildMajor,
+				recvOpen.SimConnectBuildMinor,
+			)),
+		)
+	})
+
 	//s.SubscribeToFacilities(simconnect.FACILITY_LIST_TYPE_AIRPORT, s.GetDefineID(&simconnect.DataFacilityAirport{}))
 	//s.SubscribeToFacilities(simconnect.FACILITY_LIST_TYPE_WAYPOINT, s.GetDefineID(&simconnect.DataFacilityWaypoint{}))
+	_, err = d.HandleSystemEvent(simconnect.SYSTEM_EVENT_SIM_START, func(msg interface{}) {
+		log.Info("sim start")
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = d.HandleSystemEvent(simconnect.SYSTEM_EVENT_AIRCRAFT_LOADED, func(msg interface{}) {
+		if e, ok := msg.(*simconnect.RecvEventFilename); ok {
+			log.Info("aircraft loaded", logging.F("file", e.FileName))
+		}
+	})
+	if err != nil {
+		panic(err)
+	}
 
 	startupTextEventID := s.GetEventID()
-	s.ShowText(simconnect.TEXT_TYPE_PRINT_WHITE, 15, startupTextEventID, "msfs2020-go/vfrmap connected")
+	d.HandleEvent(startupTextEventID, func(msg interface{}) {
+		// ignore
+	})
+
+	sup.StateChange = func(state simconnect.State, err error) {
+		if state == simconnect.StateConnected {
+			s.ShowText(simconnect.TEXT_TYPE_PRINT_WHITE, 15, startupTextEventID, "msfs2020-go/vfrmap connected")
+		}
+		ws.Broadcast(statusPacket(state, err))
+	}
+
+	trafficRequestID := s.GetRequestID()
+	d.HandleRequest(trafficRequestID, func(msg interface{}) {
+		trafficReport := msg.(*simconnect.SimobjectData).Value.(*TrafficReport)
+		log.Info("traffic report", logging.F("request_id", trafficRequestID), logging.F("object_id", trafficReport.ObjectID), logging.F("report", trafficReport.Inspect()))
+	})
+
+	// the report belongs to the main loop, the hotkey only asks for a bookmark
+	bookmark := make(chan struct{}, 1)
+	if bookmarkKey != "" {
+		hotkeys := d.NewInputGroup(simconnect.GROUP_PRIORITY_HIGHEST)
+		err = hotkeys.Bind(simconnect.Input(bookmarkKey), func(msg interface{}) {
+			select {
+			case bookmark <- struct{}{}:
+			default:
+			}
+		})
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sup.Start(ctx)
+	d.Start(ctx)
+
+	reports := make(chan Report, 1)
+	if err := d.Subscribe(ctx, reports, simconnect.OBJECT_ID_USER, simconnect.PERIOD_VISUAL_FRAME, reportOptions); err != nil {
+		panic(err)
+	}
+	var report Report
+	reportLog := logger.Named("report")
 
 	go func() {
 		app := func(w http.ResponseWriter, r *http.Request) {
@@ -155,12 +271,61 @@ func main() {
 			if _, err = os.Stat(filePath); os.IsNotExist(err) {
 				w.Write(MustAsset(filepath.Base(filePath)))
 			} else {
-				fmt.Println("use local", filePath)
+				log.Debug("use local", logging.F("file", filePath))
 				http.ServeFile(w, r, filePath)
 			}
 		}
 
+		airport := func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+
+			icao := strings.ToUpper(r.URL.Query().Get("icao"))
+			if icao == "" {
+				http.Error(w, "icao missing", http.StatusBadRequest)
+				return
+			}
+
+			reqCtx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+			defer cancel()
+
+			var a facility.Airport
+			if err := d.RequestFacilityData(reqCtx, &a, icao, ""); err != nil {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(&a)
+		}
+
+		system := func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+
+			reqCtx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+			defer cancel()
+
+			states := map[string]string{
+				"aircraft":   simconnect.SYSTEM_STATE_AIRCRAFT_LOADED,
+				"flight":     simconnect.SYSTEM_STATE_FLIGHT_LOADED,
+				"flightPlan": simconnect.SYSTEM_STATE_FLIGHT_PLAN,
+			}
+			pkt := map[string]string{}
+			for key, state := range states {
+				st, err := d.RequestSystemState(reqCtx, state)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusServiceUnavailable)
+					return
+				}
+				pkt[key] = st.String
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(pkt)
+		}
+
 		http.HandleFunc("/ws", ws.Serve)
+		http.HandleFunc("/system", system)
+		http.HandleFunc("/airport", airport)
 		http.Handle("/leafletjs/", http.StripPrefix("/leafletjs/", leafletjs.FS{}))
 		http.HandleFunc("/", app)
 		//http.Handle("/", http.FileServer(http.Dir(".")))
@@ -171,161 +336,168 @@ func main() {
 		}
 	}()
 
-	simconnectTick := time.NewTicker(100 * time.Millisecond)
-	planePositionTick := time.NewTicker(200 * time.Millisecond)
 	trafficPositionTick := time.NewTicker(10000 * time.Millisecond)
 
+	dispatchDone := d.Done()
+
+	var autosaveTick <-chan time.Time
+	if autosave > 0 {
+		autosaveTick = time.NewTicker(autosave).C
+	}
+
 	for {
 		select {
-		case <-planePositionTick.C:
-			report.RequestData(s)
+		case report = <-reports:
+			if reportLog.Enabled(logging.LevelDebug) {
+				reportLog.Debug("report",
+					logging.F("title", strings.TrimRight(string(report.Title[:]), "\x00")),
+					logging.F("latitude", report.Latitude),
+					logging.F("longitude", report.Longitude),
+					logging.F("altitude", report.Altitude),
+					logging.F("heading", report.Heading),
+					logging.F("airspeed", report.Airspeed),
+					logging.F("vertical_speed", report.VerticalSpeed),
+				)
+			}
+
+			ws.Broadcast(map[string]interface{}{
+				"type":           "plane",
+				"latitude":       report.Latitude,
+				"longitude":      report.Longitude,
+				"altitude":       fmt.Sprintf("%.0f", report.Altitude),
+				"heading":        int(report.Heading),
+				"airspeed":       fmt.Sprintf("%.0f", report.Airspeed),
+				"airspeed_true":  fmt.Sprintf("%.0f", report.AirspeedTrue),
+				"vertical_speed": fmt.Sprintf("%.0f", report.VerticalSpeed),
+				"flaps":          fmt.Sprintf("%.0f", report.Flaps),
+				"trim":           fmt.Sprintf("%.1f", report.Trim),
+				"rudder_trim":    fmt.Sprintf("%.1f", report.RudderTrim),
+			})
+
+		case <-bookmark:
+			log.Info("bookmark", logging.F("latitude", report.Latitude), logging.F("longitude", report.Longitude))
+			ws.Broadcast(map[string]interface{}{
+				"type":      "bookmark",
+				"latitude":  report.Latitude,
+				"longitude": report.Longitude,
+				"altitude":  fmt.Sprintf("%.0f", report.Altitude),
+			})
 
 		case <-trafficPositionTick.C:
 			//fmt.Println("--------------------------------- REQUEST TRAFFIC --------------")
-			//trafficReport.RequestData(s)
+			//trafficReport.RequestData(s, trafficRequestID)
 			//s.RequestFacilitiesList(simconnect.FACILITY_LIST_TYPE_AIRPORT, airportRequestID)
 			//s.RequestFacilitiesList(simconnect.FACILITY_LIST_TYPE_WAYPOINT, waypointRequestID)
 
-		case <-simconnectTick.C:
-			ppData, r1, err := s.GetNextDispatch()
-
-			if r1 < 0 {
-				if uint32(r1) == simconnect.E_FAIL {
-					// skip error, means no new messages?
-					continue
-				} else {
-					panic(fmt.Errorf("GetNextDispatch error: %d %s", r1, err))
-				}
+		case <-autosaveTick:
+			fileName := filepath.Join(filepath.Dir(exePath), "vfrmap-autosave")
+			if err := s.FlightSave(fileName, "vfrmap autosave", time.Now().Format(time.RFC1123), 0); err != nil {
+				log.Error("autosave failed", logging.F("file", fileName), logging.F("error", err))
 			}
 
-			recvInfo := *(*simconnect.Recv)(ppData)
-
-			switch recvInfo.ID {
-			case simconnect.RECV_ID_EXCEPTION:
-				recvErr := *(*simconnect.RecvException)(ppData)
-				fmt.Printf("SIMCONNECT_RECV_ID_EXCEPTION %#v\n", recvErr)
-
-			case simconnect.RECV_ID_OPEN:
-				recvOpen := *(*simconnect.RecvOpen)(ppData)
-				fmt.Printf(
-					"\nflight simulator info:\n  codename: %s\n  version: %d.%d (%d.%d)\n  simconnect: %d.%d (%d.%d)\n\n",
-					recvOpen.ApplicationName,
-					recvOpen.ApplicationVersionMajor,
-					recvOpen.ApplicationVersionMinor,
-					recvOpen.ApplicationBuildMajor,
-					recvOpen.ApplicationBuildMinor,
-					recvOpen.SimConnectVersionMajor,
-					recvOpen.SimConnectVersionMinor,
-					recvOpen.SimConnectBuildMajor,
-					recvOpen.SimConnectBuildMinor,
-				)
-
-			case simconnect.RECV_ID_EVENT:
-				recvEvent := *(*simconnect.RecvEvent)(ppData)
-
-				switch recvEvent.EventID {
-				case eventSimStartID:
-					fmt.Println("EVENT: SimStart")
-				case startupTextEventID:
-					// ignore
-				default:
-					fmt.Println("unknown SIMCONNECT_RECV_ID_EVENT", recvEvent.EventID)
-				}
-			case simconnect.RECV_ID_WAYPOINT_LIST:
-				waypointList := *(*simconnect.RecvFacilityWaypointList)(ppData)
-				fmt.Printf("SIMCONNECT_RECV_ID_WAYPOINT_LIST %#v\n", waypointList)
-
-			case simconnect.RECV_ID_AIRPORT_LIST:
-				airportList := *(*simconnect.RecvFacilityAirportList)(ppData)
-				fmt.Printf("SIMCONNECT_RECV_ID_AIRPORT_LIST %#v\n", airportList)
-
-			case simconnect.RECV_ID_SIMOBJECT_DATA_BYTYPE:
-				recvData := *(*simconnect.RecvSimobjectDataByType)(ppData)
-
-				switch recvData.RequestID {
-				case s.DefineMap["Report"]:
-					report = (*Report)(ppData)
-
-					if verbose {
-						fmt.Printf("REPORT: %#v\n", report)
-					}
-
-					ws.Broadcast(map[string]interface{}{
-						"type":           "plane",
-						"latitude":       report.Latitude,
-						"longitude":      report.Longitude,
-						"altitude":       fmt.Sprintf("%.0f", report.Altitude),
-						"heading":        int(report.Heading),
-						"airspeed":       fmt.Sprintf("%.0f", report.Airspeed),
-						"airspeed_true":  fmt.Sprintf("%.0f", report.AirspeedTrue),
-						"vertical_speed": fmt.Sprintf("%.0f", report.VerticalSpeed),
-						"flaps":          fmt.Sprintf("%.0f", report.Flaps),
-						"trim":           fmt.Sprintf("%.1f", report.Trim),
-						"rudder_trim":    fmt.Sprintf("%.1f", report.RudderTrim),
-					})
-
-				case s.DefineMap["TrafficReport"]:
-					trafficReport = (*TrafficReport)(ppData)
-					fmt.Printf("TRAFFIC REPORT: %s\n", trafficReport.Inspect())
-				}
-
-			default:
-				fmt.Println("recvInfo.ID unknown", recvInfo.ID)
+		case <-dispatchDone:
+			if err := d.Wait(); err != simconnect.ErrReplayEnded {
+				panic(fmt.Errorf("GetNextDispatch error: %s", err))
 			}
+			// keep serving the map with the last report
+			log.Info("replay ended")
+			dispatchDone = nil
 
 		case <-exitSignal:
-			fmt.Println("exiting..")
+			log.Info("exiting..")
+			cancel()
+			d.Wait()
 			if err = s.Close(); err != nil {
 				panic(err)
 			}
+			if recording != nil {
+				recording.Close()
+			}
+			if logOutput != nil {
+				logOutput.Close()
+			}
 			os.Exit(0)
 
-		case _ = <-ws.NewConnection:
-			// drain and skip
+		case m := <-ws.NewConnection:
+			m.Connection.SendPacket(statusPacket(sup.State(), nil))
 
 		case m := <-ws.ReceiveMessages:
-			handleClientMessage(m, s)
+			handleClientMessage(m, s, report)
 		}
 	}
 }
 
-func handleClientMessage(m websockets.ReceiveMessage, s *simconnect.SimConnect) {
+func statusPacket(state simconnect.State, err error) map[string]interface{} {
+	pkt := map[string]interface{}{
+		"type":      "status",
+		"connected": state == simconnect.StateConnected,
+		"state":     state.String(),
+	}
+	if err != nil {
+		pkt["error"] = err.Error()
+	}
+	return pkt
+}
+
+// handleClientMessage handles a packet of a browser, report is the latest
+// plane report.
+func handleClientMessage(m websockets.ReceiveMessage, s simconnect.Client, report Report) {
+	log := log.With(logging.F("conn_id", m.Connection.ID), logging.F("remote_addr", m.Connection.RemoteAddr))
+
 	var pkt map[string]interface{}
 	if err := json.Unmarshal(m.Message, &pkt); err != nil {
-		fmt.Println("invalid websocket packet", err)
+		log.Warn("invalid websocket packet", logging.F("error", err))
 	} else {
 		pktType, ok := pkt["type"].(string)
 		if !ok {
-			fmt.Println("invalid websocket packet", pkt)
+			log.Warn("invalid websocket packet", logging.F("packet", pkt))
 			return
 		}
 		switch pktType {
 		case "teleport":
 			if disableTeleport {
-				fmt.Println("teleport disabled", pkt)
+				log.Warn("teleport disabled", logging.F("packet", pkt))
 				return
 			}
 
 			// validate user input
 			lat, ok := pkt["lat"].(float64)
 			if !ok {
-				fmt.Println("invalid websocket packet", pkt)
+				log.Warn("invalid websocket packet", logging.F("packet", pkt))
 				return
 			}
 			lng, ok := pkt["lng"].(float64)
 			if !ok {
-				fmt.Println("invalid websocket packet", pkt)
+				log.Warn("invalid websocket packet", logging.F("packet", pkt))
 				return
 			}
 			altitude, ok := pkt["altitude"].(float64)
 			if !ok {
-				fmt.Println("invalid websocket packet", pkt)
+				log.Warn("invalid websocket packet", logging.F("packet", pkt))
 				return
 			}
 
+			// optional, the heading and speed of the last report when missing
+			heading, ok := pkt["heading"].(float64)
+			if !ok {
+				heading = report.Heading
+			}
+			airspeed, ok := pkt["airspeed"].(float64)
+			if !ok {
+				airspeed = report.Airspeed
+			}
+
 			// teleport
-			r := &TeleportRequest{Latitude: lat, Longitude: lng, Altitude: altitude}
-			r.SetData(s)
+			r := &TeleportRequest{Position: simconnect.DataInitPosition{
+				Latitude:  lat,
+				Longitude: lng,
+				Altitude:  altitude,
+				Heading:   heading,
+				Airspeed:  simconnect.DWORD(airspeed),
+			}}
+			if err := r.SetData(s); err != nil {
+				log.Error("teleport failed", logging.F("error", err))
+			}
 		}
 	}
 }
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/supersidor/msfs2020-go/simconnect"
+	"github.com/supersidor/msfs2020-go/simconnect/simtest"
+	"github.com/supersidor/msfs2020-go/vfrmap/websockets"
+)
+
+func TestTeleport(t *testing.T) {
+	srv := simtest.NewServer()
+	defer srv.Close()
+
+	s, err := simconnect.Dial("vfrmap", srv.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if err := s.RegisterDataDefinition(&TeleportRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	report := Report{Heading: 180, Airspeed: 95}
+	for _, test := range []struct {
+		packet string
+		want   simconnect.DataInitPosition
+	}{
+		{
+			`{"type": "teleport", "lat": 46.5, "lng": 7.25, "altitude": 3000, "heading": 90, "airspeed": 120}`,
+			simconnect.DataInitPosition{Latitude: 46.5, Longitude: 7.25, Altitude: 3000, Heading: 90, Airspeed: 120},
+		},
+		{
+			// the heading and airspeed of the last report
+			`{"type": "teleport", "lat": 47, "lng": 8, "altitude": 4000}`,
+			simconnect.DataInitPosition{Latitude: 47, Longitude: 8, Altitude: 4000, Heading: 180, Airspeed: 95},
+		},
+	} {
+		m := websockets.ReceiveMessage{Message: []byte(test.packet), Connection: &websockets.Connection{ID: 1}}
+		handleClientMessage(m, s, report)
+
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			v := srv.SimVar(simtest.UserObjectID, "Initial Position")
+			if v == test.want {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("%s: Initial Position is %+v, want %+v", test.packet, v, test.want)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
@@ -3,10 +3,10 @@ package websockets
 import (
 	"bytes"
 	"encoding/json"
-	"fmt"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/supersidor/msfs2020-go/logging"
 )
 
 const (
@@ -38,10 +38,13 @@ type ReceiveMessage struct {
 }
 
 type Connection struct {
-	socket    *Websocket
-	conn      *websocket.Conn
-	Send      chan []byte
-	SendQueue chan []byte
+	ID         uint64 // numbers the connections of a Websocket from 1
+	RemoteAddr string
+	socket     *Websocket
+	conn       *websocket.Conn
+	log        *logging.Logger
+	Send       chan []byte
+	SendQueue  chan []byte
 }
 
 func (c *Connection) Run() {
@@ -73,9 +76,10 @@ func (c *Connection) readPump() {
 		_, message, err := c.conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway) {
-				//log.Printf("error: %v", err)
+				c.log.Warn("read failed", logging.F("error", err))
+			} else {
+				c.log.Debug("read failed", logging.F("error", err))
 			}
-			//log.Printf("error: %v", err)
 			break
 		}
 		message = bytes.TrimSpace(bytes.Replace(message, newline, space, -1))
@@ -137,13 +141,13 @@ func (c *Connection) writePump() {
 			w.Write(message)
 
 			if err := w.Close(); err != nil {
-				fmt.Println(err)
+				c.log.Debug("write failed", logging.F("error", err))
 				return
 			}
 		case <-ticker.C:
 			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
 			if err := c.conn.WriteMessage(websocket.PingMessage, []byte{}); err != nil {
-				fmt.Println(err)
+				c.log.Debug("ping failed", logging.F("error", err))
 				return
 			}
 		}
@@ -2,12 +2,18 @@ package websockets
 
 import (
 	"encoding/json"
-	"fmt"
-	"log"
 	"net/http"
+	"sync/atomic"
+
+	"github.com/supersidor/msfs2020-go/logging"
 )
 
 type Websocket struct {
+	// Log gets the connections coming and going with their conn_id and
+	// remote_addr. nil logs nothing, set it before serving.
+	Log *logging.Logger
+
+	lastID          uint64
 	connections     map[*Connection]bool
 	broadcast       chan []byte
 	register        chan *Connection
@@ -38,16 +44,19 @@ func (s *Websocket) ConnectionCount() int {
 func (s *Websocket) Serve(w http.ResponseWriter, r *http.Request) {
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
-		log.Println(err)
+		s.Log.Warn("upgrade failed", logging.F("remote_addr", r.RemoteAddr), logging.F("error", err))
 		return
 	}
 
 	c := &Connection{
-		socket:    s,
-		conn:      conn,
-		Send:      make(chan []byte, 256),
-		SendQueue: make(chan []byte),
+		ID:         atomic.AddUint64(&s.lastID, 1),
+		RemoteAddr: r.RemoteAddr,
+		socket:     s,
+		conn:       conn,
+		Send:       make(chan []byte, 256),
+		SendQueue:  make(chan []byte),
 	}
+	c.log = s.Log.With(logging.F("conn_id", c.ID), logging.F("remote_addr", c.RemoteAddr))
 	s.register <- c
 
 	c.Run()
@@ -62,11 +71,11 @@ func (h *Websocket) Run() {
 	for {
 		select {
 		case c := <-h.register:
-			fmt.Println("new browser connection")
+			c.log.Info("new browser connection")
 			h.connections[c] = true
 			h.NewConnection <- ReceiveMessage{Connection: c}
 		case c := <-h.unregister:
-			fmt.Println("remove browser connection")
+			c.log.Info("remove browser connection")
 			if _, ok := h.connections[c]; ok {
 				delete(h.connections, c)
 				close(c.Send)